@@ -0,0 +1,82 @@
+package swar
+
+import "math/bits"
+
+// AnomalyConfig configures an AnomalyDetector
+// Threshold applies to every channel unless Thresholds gives one per channel
+type AnomalyConfig struct {
+	Shift      uint   // EMA smoothing: each sample moves the average by 1/2^Shift of the gap
+	Threshold  byte   // deviation above which a sample is anomalous
+	Thresholds []byte // optional per-channel thresholds, overriding Threshold
+	// OnAnomaly, if set, is called for every anomalous channel with its sample and previous average
+	OnAnomaly func(channel int, sample, average byte)
+}
+
+// AnomalyDetector flags samples that jump away from their channel's moving average
+// Any number of channels is processed 8 at a time with EMA8 and threshold masks
+type AnomalyDetector struct {
+	cfg        AnomalyConfig
+	channels   int
+	emas       []EMA8
+	thresholds []uint64
+	seeded     bool
+	anomalies  []int
+}
+
+// NewAnomalyDetector creates a detector for samples with the given number of channels
+// The first Observe call only seeds the averages and never reports anomalies
+func NewAnomalyDetector(channels int, cfg AnomalyConfig) *AnomalyDetector {
+	lanes := (channels + 7) / 8
+	d := &AnomalyDetector{
+		cfg:        cfg,
+		channels:   channels,
+		emas:       make([]EMA8, lanes),
+		thresholds: make([]uint64, lanes),
+	}
+	limits := make([]byte, 8*lanes)
+	for i := range limits {
+		limits[i] = cfg.Threshold
+		if i < len(cfg.Thresholds) {
+			limits[i] = cfg.Thresholds[i]
+		}
+	}
+	for i := range d.emas {
+		d.emas[i].shift = min(cfg.Shift, 7)
+		d.thresholds[i] = loadLane(limits, 8*i)
+	}
+	return d
+}
+
+// Observe feeds one sample per channel and returns the channels that were anomalous
+// Anomalous channels are re-baselined to their sample; the result is reused by the next call
+func (d *AnomalyDetector) Observe(samples []byte) []int {
+	samples = samples[:d.channels]
+	d.anomalies = d.anomalies[:0]
+	for i := range d.emas {
+		ema := &d.emas[i]
+		sample := loadLane(samples, 8*i)
+		if !d.seeded {
+			ema.Reset(sample)
+			continue
+		}
+		avg := ema.Value()
+		over := HighBitWhereGreater(AbsoluteDifferenceBetweenBytes(sample, avg), d.thresholds[i])
+		if 8*i+8 > d.channels {
+			over &= uint64(1)<<(8*(d.channels-8*i)) - 1
+		}
+		ema.Update(sample)
+		if over == 0 {
+			continue
+		}
+		ema.ResetWhere(sample, over)
+		for m := over; m != 0; m &= m - 1 {
+			k := bits.TrailingZeros64(m) >> 3
+			d.anomalies = append(d.anomalies, 8*i+k)
+			if d.cfg.OnAnomaly != nil {
+				d.cfg.OnAnomaly(8*i+k, byte(sample>>(8*k)), byte(avg>>(8*k)))
+			}
+		}
+	}
+	d.seeded = true
+	return d.anomalies
+}
@@ -0,0 +1,60 @@
+package swar
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestAnomalyDetector runs 13 channels, so the last lane is partly padding, through a
+// drifting signal with injected spikes. Reported channels and callback arguments must
+// match a scalar model built from emaReference with the same re-baselining rule.
+func TestAnomalyDetector(t *testing.T) {
+	const channels = 13
+	thresholds := []byte{4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 40}
+	var called [][3]int
+	d := NewAnomalyDetector(channels, AnomalyConfig{
+		Shift:      2,
+		Thresholds: thresholds,
+		OnAnomaly: func(channel int, sample, average byte) {
+			called = append(called, [3]int{channel, int(sample), int(average)})
+		},
+	})
+	refs := make([]emaReference, channels)
+	total := 0
+	for step := range 500 {
+		samples := make([]byte, channels)
+		for c := range samples {
+			samples[c] = byte(100 + step/10 + c)
+			if (step+3*c)%37 == 0 {
+				samples[c] += byte(30 + c) // spike
+			}
+		}
+		var want [][3]int
+		for c, s := range samples {
+			if step == 0 {
+				refs[c] = emaReference{int(s) << 7, 2}
+				continue
+			}
+			avg := byte((refs[c].avg + 0x40) >> 7)
+			refs[c].update(s)
+			if int(max(s, avg)-min(s, avg)) > int(thresholds[c]) {
+				want = append(want, [3]int{c, int(s), int(avg)})
+				refs[c].avg = int(s) << 7
+			}
+		}
+		called = called[:0]
+		got := d.Observe(samples)
+		total += len(got)
+		if !slices.Equal(called, want) {
+			t.Fatalf("step %d: OnAnomaly calls = %v; want %v", step, called, want)
+		}
+		for i, c := range got {
+			if c != want[i][0] {
+				t.Fatalf("step %d: Observe = %v; want channels of %v", step, got, want)
+			}
+		}
+	}
+	if total == 0 {
+		t.Errorf("Observe reported no anomalies; spikes were injected")
+	}
+}
@@ -0,0 +1,75 @@
+package swar
+
+// MixAudio8 mixes two unsigned 8-bit PCM streams into dst with clipping and returns the samples written
+// Silence is 128, so each output is a+b-128 clamped to 0..255; dst must hold the shorter input
+func MixAudio8(dst, a, b []byte) int {
+	bias := Dupe(0x80)
+	return transformLanes2(dst, a, b, func(x, y uint64) uint64 {
+		// Samples of b above silence push x up, samples below pull it down
+		up := AddBytesWithMaximum(x, y&^HighBits)
+		down := SubtractBytesWithMinimum(x, SubtractBytesWithWrapping(bias, y))
+		return SelectByHighBit(up, down, y)
+	})
+}
+
+// ScaleAudio8 scales unsigned 8-bit PCM samples by volume/255 around the silence level
+// volume 255 leaves src unchanged and 0 writes silence; dst must hold len(src) bytes
+func ScaleAudio8(dst, src []byte, volume uint8) {
+	bias := Dupe(0x80)
+	transformLanes(dst, src, func(v uint64) uint64 {
+		above := SubtractBytesWithMinimum(v, bias)
+		below := SubtractBytesWithMinimum(bias, v)
+		return bias + ScaleBytes(above, volume) - ScaleBytes(below, volume)
+	})
+}
+
+// MuLawToLinear8 decodes G.711 µ-law bytes into unsigned 8-bit PCM centred on 128
+// The 14-bit magnitude is truncated to its top bits; dst must hold len(src) bytes
+func MuLawToLinear8(dst, src []byte) {
+	transformLanes(dst, src, func(v uint64) uint64 {
+		return muLawDecodeSlots(^v&0x00FF_00FF_00FF_00FF) | muLawDecodeSlots(^v>>8&0x00FF_00FF_00FF_00FF)<<8
+	})
+}
+
+// LinearToMuLaw8 encodes unsigned 8-bit PCM centred on 128 into G.711 µ-law bytes
+// Matches the reference encoder applied to each sample scaled up to 16 bits
+func LinearToMuLaw8(dst, src []byte) {
+	transformLanes(dst, src, func(v uint64) uint64 {
+		return muLawEncodeSlots(v&0x00FF_00FF_00FF_00FF) | muLawEncodeSlots(v>>8&0x00FF_00FF_00FF_00FF)<<8
+	})
+}
+
+// muLawDecodeSlots expands inverted µ-law codes held one per 16-bit slot
+// The per-sample exponent shift is applied as three conditional shifts by 1, 2 and 4
+func muLawDecodeSlots(u uint64) uint64 {
+	exp := u >> 4 & (7 * slots16)
+	t := (u&(0xF*slots16))<<3 + 0x84*slots16
+	for k := range 3 {
+		sel := exp >> k & slots16 * 0xFFFF
+		t = t<<(1<<k)&sel | t&^sel
+	}
+	mag := (t - 0x84*slots16) >> 8 & (0xFF * slots16)
+	neg := u >> 7 & slots16 * 0xFFFF
+	return (0x80*slots16+mag)&^neg | (0x80*slots16-mag)&neg
+}
+
+// muLawEncodeSlots compresses unsigned samples held one per 16-bit slot into µ-law codes
+// The exponent counts how many of the thresholds 1, 2, 4, ..., 64 the magnitude reaches
+func muLawEncodeSlots(x uint64) uint64 {
+	// Setting bit 15 first keeps every subtraction inside its own slot
+	neg := ^(x + (0x8000-0x80)*slots16) >> 15 & slots16
+	sel := neg * 0xFFFF
+	mag := ((0x8080*slots16-x)&sel | (x|0x8000*slots16-0x80*slots16)&^sel) & (0xFF * slots16)
+	mag -= mag >> 7 & slots16 // the reference encoder clips 128 to 127
+	var exp uint64
+	for t := uint64(1); t < 128; t <<= 1 {
+		exp += (mag + (0x8000-t)*slots16) >> 15 & slots16
+	}
+	mant := mag<<5 + 0x10*slots16
+	for k := range 3 {
+		// Bits shifted in from the slot above stay above the 4 mantissa bits
+		sel := exp >> k & slots16 * 0xFFFF
+		mant = mant>>(1<<k)&sel | mant&^sel
+	}
+	return ^(neg<<7 | exp<<4 | mant&(0xF*slots16)) & (0xFF * slots16)
+}
@@ -0,0 +1,107 @@
+package swar
+
+import (
+	"testing"
+)
+
+// TestMixAudio8 checks every pair of sample values against clamped integer mixing, in both
+// argument orders since the SWAR form treats its inputs asymmetrically.
+func TestMixAudio8(t *testing.T) {
+	a := make([]byte, 256*256)
+	b := make([]byte, 256*256)
+	for i := range a {
+		a[i], b[i] = byte(i), byte(i>>8)
+	}
+	run := func(a, b []byte) {
+		dst := make([]byte, len(a)+3)
+		if n := MixAudio8(dst, a, b[:len(b)-3]); n != len(b)-3 {
+			t.Errorf("MixAudio8 wrote %d samples; want %d", n, len(b)-3)
+		}
+		for i := 0; i < len(b)-3; i++ {
+			want := byte(min(max(int(a[i])+int(b[i])-128, 0), 255))
+			if dst[i] != want {
+				t.Errorf("MixAudio8(%d, %d) = %d; want %d", a[i], b[i], dst[i], want)
+			}
+		}
+		if dst[len(b)-3] != 0 {
+			t.Errorf("MixAudio8 wrote past the shorter input")
+		}
+	}
+
+	run(a, b)
+	run(b, a)
+}
+
+// TestScaleAudio8 checks every sample at several volumes against rounded scaling of the
+// signed amplitude, so silence stays at 128 and full volume is the identity.
+func TestScaleAudio8(t *testing.T) {
+	src := make([]byte, 256+5)
+	for i := range src {
+		src[i] = byte(i)
+	}
+	for _, volume := range []int{0, 1, 64, 127, 128, 200, 254, 255} {
+		dst := make([]byte, len(src))
+		ScaleAudio8(dst, src, uint8(volume))
+		for i, c := range src {
+			amp := int(c) - 128
+			scaled := (max(amp, -amp)*volume + 127) / 255
+			if amp < 0 {
+				scaled = -scaled
+			}
+			if want := byte(128 + scaled); dst[i] != want {
+				t.Errorf("ScaleAudio8(%d, %d) = %d; want %d", c, volume, dst[i], want)
+			}
+		}
+	}
+}
+
+// muLawEncodeReference is the G.711 reference encoder for a 16-bit sample.
+func muLawEncodeReference(sample int) byte {
+	sign := 0
+	if sample < 0 {
+		sign, sample = 0x80, -sample
+	}
+	sample = min(sample, 32635) + 0x84
+	exp := 7
+	for mask := 0x4000; sample&mask == 0 && exp > 0; mask >>= 1 {
+		exp--
+	}
+	mant := sample >> (exp + 3) & 0x0F
+	return ^byte(sign | exp<<4 | mant)
+}
+
+// muLawDecodeReference is the G.711 reference decoder producing a 16-bit sample.
+func muLawDecodeReference(code byte) int {
+	u := ^code
+	t := (int(u&0x0F)<<3 + 0x84) << (u >> 4 & 7)
+	if u&0x80 != 0 {
+		return 0x84 - t
+	}
+	return t - 0x84
+}
+
+// TestMuLaw checks every code and every 8-bit sample against the G.711 reference
+// routines, so frames converted here interoperate with any telephony stack.
+func TestMuLaw(t *testing.T) {
+	all := make([]byte, 256+3)
+	for i := range all {
+		all[i] = byte(i)
+	}
+	dst := make([]byte, len(all))
+
+	MuLawToLinear8(dst, all)
+	for i, code := range all {
+		sample := muLawDecodeReference(code)
+		// Integer division truncates toward zero, matching the symmetric magnitude cut
+		if want := byte(128 + sample/256); dst[i] != want {
+			t.Errorf("MuLawToLinear8(0x%02x) = %d; want %d", code, dst[i], want)
+		}
+	}
+
+	LinearToMuLaw8(dst, all)
+	for i, c := range all {
+		if want := muLawEncodeReference((int(c) - 128) * 256); dst[i] != want {
+			t.Errorf("LinearToMuLaw8(%d) = 0x%02x; want 0x%02x", c, dst[i], want)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package swar
+
+// base64Alphabet matches the standard base64 alphabet (RFC 4648 section 4)
+var base64Alphabet = NewRangeMatcher([2]byte{'A', 'Z'}, [2]byte{'a', 'z'}, [2]byte{'0', '9'}, [2]byte{'+', '+'}, [2]byte{'/', '/'})
+
+// ValidateBase64 reports whether b is standard base64 that a decoder would accept
+// With padding the length must be a multiple of 4 and end in at most two '='
+func ValidateBase64(b []byte, padding bool) bool {
+	if padding {
+		if len(b)%4 != 0 {
+			return false
+		}
+		for i := 0; i < 2 && len(b) > 0 && b[len(b)-1] == '='; i++ {
+			b = b[:len(b)-1]
+		}
+	}
+	if len(b)%4 == 1 {
+		return false
+	}
+	return indexMasked(b, notBase64Mask) < 0
+}
+
+// StripBase64Whitespace copies src to dst without ASCII whitespace and returns its length
+// Lanes without whitespace are copied whole; dst must hold len(src) bytes and may equal src
+func StripBase64Whitespace(dst, src []byte) int {
+	return Compress(dst, src, spaceMask)
+}
+
+// notBase64Mask sets the high bit of every byte outside the base64 alphabet
+func notBase64Mask(v uint64) uint64 {
+	return base64Alphabet.Mask(v) ^ HighBits
+}
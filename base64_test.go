@@ -0,0 +1,59 @@
+package swar
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+// TestValidateBase64 verifies that validation agrees with encoding/base64 for padded and
+// unpadded input. Every byte value is substituted into each position so that the
+// alphabet ranges and the padding rules are checked exhaustively.
+func TestValidateBase64(t *testing.T) {
+	check := func(b []byte) {
+		_, err := base64.StdEncoding.DecodeString(string(b))
+		if got, want := ValidateBase64(b, true), err == nil; got != want {
+			t.Errorf("ValidateBase64(%q, true) = %v; want %v", b, got, want)
+		}
+		_, err = base64.RawStdEncoding.DecodeString(string(b))
+		if got, want := ValidateBase64(b, false), err == nil; got != want {
+			t.Errorf("ValidateBase64(%q, false) = %v; want %v", b, got, want)
+		}
+	}
+
+	for _, s := range []string{"", "QQ==", "QUI=", "QUJD", "QUJDRA==", "Q", "QQ", "QUJ", "Q===", "=QQQ", "QQ=A", "SGVsbG8sIFdvcmxkIQ=="} {
+		check([]byte(s))
+	}
+	base := []byte("SGVsbG8sIFdvcmxkIQ==")
+	for i := range base {
+		for c := 0; c < 256; c++ {
+			if c == '\r' || c == '\n' {
+				continue // encoding/base64 skips newlines while decoding
+			}
+			b := append([]byte(nil), base...)
+			b[i] = byte(c)
+			check(b)
+		}
+	}
+}
+
+// TestStripBase64Whitespace verifies that whitespace is removed wherever it falls,
+// including whole lanes of it and runs straddling lanes, and that stripping in place
+// produces the same result as stripping into a separate buffer.
+func TestStripBase64Whitespace(t *testing.T) {
+	run := func(src, want string) {
+		dst := make([]byte, len(src))
+		if n := StripBase64Whitespace(dst, []byte(src)); string(dst[:n]) != want {
+			t.Errorf("StripBase64Whitespace(%q) = %q; want %q", src, dst[:n], want)
+		}
+		inPlace := []byte(src)
+		if n := StripBase64Whitespace(inPlace, inPlace); string(inPlace[:n]) != want {
+			t.Errorf("StripBase64Whitespace(%q) in place = %q; want %q", src, inPlace[:n], want)
+		}
+	}
+
+	run("", "")
+	run("SGVsbG8s", "SGVsbG8s")
+	run("SGVsbG8sIFdv\r\ncmxkIQ==\n", "SGVsbG8sIFdvcmxkIQ==")
+	run("        \t\t\t\t\t\t\t\tQUJD", "QUJD")
+	run(" Q U J D R A = = ", "QUJDRA==")
+}
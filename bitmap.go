@@ -0,0 +1,176 @@
+package swar
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// MatchBitmap returns a bitset with bit i%64 of word i/64 set where pred flags b[i]
+// pred sets the high bit of matching bytes, like the HighBitWhere* functions
+func MatchBitmap(b []byte, pred func(uint64) uint64) []uint64 {
+	bitmap := make([]uint64, (len(b)+63)/64)
+	matchBitmapInto(bitmap, b, pred)
+	return bitmap
+}
+
+// matchBitmapInto packs the predicate results of 8 lanes into each bitmap word
+// Bits past the end of b are left clear even when pred matches the padding
+func matchBitmapInto(bitmap []uint64, b []byte, pred func(uint64) uint64) {
+	var lanes [8]uint64
+	for w := range bitmap[:(len(b)+63)/64] {
+		for k := range lanes {
+			lanes[k] = pred(loadLane(b, 64*w+8*k)) >> 7
+		}
+		word := ExtractLowBits8(lanes)
+		if rest := len(b) - 64*w; rest < 64 {
+			word &= uint64(1)<<rest - 1
+		}
+		bitmap[w] = word
+	}
+}
+
+// Rank counts the set bits of bitmap at positions before i
+// Turns a match position into its ordinal, e.g. which field a delimiter ends
+func Rank(bitmap []uint64, i int) int {
+	count := 0
+	for _, word := range bitmap[:i/64] {
+		count += bits.OnesCount64(word)
+	}
+	if i%64 != 0 {
+		count += bits.OnesCount64(bitmap[i/64] & (uint64(1)<<(i%64) - 1))
+	}
+	return count
+}
+
+// Select returns the position of the set bit with zero-based rank n, or -1 if there is none
+// Inverse of Rank; jumps straight to the n-th match without visiting the others
+func Select(bitmap []uint64, n int) int {
+	if n < 0 {
+		return -1
+	}
+	for w, word := range bitmap {
+		count := bits.OnesCount64(word)
+		if n >= count {
+			n -= count
+			continue
+		}
+		pos := 64 * w
+		for count = bits.OnesCount8(uint8(word)); n >= count; count = bits.OnesCount8(uint8(word)) {
+			n -= count
+			word >>= 8
+			pos += 8
+		}
+		for ; n > 0; n-- {
+			word &= word - 1
+		}
+		return pos + bits.TrailingZeros64(word)
+	}
+	return -1
+}
+
+// SetBits yields the position of every set bit in bitmap in ascending order
+// Walks MatchBitmap results without testing each bit individually
+func SetBits(bitmap []uint64) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for w, word := range bitmap {
+			for word != 0 {
+				if !yield(64*w + bits.TrailingZeros64(word)) {
+					return
+				}
+				word &= word - 1
+			}
+		}
+	}
+}
+
+// AndBitmaps stores a AND b into dst over their common length and returns the words written
+// Intersects predicate bitmaps, e.g. digits that are also inside a quoted region
+func AndBitmaps(dst, a, b []uint64) int {
+	n := min(len(dst), len(a), len(b))
+	dst, a, b = dst[:n], a[:n], b[:n]
+	a, b = unaliased(dst, a, true), unaliased(dst, b, true)
+	i := 0
+	for ; i+4 <= n; i += 4 {
+		dst[i] = a[i] & b[i]
+		dst[i+1] = a[i+1] & b[i+1]
+		dst[i+2] = a[i+2] & b[i+2]
+		dst[i+3] = a[i+3] & b[i+3]
+	}
+	for ; i < n; i++ {
+		dst[i] = a[i] & b[i]
+	}
+	return n
+}
+
+// OrBitmaps stores a OR b into dst over their common length and returns the words written
+// Unions predicate bitmaps when a byte may match any of several classes
+func OrBitmaps(dst, a, b []uint64) int {
+	n := min(len(dst), len(a), len(b))
+	dst, a, b = dst[:n], a[:n], b[:n]
+	a, b = unaliased(dst, a, true), unaliased(dst, b, true)
+	i := 0
+	for ; i+4 <= n; i += 4 {
+		dst[i] = a[i] | b[i]
+		dst[i+1] = a[i+1] | b[i+1]
+		dst[i+2] = a[i+2] | b[i+2]
+		dst[i+3] = a[i+3] | b[i+3]
+	}
+	for ; i < n; i++ {
+		dst[i] = a[i] | b[i]
+	}
+	return n
+}
+
+// AndNotBitmaps stores a AND NOT b into dst over their common length and returns the words written
+// Removes excluded positions, e.g. delimiters that fall inside quotes
+func AndNotBitmaps(dst, a, b []uint64) int {
+	n := min(len(dst), len(a), len(b))
+	dst, a, b = dst[:n], a[:n], b[:n]
+	a, b = unaliased(dst, a, true), unaliased(dst, b, true)
+	i := 0
+	for ; i+4 <= n; i += 4 {
+		dst[i] = a[i] &^ b[i]
+		dst[i+1] = a[i+1] &^ b[i+1]
+		dst[i+2] = a[i+2] &^ b[i+2]
+		dst[i+3] = a[i+3] &^ b[i+3]
+	}
+	for ; i < n; i++ {
+		dst[i] = a[i] &^ b[i]
+	}
+	return n
+}
+
+// PopcountBitmap counts the set bits across the whole bitmap
+// Number of matches in a combined bitmap without iterating them
+func PopcountBitmap(bitmap []uint64) int {
+	var c0, c1, c2, c3 int
+	i := 0
+	for ; i+4 <= len(bitmap); i += 4 {
+		c0 += bits.OnesCount64(bitmap[i])
+		c1 += bits.OnesCount64(bitmap[i+1])
+		c2 += bits.OnesCount64(bitmap[i+2])
+		c3 += bits.OnesCount64(bitmap[i+3])
+	}
+	for ; i < len(bitmap); i++ {
+		c0 += bits.OnesCount64(bitmap[i])
+	}
+	return c0 + c1 + c2 + c3
+}
+
+// BitIndices yields the position of each set bit of mask in ascending order
+// Trailing-zero stripping visits only the set bits, so sparse ExtractLowBits results are cheap
+func BitIndices(mask byte) iter.Seq[int] {
+	return BitIndices64(uint64(mask))
+}
+
+// BitIndices64 yields the position of each set bit of mask in ascending order
+// For HighBitWhere* masks, position>>3 is the matching byte's index in the lane
+func BitIndices64(mask uint64) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for m := mask; m != 0; m &= m - 1 {
+			if !yield(bits.TrailingZeros64(m)) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package swar
+
+import (
+	"math/bits"
+	"slices"
+	"testing"
+)
+
+// TestMatchBitmap verifies bitmap packing against a scalar predicate for lengths around
+// word and lane boundaries. A predicate matching zero bytes checks that the padding of
+// the final lane never leaks into the bitmap.
+func TestMatchBitmap(t *testing.T) {
+	src := make([]byte, 200)
+	for i := range src {
+		src[i] = byte(i * 7 % 13)
+	}
+	zeros := func(v uint64) uint64 { return HighBitWhereEqual(v, 0) }
+	for n := 0; n <= len(src); n++ {
+		got := MatchBitmap(src[:n], zeros)
+		if len(got) != (n+63)/64 {
+			t.Fatalf("MatchBitmap(len %d) returned %d words; want %d", n, len(got), (n+63)/64)
+		}
+		want := make([]uint64, len(got))
+		for i, c := range src[:n] {
+			if c == 0 {
+				want[i/64] |= 1 << (i % 64)
+			}
+		}
+		for w := range want {
+			if got[w] != want[w] {
+				t.Errorf("MatchBitmap(len %d) word %d = 0x%016x; want 0x%016x", n, w, got[w], want[w])
+			}
+		}
+	}
+}
+
+// TestRankSelect checks Rank and Select against a list of set positions built bit by bit.
+// Select must invert Rank for every set bit and report -1 once n runs past the last one.
+func TestRankSelect(t *testing.T) {
+	run := func(bitmap []uint64) {
+		var positions []int
+		for i := 0; i < 64*len(bitmap); i++ {
+			if bitmap[i/64]>>(i%64)&1 == 1 {
+				positions = append(positions, i)
+			}
+		}
+		for i := 0; i <= 64*len(bitmap); i++ {
+			want := 0
+			for _, p := range positions {
+				if p < i {
+					want++
+				}
+			}
+			if got := Rank(bitmap, i); got != want {
+				t.Errorf("Rank(%x, %d) = %d; want %d", bitmap, i, got, want)
+			}
+		}
+		for n, want := range positions {
+			if got := Select(bitmap, n); got != want {
+				t.Errorf("Select(%x, %d) = %d; want %d", bitmap, n, got, want)
+			}
+		}
+		if got := Select(bitmap, len(positions)); got != -1 {
+			t.Errorf("Select(%x, %d) = %d; want -1", bitmap, len(positions), got)
+		}
+		var got []int
+		for p := range SetBits(bitmap) {
+			got = append(got, p)
+		}
+		if !slices.Equal(got, positions) {
+			t.Errorf("SetBits(%x) = %v; want %v", bitmap, got, positions)
+		}
+	}
+
+	run(nil)
+	run([]uint64{0})
+	run([]uint64{1 << 63, 1})
+	run([]uint64{0, 0xFFFF_FFFF_FFFF_FFFF, 0})
+	for n := uint64(0); n < 0xFF_FF_FF_FF_FF; n = (n*12 + 13) / 11 {
+		run([]uint64{n, n * 0x9E37_79B9_7F4A_7C15})
+	}
+}
+
+// TestBitmapOps compares the unrolled boolean operations with per-word operators across
+// lengths that exercise both the unrolled body and the remainder loop, including a short dst.
+func TestBitmapOps(t *testing.T) {
+	a := make([]uint64, 11)
+	b := make([]uint64, 11)
+	for i := range a {
+		a[i] = uint64(i+1) * 0x9E37_79B9_7F4A_7C15
+		b[i] = uint64(i+3) * 0xBF58_476D_1CE4_E5B9
+	}
+	ops := []struct {
+		name string
+		f    func(dst, a, b []uint64) int
+		ref  func(x, y uint64) uint64
+	}{
+		{"AndBitmaps", AndBitmaps, func(x, y uint64) uint64 { return x & y }},
+		{"OrBitmaps", OrBitmaps, func(x, y uint64) uint64 { return x | y }},
+		{"AndNotBitmaps", AndNotBitmaps, func(x, y uint64) uint64 { return x &^ y }},
+	}
+	for n := 0; n <= len(a); n++ {
+		for _, op := range ops {
+			dst := make([]uint64, len(a))
+			if got := op.f(dst[:n], a, b); got != n {
+				t.Errorf("%s(len %d) = %d; want %d", op.name, n, got, n)
+			}
+			for i := range dst {
+				want := uint64(0)
+				if i < n {
+					want = op.ref(a[i], b[i])
+				}
+				if dst[i] != want {
+					t.Errorf("%s(len %d) word %d = 0x%016x; want 0x%016x", op.name, n, i, dst[i], want)
+				}
+			}
+		}
+		want := 0
+		for _, w := range a[:n] {
+			want += bits.OnesCount64(w)
+		}
+		if got := PopcountBitmap(a[:n]); got != want {
+			t.Errorf("PopcountBitmap(len %d) = %d; want %d", n, got, want)
+		}
+	}
+}
+
+// TestBitIndices compares both iterators with a bit loop, ranging over each Seq twice since
+// a Seq may be reused, and checks that stopping early is honoured, since callers often
+// break at the first interesting match.
+func TestBitIndices(t *testing.T) {
+	run := func(mask uint64) {
+		var want []int
+		for i := range 64 {
+			if mask>>i&1 == 1 {
+				want = append(want, i)
+			}
+		}
+		seq := BitIndices64(mask)
+		for pass := range 2 {
+			if got := slices.Collect(seq); !slices.Equal(got, want) {
+				t.Errorf("BitIndices64(0x%016x) pass %d = %v; want %v", mask, pass, got, want)
+			}
+		}
+		if mask < 256 {
+			seq := BitIndices(byte(mask))
+			for pass := range 2 {
+				if got := slices.Collect(seq); !slices.Equal(got, want) {
+					t.Errorf("BitIndices(0x%02x) pass %d = %v; want %v", mask, pass, got, want)
+				}
+			}
+		}
+		for i := range BitIndices64(mask) {
+			if i != want[0] {
+				t.Errorf("BitIndices64(0x%016x) first = %d; want %d", mask, i, want[0])
+			}
+			break
+		}
+	}
+
+	for m := range uint64(256) {
+		run(m)
+	}
+	run(HighBits)
+	run(1 << 63)
+	for n := uint64(0); n < 0xFF_FF_FF_FF_FF; n = (n*12 + 13) / 11 {
+		run(n * 0x9E37_79B9_7F4A_7C15)
+	}
+}
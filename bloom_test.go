@@ -0,0 +1,54 @@
+package swar
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestBitPerByte checks every index in every byte position, since a probe mask with a
+// missing or extra bit would silently change the false positive rate.
+func TestBitPerByte(t *testing.T) {
+	for k := range 8 {
+		for i := range 8 {
+			idx := Dupe(byte(7-k))&^(0xFF<<(8*i)) | uint64(k)<<(8*i)
+			want := Dupe(1<<(7-k))&^(0xFF<<(8*i)) | uint64(1)<<(k+8*i)
+			if got := bitPerByte(idx); got != want {
+				t.Errorf("bitPerByte(0x%016x) = 0x%016x; want 0x%016x", idx, got, want)
+			}
+		}
+	}
+}
+
+// TestBlockedBloomFilter fills filters to the load NewBlockedBloomFilter was sized for and
+// checks there are no false negatives, then measures the false positive rate on 100000 keys
+// never added and requires it to be within a factor of 1.5 of the rate its doc comment states.
+func TestBlockedBloomFilter(t *testing.T) {
+	run := func(bitsPerKey int, documented float64) {
+		const keys, probes = 20000, 100000
+		f := NewBlockedBloomFilter(keys, bitsPerKey)
+		for i := range keys {
+			f.Add(fmt.Appendf(nil, "key-%d", i))
+		}
+		for i := range keys {
+			if key := fmt.Appendf(nil, "key-%d", i); !f.MayContain(key) {
+				t.Fatalf("MayContain(%q) = false after Add with %d bits per key", key, bitsPerKey)
+			}
+		}
+		hits := 0
+		for i := range probes {
+			if f.MayContain(fmt.Appendf(nil, "other-%d", i)) {
+				hits++
+			}
+		}
+		if rate := float64(hits) / probes; rate < documented/1.5 || rate > documented*1.5 {
+			t.Errorf("false positive rate with %d bits per key = %.4f; documented as about %.4f", bitsPerKey, rate, documented)
+		}
+	}
+
+	run(10, 0.03)
+	run(16, 0.005)
+
+	if f := NewBlockedBloomFilter(0, 10); f.MayContain([]byte("x")) {
+		t.Errorf("empty NewBlockedBloomFilter(0, 10).MayContain = true; want false")
+	}
+}
@@ -0,0 +1,216 @@
+package swar
+
+import "math/bits"
+
+// ReplaceByte copies src to dst with every occurrence of old replaced by new
+// Returns the number of replacements; dst must hold len(src) bytes and may equal src
+func ReplaceByte(dst, src []byte, old, new byte) int {
+	dst = dst[:len(src)]
+	src = unaliased(dst, src, true)
+	from, to := Dupe(old), Dupe(new)
+	count := 0
+	for i := 0; i < len(src); i += 8 {
+		v := loadLane(src, i)
+		m := HighBitWhereEqual(v, from)
+		if i+8 > len(src) {
+			m &= uint64(1)<<(8*(len(src)-i)) - 1
+		}
+		count += bits.OnesCount64(m)
+		storeLane(dst, i, SelectByHighBit(to, v, m))
+	}
+	return count
+}
+
+// FillBytes sets every byte of b to c, a lane at a time
+// Equivalent to a memset; the final partial lane is written byte-wise
+func FillBytes(b []byte, c byte) {
+	pattern := Dupe(c)
+	for i := 0; i < len(b); i += 8 {
+		storeLane(b, i, pattern)
+	}
+}
+
+// FillPattern repeats pattern across b, starting with pattern[0] at b[0]
+// The lcm(len(pattern), 8)/8 distinct lanes are built once and cycled; an empty pattern leaves b unchanged
+func FillPattern(b, pattern []byte) {
+	if len(pattern) == 0 {
+		return
+	}
+	lanes := make([]uint64, len(pattern)>>min(bits.TrailingZeros(uint(len(pattern))), 3))
+	for k := range lanes {
+		lanes[k] = DupePatternAt(pattern, 8*k)
+	}
+	for i, k := 0, 0; i < len(b); i += 8 {
+		storeLane(b, i, lanes[k])
+		if k++; k == len(lanes) {
+			k = 0
+		}
+	}
+}
+
+// NthIndexByte returns the index of the zero-based n-th occurrence of c in b, or -1
+// Skips whole lanes by popcount and only walks matches in the lane that holds it
+func NthIndexByte(b []byte, c byte, n int) int {
+	if n < 0 {
+		return -1
+	}
+	pattern := Dupe(c)
+	for i := 0; i < len(b); i += 8 {
+		m := HighBitWhereEqual(loadLane(b, i), pattern)
+		if i+8 > len(b) {
+			m &= uint64(1)<<(8*(len(b)-i)) - 1
+		}
+		count := bits.OnesCount64(m)
+		if n >= count {
+			n -= count
+			continue
+		}
+		for ; n > 0; n-- {
+			m &= m - 1
+		}
+		return i + bits.TrailingZeros64(m)>>3
+	}
+	return -1
+}
+
+// CountAnyBytes returns how many bytes of b equal any byte in set
+// The per-byte equality masks are fused so each lane costs one popcount
+func CountAnyBytes(b []byte, set ...byte) int {
+	patterns := make([]uint64, len(set))
+	for i, c := range set {
+		patterns[i] = Dupe(c)
+	}
+	return countMasked(b, func(v uint64) uint64 {
+		var m uint64
+		for _, p := range patterns {
+			m |= HighBitWhereEqual(v, p)
+		}
+		return m
+	})
+}
+
+// CountMatches returns how many bytes of b have their high bit set by classifier
+// Accepts any HighBitWhere*-style mask, e.g. a RangeMatcher's Mask method
+func CountMatches(b []byte, classifier func(uint64) uint64) int {
+	return countMasked(b, classifier)
+}
+
+// RemoveByte copies src to dst without any occurrence of c and returns the length
+// dst must hold len(src) bytes and may be the same slice as src
+func RemoveByte(dst, src []byte, c byte) int {
+	pattern := Dupe(c)
+	return Compress(dst, src, func(v uint64) uint64 {
+		return HighBitWhereEqual(v, pattern)
+	})
+}
+
+// Compress left-packs the bytes of src not flagged by mask into dst and returns the length
+// PEXT per lane via OnesPositions; lanes without matches copy whole. dst may equal src
+func Compress(dst, src []byte, mask func(uint64) uint64) int {
+	dst = dst[:len(src)]
+	src = unaliased(dst, src, true)
+	n := 0
+	for i := 0; i < len(src); i += 8 {
+		v := loadLane(src, i)
+		m := mask(v) & HighBits
+		if m == 0 && i+8 <= len(src) {
+			storeLane(dst, n, v)
+			n += 8
+			continue
+		}
+		keep := ExtractLowBits((m ^ HighBits) >> 7)
+		if i+8 > len(src) {
+			keep &= byte(1)<<(len(src)-i) - 1
+		}
+		for _, p := range Lookup.OnesPositions[keep][:Lookup.OnesCount[keep]] {
+			dst[n] = byte(v >> (8 * p))
+			n++
+		}
+	}
+	return n
+}
+
+// Expand scatters src into the bytes of dst selected by bit i%64 of mask[i/64], zeroing the others
+// The inverse of Compress, like PDEP per lane; returns the number of src bytes used
+func Expand(dst, src []byte, mask []uint64) int {
+	src = unaliased(dst, src, false)
+	n := 0
+	for i := 0; i < len(dst); i += 8 {
+		sel := byte(mask[i/64] >> (i % 64))
+		if i+8 > len(dst) {
+			sel &= byte(1)<<(len(dst)-i) - 1
+		}
+		if sel == 0xFF && len(src)-n >= 8 {
+			storeLane(dst, i, loadLane(src, n))
+			n += 8
+			continue
+		}
+		var lane uint64
+		for _, p := range Lookup.OnesPositions[sel][:Lookup.OnesCount[sel]] {
+			if n == len(src) {
+				break
+			}
+			lane |= uint64(src[n]) << (8 * p)
+			n++
+		}
+		storeLane(dst, i, lane)
+	}
+	return n
+}
+
+// AddSaturating adds each byte of src to the matching byte of dst in place, clipping at 255
+// Covers the common length of dst and src; for accumulation buffers such as heatmaps
+func AddSaturating(dst, src []byte) {
+	transformLanes2(dst, dst, src, AddBytesWithMaximum)
+}
+
+// SubtractSaturating subtracts each byte of src from the matching byte of dst in place, clipping at 0
+// Covers the common length of dst and src; the inverse step of AddSaturating when nothing clipped
+func SubtractSaturating(dst, src []byte) {
+	transformLanes2(dst, dst, src, SubtractBytesWithMinimum)
+}
+
+// MaxInto keeps the larger of each byte of dst and the matching byte of src in dst
+// Covers the common length of dst and src; builds peak-hold buffers and lighten blends
+func MaxInto(dst, src []byte) {
+	transformLanes2(dst, dst, src, SelectLargerBytes)
+}
+
+// MinInto keeps the smaller of each byte of dst and the matching byte of src in dst
+// Covers the common length of dst and src; builds trough-hold buffers and darken blends
+func MinInto(dst, src []byte) {
+	transformLanes2(dst, dst, src, SelectSmallerBytes)
+}
+
+// GatherBytes sets dst[i] = src[idx[i]] for each index, packing eight results into a lane per store
+// dst must hold len(idx) bytes; an index outside src panics like any out of range index
+func GatherBytes(dst, src []byte, idx []int32) {
+	dst = dst[:len(idx)]
+	src = unaliased(dst, src, false)
+	i := 0
+	for ; i+8 <= len(idx); i += 8 {
+		x := idx[i : i+8 : i+8]
+		storeLane(dst, i, uint64(src[x[0]])|uint64(src[x[1]])<<8|
+			uint64(src[x[2]])<<16|uint64(src[x[3]])<<24|
+			uint64(src[x[4]])<<32|uint64(src[x[5]])<<40|
+			uint64(src[x[6]])<<48|uint64(src[x[7]])<<56)
+	}
+	for ; i < len(idx); i++ {
+		dst[i] = src[idx[i]]
+	}
+}
+
+// ScatterBytes sets dst[idx[i]] = src[i] for each index, reading src a lane at a time
+// src must hold len(idx) bytes; when an index repeats, the later byte of src wins
+func ScatterBytes(dst, src []byte, idx []int32) {
+	src = unaliased(dst, src[:len(idx)], false)
+	i := 0
+	for ; i+8 <= len(idx); i += 8 {
+		v, x := loadLane(src, i), idx[i:i+8:i+8]
+		dst[x[0]], dst[x[1]], dst[x[2]], dst[x[3]] = byte(v), byte(v>>8), byte(v>>16), byte(v>>24)
+		dst[x[4]], dst[x[5]], dst[x[6]], dst[x[7]] = byte(v>>32), byte(v>>40), byte(v>>48), byte(v>>56)
+	}
+	for ; i < len(idx); i++ {
+		dst[idx[i]] = src[i]
+	}
+}
@@ -0,0 +1,284 @@
+package swar
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestReplaceByte verifies single byte replacement against bytes.ReplaceAll, including the
+// replacement count, in-place use, and a zero old byte that must not match lane padding.
+func TestReplaceByte(t *testing.T) {
+	run := func(src string, old, new byte) {
+		want := bytes.ReplaceAll([]byte(src), []byte{old}, []byte{new})
+		wantCount := bytes.Count([]byte(src), []byte{old})
+		dst := make([]byte, len(src))
+		if n := ReplaceByte(dst, []byte(src), old, new); string(dst) != string(want) || n != wantCount {
+			t.Errorf("ReplaceByte(%q, %q, %q) = %q, %d; want %q, %d", src, old, new, dst, n, want, wantCount)
+		}
+		inPlace := []byte(src)
+		if ReplaceByte(inPlace, inPlace, old, new); string(inPlace) != string(want) {
+			t.Errorf("ReplaceByte(%q, %q, %q) in place = %q; want %q", src, old, new, inPlace, want)
+		}
+	}
+
+	run("", 'a', 'b')
+	run("a,b,c", ',', ';')
+	run("path/to/some/deeply/nested/file", '/', '\\')
+	run("nul\x00separated\x00fields", 0, '\n')
+	run("abc", 0, 'x')
+	run("aaaaaaaaaaaaaaaaa", 'a', 'a')
+}
+
+// TestFillPattern compares fills with bytes.Repeat for pattern lengths that do and do not
+// divide a lane, over buffer lengths that end mid-lane and mid-pattern.
+func TestFillPattern(t *testing.T) {
+	for n := 0; n < 40; n++ {
+		b := make([]byte, n)
+		FillBytes(b, 0xA5)
+		if want := bytes.Repeat([]byte{0xA5}, n); !bytes.Equal(b, want) {
+			t.Errorf("FillBytes(len %d) = %x; want %x", n, b, want)
+		}
+		for _, pattern := range []string{"", "x", "ab", "\xde\xad\xbe\xef", "abc", "12345", "0123456789", "0123456789ab"} {
+			b := make([]byte, n)
+			FillPattern(b, []byte(pattern))
+			want := make([]byte, n)
+			if pattern != "" {
+				want = bytes.Repeat([]byte(pattern), n/len(pattern)+1)[:n]
+			}
+			if !bytes.Equal(b, want) {
+				t.Errorf("FillPattern(len %d, %q) = %q; want %q", n, pattern, b, want)
+			}
+		}
+	}
+}
+
+// TestNthIndexByte checks every occurrence against a scalar count, plus one past the last.
+// Zero padding in the final lane must not be counted when searching for NUL.
+func TestNthIndexByte(t *testing.T) {
+	run := func(src string, c byte) {
+		n := 0
+		for i := 0; i < len(src); i++ {
+			if src[i] == c {
+				if got := NthIndexByte([]byte(src), c, n); got != i {
+					t.Errorf("NthIndexByte(%q, %q, %d) = %d; want %d", src, c, n, got, i)
+				}
+				n++
+			}
+		}
+		if got := NthIndexByte([]byte(src), c, n); got != -1 {
+			t.Errorf("NthIndexByte(%q, %q, %d) = %d; want -1", src, c, n, got)
+		}
+	}
+
+	run("", ',')
+	run("a,b,c", ',')
+	run("name,age,,city,country,zip,,,,phone,email", ',')
+	run(",,,,,,,,,,,,,,,,,", ',')
+	run("ab\x00cd", 0)
+	run("no commas here at all", ',')
+}
+
+// TestCountAnyBytes compares set and classifier counts with scalar loops. NUL in the set
+// and a zero-matching classifier catch any padding bytes leaking into the total.
+func TestCountAnyBytes(t *testing.T) {
+	vowels := NewRangeMatcher([2]byte{'a', 'a'}, [2]byte{'e', 'e'}, [2]byte{'i', 'i'}, [2]byte{'o', 'o'}, [2]byte{'u', 'u'})
+	run := func(src string, set ...byte) {
+		want := 0
+		for i := 0; i < len(src); i++ {
+			if bytes.IndexByte(set, src[i]) >= 0 {
+				want++
+			}
+		}
+		if got := CountAnyBytes([]byte(src), set...); got != want {
+			t.Errorf("CountAnyBytes(%q, %q) = %d; want %d", src, set, got, want)
+		}
+		want = strings.Count(src, "a") + strings.Count(src, "e") + strings.Count(src, "i") + strings.Count(src, "o") + strings.Count(src, "u")
+		if got := CountMatches([]byte(src), vowels.Mask); got != want {
+			t.Errorf("CountMatches(%q, vowels) = %d; want %d", src, got, want)
+		}
+	}
+
+	run("")
+	run("hello world", 'o', 'l')
+	run("the quick brown fox jumps over the lazy dog", 'a', 'e', 'i', 'o', 'u')
+	run("a,b;c\td,e;f", ',', ';', '\t')
+	run("nul\x00sep\x00", 0)
+	run("no match", 'z', 'q')
+}
+
+// TestRemoveByte verifies removal against bytes.ReplaceAll with an empty replacement,
+// covering lanes made entirely of the removed byte and in-place compaction.
+func TestRemoveByte(t *testing.T) {
+	run := func(src string, c byte) {
+		want := bytes.ReplaceAll([]byte(src), []byte{c}, nil)
+		dst := make([]byte, len(src))
+		if n := RemoveByte(dst, []byte(src), c); string(dst[:n]) != string(want) {
+			t.Errorf("RemoveByte(%q, %q) = %q; want %q", src, c, dst[:n], want)
+		}
+		inPlace := []byte(src)
+		if n := RemoveByte(inPlace, inPlace, c); string(inPlace[:n]) != string(want) {
+			t.Errorf("RemoveByte(%q, %q) in place = %q; want %q", src, c, inPlace[:n], want)
+		}
+	}
+
+	run("", ' ')
+	run("no match", 'z')
+	run("        spaces        everywhere  ", ' ')
+	run("nul\x00\x00\x00\x00\x00\x00\x00\x00\x00terminated\x00", 0)
+	run("abc", 0)
+}
+
+// TestCompress verifies left-packing with an arbitrary classifier against a scalar filter
+// for every length up to several lanes.
+func TestCompress(t *testing.T) {
+	digits := func(v uint64) uint64 { return digitMask(v) }
+	src := []byte("a1b22c333d4444e55555f666666g7777777h88888888i")
+	for n := 0; n <= len(src); n++ {
+		var want []byte
+		for _, c := range src[:n] {
+			if c < '0' || c > '9' {
+				want = append(want, c)
+			}
+		}
+		dst := make([]byte, n)
+		if got := Compress(dst, src[:n], digits); string(dst[:got]) != string(want) {
+			t.Errorf("Compress(%q, digits) = %q; want %q", src[:n], dst[:got], want)
+		}
+	}
+}
+
+// TestExpand verifies scattering against a scalar loop for bitmaps with full, empty and
+// sparse lanes, including running out of source bytes part way through. Expanding the
+// output of Compress with the complementary bitmap must restore the kept bytes.
+func TestExpand(t *testing.T) {
+	run := func(n int, mask []uint64, src []byte) {
+		want := make([]byte, n)
+		used := 0
+		for i := range want {
+			if mask[i/64]>>(i%64)&1 == 1 && used < len(src) {
+				want[i] = src[used]
+				used++
+			}
+		}
+		dst := bytes.Repeat([]byte{0xEE}, n)
+		if got := Expand(dst, src, mask); string(dst) != string(want) || got != used {
+			t.Errorf("Expand(len %d, %016x, %q) = %q, %d; want %q, %d", n, mask, src, dst, got, want, used)
+		}
+	}
+
+	src := []byte("0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ!@#$%^&*()")
+	for _, mask := range []uint64{0, ^uint64(0), 0x00FF_FF00_0F0F_A5A5, 0x8000_0000_0000_0001, 0xFFFF_FFFF_0000_0000} {
+		for _, n := range []int{0, 3, 8, 13, 64, 100} {
+			run(n, []uint64{mask, ^mask}, src)
+			run(n, []uint64{mask, mask}, src[:5])
+		}
+	}
+
+	text := []byte("keep d1g1ts out 0f this str1ng pl3ase")
+	keep := make([]byte, len(text))
+	k := Compress(keep, text, digitMask)
+	bitmap := make([]uint64, 1)
+	for i, c := range text {
+		if c < '0' || c > '9' {
+			bitmap[0] |= 1 << i
+		}
+	}
+	restored := make([]byte, len(text))
+	Expand(restored, keep[:k], bitmap)
+	for i, c := range text {
+		if bitmap[0]>>i&1 == 1 && restored[i] != c {
+			t.Errorf("Expand(Compress(%q)) byte %d = %q; want %q", text, i, restored[i], c)
+		}
+	}
+}
+
+// TestAccumulateInto compares the saturating and min/max accumulators with a byte loop,
+// including a src longer than dst that must leave dst's length untouched, a src shorter
+// than dst whose uncovered bytes must not change, and src aliasing dst.
+func TestAccumulateInto(t *testing.T) {
+	ops := []struct {
+		name string
+		f    func(dst, src []byte)
+		ref  func(d, s byte) byte
+	}{
+		{"AddSaturating", AddSaturating, func(d, s byte) byte { return byte(min(int(d)+int(s), 255)) }},
+		{"SubtractSaturating", SubtractSaturating, func(d, s byte) byte { return byte(max(int(d)-int(s), 0)) }},
+		{"MaxInto", MaxInto, func(d, s byte) byte { return max(d, s) }},
+		{"MinInto", MinInto, func(d, s byte) byte { return min(d, s) }},
+	}
+	a := make([]byte, 37)
+	b := make([]byte, 37)
+	for i := range a {
+		a[i], b[i] = byte(i*71), byte(i*29+100)
+	}
+	for _, op := range ops {
+		for _, lens := range [][2]int{{0, 0}, {37, 37}, {20, 37}, {37, 13}, {8, 8}} {
+			dst, src := bytes.Clone(a[:lens[0]]), b[:lens[1]]
+			want := bytes.Clone(dst)
+			for i := range min(len(dst), len(src)) {
+				want[i] = op.ref(dst[i], src[i])
+			}
+			if op.f(dst, src); !bytes.Equal(dst, want) {
+				t.Errorf("%s(%v, %v) = %v; want %v", op.name, a[:lens[0]], src, dst, want)
+			}
+		}
+		dst := bytes.Clone(a)
+		want := make([]byte, len(a))
+		for i, c := range a {
+			want[i] = op.ref(c, c)
+		}
+		if op.f(dst, dst); !bytes.Equal(dst, want) {
+			t.Errorf("%s(%v, itself) = %v; want %v", op.name, a, dst, want)
+		}
+	}
+}
+
+// TestGatherScatterBytes compares both directions with scalar loops for index lists that
+// permute, repeat and skip positions, and runs them in place, where a permutation must
+// read every byte before any is overwritten.
+func TestGatherScatterBytes(t *testing.T) {
+	src := make([]byte, 30)
+	for i := range src {
+		src[i] = byte(i*13 + 1)
+	}
+	for n := range 21 {
+		idx := make([]int32, n)
+		for i := range idx {
+			idx[i] = int32(i*7+3) % 17
+		}
+		want := make([]byte, n)
+		for i, x := range idx {
+			want[i] = src[x]
+		}
+		got := make([]byte, n)
+		if GatherBytes(got, src, idx); !bytes.Equal(got, want) {
+			t.Errorf("GatherBytes(%v) = %v; want %v", idx, got, want)
+		}
+
+		wantScatter := make([]byte, len(src))
+		for i, x := range idx {
+			wantScatter[x] = src[i]
+		}
+		gotScatter := make([]byte, len(src))
+		if ScatterBytes(gotScatter, src, idx); !bytes.Equal(gotScatter, wantScatter) {
+			t.Errorf("ScatterBytes(%v) = %v; want %v", idx, gotScatter, wantScatter)
+		}
+	}
+
+	perm := make([]int32, len(src))
+	for i := range perm {
+		perm[i] = int32(len(src) - 1 - i)
+	}
+	want := bytes.Clone(src)
+	slices.Reverse(want)
+	inPlace := bytes.Clone(src)
+	if GatherBytes(inPlace, inPlace, perm); !bytes.Equal(inPlace, want) {
+		t.Errorf("GatherBytes in place (reverse) = %v; want %v", inPlace, want)
+	}
+	inPlace = bytes.Clone(src)
+	if ScatterBytes(inPlace, inPlace, perm); !bytes.Equal(inPlace, want) {
+		t.Errorf("ScatterBytes in place (reverse) = %v; want %v", inPlace, want)
+	}
+}
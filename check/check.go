@@ -0,0 +1,178 @@
+// Package check verifies lane functions against scalar references
+//
+// Kernels built from the swar primitives are easy to get subtly wrong in a single byte
+// position or only when a carry crosses into a neighbour. The drivers here generate the
+// same inputs the swar package tests itself with: a geometric sweep over magnitudes,
+// every byte value and byte pair in every position, and seeded pseudo-random lanes.
+// CheckLaneFunc and CheckLaneFunc2 run all of them and report the first mismatch.
+package check
+
+import (
+	"fmt"
+	"iter"
+)
+
+const (
+	// lowBits has the lowest bit set in each byte
+	lowBits uint64 = 0x0101_0101_0101_0101
+	// golden is the 64-bit golden ratio, used to scramble sweep values into a second operand
+	golden uint64 = 0x9E37_79B9_7F4A_7C15
+	// sweepEnd bounds the geometric sweep, as in the swar package's own tests
+	sweepEnd uint64 = 0xFF_FF_FF_FF_FF
+	// randomCount is how many pseudo-random inputs the default checks add
+	randomCount = 1 << 16
+)
+
+// Mismatch describes an input on which a function and its reference disagree
+// Returned as the error of the Check functions; Inputs holds one or two operands
+type Mismatch struct {
+	Inputs    []uint64
+	Got, Want uint64
+}
+
+// Error formats the mismatch in the got/want style of the swar tests
+func (m *Mismatch) Error() string {
+	args := ""
+	for i, v := range m.Inputs {
+		if i > 0 {
+			args += ", "
+		}
+		args += fmt.Sprintf("0x%016x", v)
+	}
+	return fmt.Sprintf("f(%s) = 0x%016x; want 0x%016x", args, m.Got, m.Want)
+}
+
+// CheckLaneFunc compares f with ref on the sweep, bytewise and random inputs
+// Returns a *Mismatch for the first disagreement, or nil when they always agree
+func CheckLaneFunc(f, ref func(uint64) uint64) error {
+	for _, inputs := range []iter.Seq[uint64]{Sweep(), Bytewise(), Random(1, randomCount)} {
+		if err := CheckLaneFuncOn(f, ref, inputs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckLaneFunc2 compares the two-operand f with ref on the sweep, bytewise and random pairs
+// Returns a *Mismatch for the first disagreement, or nil when they always agree
+func CheckLaneFunc2(f, ref func(a, b uint64) uint64) error {
+	for _, inputs := range []iter.Seq2[uint64, uint64]{Sweep2(), Bytewise2(), Random2(1, randomCount)} {
+		if err := CheckLaneFunc2On(f, ref, inputs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckLaneFuncOn compares f with ref on the given inputs
+// Use it with a custom driver, e.g. to restrict inputs to a function's valid domain
+func CheckLaneFuncOn(f, ref func(uint64) uint64, inputs iter.Seq[uint64]) error {
+	for v := range inputs {
+		if got, want := f(v), ref(v); got != want {
+			return &Mismatch{[]uint64{v}, got, want}
+		}
+	}
+	return nil
+}
+
+// CheckLaneFunc2On compares the two-operand f with ref on the given pairs
+// Use it with a custom driver, e.g. to hold one operand to a valid mask
+func CheckLaneFunc2On(f, ref func(a, b uint64) uint64, inputs iter.Seq2[uint64, uint64]) error {
+	for a, b := range inputs {
+		if got, want := f(a, b), ref(a, b); got != want {
+			return &Mismatch{[]uint64{a, b}, got, want}
+		}
+	}
+	return nil
+}
+
+// Sweep yields a geometric sequence from 0 to 2^40 and each value scrambled across all bytes
+// Growing by 12/11 per step covers every magnitude while staying a few thousand inputs long
+func Sweep() iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		for n := uint64(0); n < sweepEnd; n = (n*12 + 13) / 11 {
+			if !yield(n) || !yield(n*golden) {
+				return
+			}
+		}
+	}
+}
+
+// Sweep2 yields the geometric sequence paired with a scrambled second operand
+// Also pairs each value with a lane of one repeated byte, where carries between bytes are likeliest
+func Sweep2() iter.Seq2[uint64, uint64] {
+	return func(yield func(uint64, uint64) bool) {
+		for n := uint64(0); n < sweepEnd; n = (n*12 + 13) / 11 {
+			m := n*golden ^ 0x0000_0053_5195_2b76
+			if !yield(n, m) || !yield(m, n) || !yield(n, m&0xFF*lowBits) {
+				return
+			}
+		}
+	}
+}
+
+// Bytewise yields every byte value repeated across a lane, then alone in each position
+// Exhaustive for functions that treat bytes independently, and exposes leaks into neighbours
+func Bytewise() iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		for c := range uint64(256) {
+			if !yield(c * lowBits) {
+				return
+			}
+			for i := range 8 {
+				if !yield(c<<(8*i)) || !yield(^((^c & 0xFF) << (8 * i))) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Bytewise2 yields every pair of byte values in every position
+// Each pair appears repeated across the lane and with complemented neighbours in odd bytes
+func Bytewise2() iter.Seq2[uint64, uint64] {
+	return func(yield func(uint64, uint64) bool) {
+		const odd uint64 = 0xFF00_FF00_FF00_FF00
+		for x := range uint64(256) {
+			for y := range uint64(256) {
+				a, b := x*lowBits, y*lowBits
+				if !yield(a, b) || !yield(a^odd, b^odd) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Random yields count pseudo-random lanes from a SplitMix64 stream seeded with seed
+// The same seed always gives the same inputs, so failures reproduce
+func Random(seed uint64, count int) iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		for range count {
+			if !yield(splitMix64(&seed)) {
+				return
+			}
+		}
+	}
+}
+
+// Random2 yields count pairs of pseudo-random lanes from a SplitMix64 stream seeded with seed
+// The same seed always gives the same inputs, so failures reproduce
+func Random2(seed uint64, count int) iter.Seq2[uint64, uint64] {
+	return func(yield func(uint64, uint64) bool) {
+		for range count {
+			if !yield(splitMix64(&seed), splitMix64(&seed)) {
+				return
+			}
+		}
+	}
+}
+
+// splitMix64 advances state and returns the next output of Vigna's SplitMix64
+func splitMix64(state *uint64) uint64 {
+	*state += golden
+	z := *state
+	z = (z ^ z>>30) * 0xBF58_476D_1CE4_E5B9
+	z = (z ^ z>>27) * 0x94D0_49BB_1331_11EB
+	return z ^ z>>31
+}
@@ -0,0 +1,90 @@
+package check_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dans-stuff/swar"
+	"github.com/dans-stuff/swar/check"
+	"github.com/dans-stuff/swar/ref"
+)
+
+// TestCheckLaneFunc runs correct kernels through the default drivers, which must all pass,
+// and subtly broken ones, which must be caught with a mismatch that reproduces.
+func TestCheckLaneFunc(t *testing.T) {
+	if err := check.CheckLaneFunc(swar.CountOnesPerByte, ref.CountOnesPerByte); err != nil {
+		t.Errorf("CheckLaneFunc(CountOnesPerByte) = %v; want nil", err)
+	}
+	if err := check.CheckLaneFunc2(swar.AverageBytes, ref.AverageBytes); err != nil {
+		t.Errorf("CheckLaneFunc2(AverageBytes) = %v; want nil", err)
+	}
+
+	// Doubling without masking lets each high bit carry into the next byte up
+	double := func(v uint64) uint64 { return v << 1 }
+	var mismatch *check.Mismatch
+	err := check.CheckLaneFunc(double, func(v uint64) uint64 { return ref.AddBytesWithWrapping(v, v) })
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("CheckLaneFunc(v<<1) = %v; want a *Mismatch", err)
+	}
+	if v := mismatch.Inputs[0]; double(v) != mismatch.Got || ref.AddBytesWithWrapping(v, v) != mismatch.Want || mismatch.Got == mismatch.Want {
+		t.Errorf("CheckLaneFunc(v<<1) = %v; not a reproducible mismatch", err)
+	}
+
+	// A plain shifted sum loses the carry out of each byte
+	average := func(a, b uint64) uint64 { return (a + b) >> 1 & 0x7F7F_7F7F_7F7F_7F7F }
+	err = check.CheckLaneFunc2(average, ref.AverageBytes)
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("CheckLaneFunc2((a+b)>>1) = %v; want a *Mismatch", err)
+	}
+	if a, b := mismatch.Inputs[0], mismatch.Inputs[1]; average(a, b) != mismatch.Got || ref.AverageBytes(a, b) != mismatch.Want {
+		t.Errorf("CheckLaneFunc2((a+b)>>1) = %v; not a reproducible mismatch", err)
+	}
+}
+
+// TestDrivers checks that the bytewise drivers really are exhaustive, that every driver is
+// deterministic, and that they stop as soon as the consumer does.
+func TestDrivers(t *testing.T) {
+	var seen [8][256]bool
+	for v := range check.Bytewise() {
+		for i := range 8 {
+			seen[i][byte(v>>(8*i))] = true
+		}
+	}
+	for i := range seen {
+		for c, ok := range seen[i] {
+			if !ok {
+				t.Errorf("Bytewise() never has 0x%02x in byte %d", c, i)
+			}
+		}
+	}
+
+	var pairs [256][256]bool
+	for a, b := range check.Bytewise2() {
+		pairs[byte(a>>24)][byte(b>>24)] = true
+	}
+	for x := range pairs {
+		for y, ok := range pairs[x] {
+			if !ok {
+				t.Errorf("Bytewise2() never pairs 0x%02x with 0x%02x in byte 3", x, y)
+			}
+		}
+	}
+
+	var first, second []uint64
+	for v := range check.Random(42, 100) {
+		first = append(first, v)
+	}
+	for v := range check.Random(42, 100) {
+		second = append(second, v)
+	}
+	if len(first) != 100 || first[0] == first[1] || first[99] != second[99] {
+		t.Errorf("Random(42, 100) is not a repeatable stream of 100 values")
+	}
+
+	for range check.Sweep() {
+		break
+	}
+	for range check.Sweep2() {
+		break
+	}
+}
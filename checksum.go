@@ -0,0 +1,146 @@
+package swar
+
+import "math/bits"
+
+// InternetChecksum returns the RFC 1071 checksum of b as used by IPv4, ICMP, TCP and UDP
+// Odd lengths are padded with a zero byte; the result is ready to store big-endian
+func InternetChecksum(b []byte) uint16 {
+	return ^OnesComplementSum(0, b, 0)
+}
+
+// OnesComplementSum adds b, starting at message position offset, to a running ones' complement sum
+// Pieces can be summed separately in network byte order; complement the final sum for the checksum
+func OnesComplementSum(sum uint16, b []byte, offset int) uint16 {
+	var acc, carry uint64
+	chunks, unused := BytesToLanes(b)
+	for _, chunk := range chunks {
+		acc, carry = bits.Add64(acc, chunk, carry)
+	}
+	acc, carry = bits.Add64(acc, loadLane(b, unused), carry)
+	acc, carry = bits.Add64(acc, carry, 0)
+	acc += carry // a second end-around carry leaves acc at zero, so this one cannot overflow
+	s := foldOnesComplement(acc)
+	if offset%2 == 0 {
+		// Lanes were loaded little-endian; the end-around sum commutes with a byte swap
+		s = bits.ReverseBytes16(s)
+	}
+	t := uint32(sum) + uint32(s)
+	return uint16(t + t>>16)
+}
+
+// foldOnesComplement reduces a 64-bit ones' complement sum to 16 bits
+// Each fold adds the halves back together with their end-around carry
+func foldOnesComplement(v uint64) uint16 {
+	v = v>>32 + v&0xFFFF_FFFF
+	v = v>>32 + v&0xFFFF_FFFF
+	v = v>>16 + v&0xFFFF
+	v = v>>16 + v&0xFFFF
+	return uint16(v)
+}
+
+// checksumBlockLanes bounds how many lanes run between modulo reductions
+// The position-weighted sums stay far below 2^64 for this many lanes of any width
+const checksumBlockLanes = 2048
+
+// Adler32 returns the Adler-32 checksum of b, matching hash/adler32
+// Each lane adds SumBytes to a and a position-weighted byte sum to b, reducing rarely
+func Adler32(b []byte) uint32 {
+	s1, s2 := byteSums(1, 0, b, 65521)
+	return uint32(s2<<16 | s1)
+}
+
+// Fletcher16 returns the Fletcher-16 checksum of b with sums modulo 255
+// The second sum is returned in the high byte
+func Fletcher16(b []byte) uint16 {
+	s1, s2 := byteSums(0, 0, b, 255)
+	return uint16(s2<<8 | s1)
+}
+
+// byteSums runs the Adler/Fletcher recurrence a += x, b += a over bytes modulo mod
+// A lane advances b by 8a plus 8,7,...,1 times its bytes, computed with two multiplies
+func byteSums(a, b uint64, data []byte, mod uint64) (uint64, uint64) {
+	chunks, unused := BytesToLanes(data)
+	for len(chunks) > 0 {
+		block := chunks[:min(len(chunks), checksumBlockLanes)]
+		chunks = chunks[len(block):]
+		for _, v := range block {
+			even := v & 0x00FF_00FF_00FF_00FF
+			odd := v >> 8 & 0x00FF_00FF_00FF_00FF
+			b += 8*a + even*0x0008_0006_0004_0002>>48 + odd*0x0007_0005_0003_0001>>48
+			a += SumBytes(v)
+		}
+		a, b = a%mod, b%mod
+	}
+	for _, c := range data[unused:] {
+		a += uint64(c)
+		b += a
+	}
+	return a % mod, b % mod
+}
+
+// Fletcher32 returns the Fletcher-32 checksum of b as little-endian 16-bit words modulo 65535
+// An odd final byte is padded with zero; the second sum is returned in the high half
+func Fletcher32(b []byte) uint32 {
+	var s1, s2 uint64
+	chunks, unused := BytesToLanes(b)
+	for len(chunks) > 0 {
+		block := chunks[:min(len(chunks), checksumBlockLanes)]
+		chunks = chunks[len(block):]
+		for _, v := range block {
+			even := v & 0x0000_FFFF_0000_FFFF
+			odd := v >> 16 & 0x0000_FFFF_0000_FFFF
+			s2 += 4*s1 + even*0x0000_0004_0000_0002>>32 + odd*0x0000_0003_0000_0001>>32
+			s1 += (even + odd) * 0x0000_0001_0000_0001 >> 32
+		}
+		s1, s2 = s1%65535, s2%65535
+	}
+	tail := b[unused:]
+	for i := 0; i < len(tail); i += 2 {
+		word := uint64(tail[i])
+		if i+1 < len(tail) {
+			word |= uint64(tail[i+1]) << 8
+		}
+		s1 += word
+		s2 += s1
+	}
+	return uint32(s2%65535<<16 | s1%65535)
+}
+
+// XorChecksum returns the XOR of every byte of b, as used by NMEA sentences
+// Lanes are XORed together and the final lane is folded down to one byte
+func XorChecksum(b []byte) byte {
+	x := xorLanes(b)
+	x ^= x >> 32
+	x ^= x >> 16
+	x ^= x >> 8
+	return byte(x)
+}
+
+// LRC returns the longitudinal redundancy check of b, as used by Modbus ASCII
+// The two's complement of the byte sum, so adding it to the sum of b gives zero
+func LRC(b []byte) byte {
+	var acc uint64
+	chunks, unused := BytesToLanes(b)
+	for _, chunk := range chunks {
+		acc = AddBytesWithWrapping(acc, chunk)
+	}
+	acc = AddBytesWithWrapping(acc, loadLane(b, unused))
+	return -byte(SumBytes(acc))
+}
+
+// Parity reports whether b contains an odd number of set bits
+// XOR preserves bit parity, so one popcount of the folded lanes is enough
+func Parity(b []byte) bool {
+	return bits.OnesCount64(xorLanes(b))&1 == 1
+}
+
+// xorLanes XORs every lane of b together, zero-padding the tail
+// Shared by the XOR checksum and parity, which only differ in the final fold
+func xorLanes(b []byte) uint64 {
+	var x uint64
+	chunks, unused := BytesToLanes(b)
+	for _, chunk := range chunks {
+		x ^= chunk
+	}
+	return x ^ loadLane(b, unused)
+}
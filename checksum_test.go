@@ -0,0 +1,122 @@
+package swar
+
+import (
+	"bytes"
+	"hash/adler32"
+	"math/bits"
+	"testing"
+)
+
+// internetChecksumReference sums big-endian 16-bit words one at a time as in RFC 1071.
+func internetChecksumReference(b []byte) uint16 {
+	var sum uint32
+	for i := 0; i < len(b); i += 2 {
+		word := uint32(b[i]) << 8
+		if i+1 < len(b) {
+			word |= uint32(b[i+1])
+		}
+		sum += word
+		sum = sum&0xFFFF + sum>>16
+	}
+	return ^uint16(sum)
+}
+
+// TestInternetChecksum compares with the RFC 1071 word loop for every length, and checks
+// that summing a buffer in two pieces at any split, odd or even, gives the same checksum.
+func TestInternetChecksum(t *testing.T) {
+	// Example header from RFC 1071 section 3; checksum field zeroed
+	header := []byte{0x45, 0x00, 0x00, 0x73, 0x00, 0x00, 0x40, 0x00, 0x40, 0x11, 0x00, 0x00, 0xc0, 0xa8, 0x00, 0x01, 0xc0, 0xa8, 0x00, 0xc7}
+	if got := InternetChecksum(header); got != 0xb861 {
+		t.Errorf("InternetChecksum(header) = 0x%04x; want 0xb861", got)
+	}
+
+	b := make([]byte, 100)
+	for i := range b {
+		b[i] = byte(i*37 + 0xF0)
+	}
+	for n := 0; n <= len(b); n++ {
+		want := internetChecksumReference(b[:n])
+		if got := InternetChecksum(b[:n]); got != want {
+			t.Errorf("InternetChecksum(len %d) = 0x%04x; want 0x%04x", n, got, want)
+		}
+		for split := 0; split <= n; split++ {
+			sum := OnesComplementSum(0, b[:split], 0)
+			sum = OnesComplementSum(sum, b[split:n], split)
+			if ^sum != want {
+				t.Errorf("OnesComplementSum split at %d of %d = 0x%04x; want 0x%04x", split, n, ^sum, want)
+			}
+		}
+	}
+}
+
+// TestAdlerFletcher compares the lane-at-a-time checksums with byte loops and hash/adler32.
+// Inputs longer than checksumBlockLanes lanes of 0xFF bytes check the deferred modulo.
+func TestAdlerFletcher(t *testing.T) {
+	run := func(b []byte) {
+		if got, want := Adler32(b), adler32.Checksum(b); got != want {
+			t.Errorf("Adler32(len %d) = 0x%08x; want 0x%08x", len(b), got, want)
+		}
+		var a16, b16 uint32
+		for _, c := range b {
+			a16 = (a16 + uint32(c)) % 255
+			b16 = (b16 + a16) % 255
+		}
+		if got, want := Fletcher16(b), uint16(b16<<8|a16); got != want {
+			t.Errorf("Fletcher16(len %d) = 0x%04x; want 0x%04x", len(b), got, want)
+		}
+		var a32, b32 uint64
+		for i := 0; i < len(b); i += 2 {
+			word := uint64(b[i])
+			if i+1 < len(b) {
+				word |= uint64(b[i+1]) << 8
+			}
+			a32 = (a32 + word) % 65535
+			b32 = (b32 + a32) % 65535
+		}
+		if got, want := Fletcher32(b), uint32(b32<<16|a32); got != want {
+			t.Errorf("Fletcher32(len %d) = 0x%08x; want 0x%08x", len(b), got, want)
+		}
+	}
+
+	b := make([]byte, 100)
+	for i := range b {
+		b[i] = byte(i*37 + 0xF0)
+	}
+	for n := 0; n <= len(b); n++ {
+		run(b[:n])
+	}
+	run([]byte("abcde"))
+	run(bytes.Repeat([]byte{0xFF}, 8*checksumBlockLanes*3+5))
+}
+
+// TestXorLRCParity compares the folded checksums with byte loops for every length, and
+// checks a known NMEA sentence so the XOR matches what receivers expect on the wire.
+func TestXorLRCParity(t *testing.T) {
+	nmea := "GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,"
+	if got := XorChecksum([]byte(nmea)); got != 0x47 {
+		t.Errorf("XorChecksum(%q) = 0x%02x; want 0x47", nmea, got)
+	}
+
+	b := make([]byte, 100)
+	for i := range b {
+		b[i] = byte(i*37 + 0xF0)
+	}
+	for n := 0; n <= len(b); n++ {
+		var x, sum byte
+		ones := 0
+		for _, c := range b[:n] {
+			x ^= c
+			sum += c
+			ones += bits.OnesCount8(c)
+		}
+		if got := XorChecksum(b[:n]); got != x {
+			t.Errorf("XorChecksum(len %d) = 0x%02x; want 0x%02x", n, got, x)
+		}
+		if got := LRC(b[:n]); got != -sum {
+			t.Errorf("LRC(len %d) = 0x%02x; want 0x%02x", n, got, -sum)
+		}
+		if got := Parity(b[:n]); got != (ones%2 == 1) {
+			t.Errorf("Parity(len %d) = %v; want %v", n, got, ones%2 == 1)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package swar
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// gearTable maps each byte to a random 64-bit value for the Gear rolling hash
+// Generated with SplitMix64 from a fixed seed so chunk boundaries are stable across runs
+var gearTable = func() (table [256]uint64) {
+	state := uint64(0x5357_4152_4745_4152)
+	for i := range table {
+		state += 0x9E37_79B9_7F4A_7C15
+		z := state
+		z = (z ^ z>>30) * 0xBF58_476D_1CE4_E5B9
+		z = (z ^ z>>27) * 0x94D0_49BB_1331_11EB
+		table[i] = z ^ z>>31
+	}
+	return table
+}()
+
+// Gear is a rolling hash over roughly the last 64 bytes rolled in
+// Each byte shifts the hash left, so older bytes fall off the top without a removal step
+type Gear struct {
+	hash uint64
+}
+
+// Roll adds c to the hash and returns the updated value
+// Equal 64-byte windows give equal hashes whatever came before them
+func (g *Gear) Roll(c byte) uint64 {
+	g.hash = g.hash<<1 + gearTable[c]
+	return g.hash
+}
+
+// Sum64 returns the current hash value
+func (g *Gear) Sum64() uint64 {
+	return g.hash
+}
+
+// Chunker splits data into content-defined chunks with FastCDC normalized chunking
+// Boundaries depend only on nearby bytes, so edits move only the chunks around them
+type Chunker struct {
+	minSize, avgSize, maxSize int
+	maskS, maskL              uint64
+}
+
+// NewChunker creates a chunker producing chunks between minSize and maxSize bytes
+// Sizes cluster around avgSize; panics unless 0 < minSize <= avgSize <= maxSize
+func NewChunker(minSize, avgSize, maxSize int) *Chunker {
+	if minSize <= 0 || minSize > avgSize || avgSize > maxSize {
+		panic("swar: NewChunker requires 0 < minSize <= avgSize <= maxSize")
+	}
+	n := bits.Len(uint(avgSize)) - 1
+	return &Chunker{
+		minSize: minSize,
+		avgSize: avgSize,
+		maxSize: maxSize,
+		// Harder to match before the average size and easier after it
+		maskS: ^uint64(0) << (64 - min(n+1, 63)),
+		maskL: ^uint64(0) << (64 - max(n-1, 1)),
+	}
+}
+
+// Next returns the length of the chunk at the start of b
+// The whole of b is one chunk when it is no longer than the minimum size
+func (c *Chunker) Next(b []byte) int {
+	if len(b) <= c.minSize {
+		return len(b)
+	}
+	end := min(len(b), c.maxSize)
+	normal := min(end, c.avgSize)
+	var h uint64
+	if n, ok := scanGear(b, c.minSize, normal, &h, c.maskS); ok {
+		return n
+	}
+	if n, ok := scanGear(b, normal, end, &h, c.maskL); ok {
+		return n
+	}
+	return end
+}
+
+// scanGear rolls b[i:end] into h and returns the cut length at the first hash with mask clear
+// Bytes are taken from one lane load per 8 positions instead of indexing b each time
+func scanGear(b []byte, i, end int, h *uint64, mask uint64) (int, bool) {
+	hash := *h
+	for ; i+8 <= end; i += 8 {
+		v := loadLane(b, i)
+		for k := range 8 {
+			hash = hash<<1 + gearTable[byte(v>>(8*k))]
+			if hash&mask == 0 {
+				return i + k + 1, true
+			}
+		}
+	}
+	for ; i < end; i++ {
+		hash = hash<<1 + gearTable[b[i]]
+		if hash&mask == 0 {
+			return i + 1, true
+		}
+	}
+	*h = hash
+	return end, false
+}
+
+// Chunks yields consecutive content-defined chunks of b
+// The chunks are subslices of b and together cover it exactly
+func (c *Chunker) Chunks(b []byte) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		for rest := b; len(rest) > 0; {
+			n := c.Next(rest)
+			if !yield(rest[:n]) {
+				return
+			}
+			rest = rest[n:]
+		}
+	}
+}
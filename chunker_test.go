@@ -0,0 +1,84 @@
+package swar
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestGearRoll checks that the hash depends only on the last 64 bytes rolled in, which is
+// what lets two streams agree on boundaries once they share content.
+func TestGearRoll(t *testing.T) {
+	window := make([]byte, 64)
+	for i := range window {
+		window[i] = byte(i * 29)
+	}
+	var a, b Gear
+	for _, c := range []byte("some prefix") {
+		a.Roll(c)
+	}
+	for _, c := range window {
+		a.Roll(c)
+		b.Roll(c)
+	}
+	if a.Sum64() != b.Sum64() {
+		t.Errorf("Gear after shared window = 0x%016x and 0x%016x; want equal", a.Sum64(), b.Sum64())
+	}
+}
+
+// TestChunker checks that chunks cover the input exactly, respect the size limits, can be
+// ranged over again, and resynchronise after an insertion near the start so later chunks
+// are unchanged.
+func TestChunker(t *testing.T) {
+	data := make([]byte, 1<<18)
+	state := uint64(1)
+	for i := range data {
+		state = state*6364136223846793005 + 1442695040888963407
+		data[i] = byte(state >> 56)
+	}
+	c := NewChunker(512, 2048, 8192)
+	chunk := func(b []byte) (chunks [][]byte) {
+		for ch := range c.Chunks(b) {
+			chunks = append(chunks, ch)
+		}
+		return chunks
+	}
+
+	chunks := chunk(data)
+	if got := bytes.Join(chunks, nil); !bytes.Equal(got, data) {
+		t.Fatalf("Chunks did not reproduce the input")
+	}
+	for i, ch := range chunks {
+		if len(ch) > 8192 || (len(ch) < 512 && i != len(chunks)-1) {
+			t.Errorf("chunk %d has length %d; want 512 to 8192", i, len(ch))
+		}
+	}
+	if n := len(chunks); n < len(data)/8192 || n > len(data)/512 {
+		t.Errorf("Chunks produced %d chunks for %d bytes", n, len(data))
+	}
+	seq, first := c.Chunks(data[:5000]), 0
+	for range seq {
+		first++
+	}
+	second := 0
+	for range seq {
+		second++
+	}
+	if first == 0 || second != first {
+		t.Errorf("Chunks(5000 bytes) ranged twice gave %d then %d chunks; want the same nonzero count", first, second)
+	}
+
+	edited := append([]byte("inserted"), data...)
+	shared := map[string]bool{}
+	for _, ch := range chunks {
+		shared[string(ch)] = true
+	}
+	same := 0
+	for _, ch := range chunk(edited) {
+		if shared[string(ch)] {
+			same++
+		}
+	}
+	if same < len(chunks)-2 {
+		t.Errorf("after insertion %d of %d chunks unchanged; want all but 2", same, len(chunks))
+	}
+}
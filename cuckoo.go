@@ -0,0 +1,19 @@
+package swar
+
+import "math/bits"
+
+// BucketMatch sets the high bit of each slot of an 8-slot cuckoo filter bucket holding fp
+// One comparison checks the whole bucket; nonzero means the fingerprint may be present
+func BucketMatch(bucket uint64, fp byte) uint64 {
+	return HighBitWhereEqual(bucket, Dupe(fp))
+}
+
+// FindEmptySlot returns the index of the first zero slot of bucket, or -1 if it is full
+// Zero marks empty, so fingerprints must be nonzero; the borrow trick is exact for the first zero
+func FindEmptySlot(bucket uint64) int {
+	zeros := (bucket - LowBits) &^ bucket & HighBits
+	if zeros == 0 {
+		return -1
+	}
+	return bits.TrailingZeros64(zeros) >> 3
+}
@@ -0,0 +1,54 @@
+package swar
+
+import "testing"
+
+// TestBucketMatch checks the match mask against a per-slot comparison for buckets with
+// repeated, missing and high-bit fingerprints, including empty slots that must not match.
+func TestBucketMatch(t *testing.T) {
+	run := func(slots [8]byte, fp byte) {
+		var want uint64
+		for i, c := range slots {
+			if c == fp {
+				want |= 0x80 << (8 * i)
+			}
+		}
+		if got := BucketMatch(LanesToInt(slots), fp); got != want {
+			t.Errorf("BucketMatch(%v, %d) = 0x%016x; want 0x%016x", slots, fp, got, want)
+		}
+	}
+
+	run([8]byte{1, 2, 3, 4, 5, 6, 7, 8}, 5)
+	run([8]byte{9, 9, 0, 9, 0, 0, 0, 9}, 9)
+	run([8]byte{0x80, 0x7F, 0xFF, 0x01, 0x80, 0, 0, 0}, 0x80)
+	run([8]byte{1, 2, 3, 4, 5, 6, 7, 8}, 42)
+}
+
+// TestFindEmptySlot checks every empty position, with fingerprints of 1 right after the
+// empty slot, which the borrow from the zero also flags, so only the first flag is exact.
+func TestFindEmptySlot(t *testing.T) {
+	run := func(slots [8]byte) {
+		want := -1
+		for i, c := range slots {
+			if c == 0 {
+				want = i
+				break
+			}
+		}
+		if got := FindEmptySlot(LanesToInt(slots)); got != want {
+			t.Errorf("FindEmptySlot(%v) = %d; want %d", slots, got, want)
+		}
+	}
+
+	run([8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	run([8]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})
+	run([8]byte{})
+	for i := range 8 {
+		slots := [8]byte{1, 1, 1, 1, 1, 1, 1, 1}
+		slots[i] = 0
+		run(slots)
+		slots = [8]byte{0x80, 1, 0x80, 1, 0x80, 1, 0x80, 1}
+		slots[i] = 0
+		run(slots)
+	}
+	run([8]byte{7, 0, 1, 0, 1, 1, 0, 0})
+}
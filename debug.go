@@ -0,0 +1,71 @@
+package swar
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// FormatLane renders v as a table with one column per byte in memory order
+// Rows show the byte index, hex, decimal and binary value, so carries between bytes stand out
+func FormatLane(v uint64) string {
+	lane := IntToLanes(v)
+	var sb strings.Builder
+	sb.WriteString("byte")
+	for i := range lane {
+		fmt.Fprintf(&sb, " %8d", i)
+	}
+	for _, row := range [...]struct{ label, format string }{
+		{"\nhex ", " %8.2x"},
+		{"\ndec ", " %8d"},
+		{"\nbin ", " %08b"},
+	} {
+		sb.WriteString(row.label)
+		for _, c := range lane {
+			fmt.Fprintf(&sb, row.format, c)
+		}
+	}
+	sb.WriteByte('\n')
+	return sb.String()
+}
+
+// FormatMask renders a HighBitWhere* style mask as one mark per byte in memory order
+// '1' is a set high bit, '.' a clear byte and '?' a byte with only low bits set, which usually means a bug
+func FormatMask(m uint64) string {
+	var marks [8]byte
+	for i := range marks {
+		switch c := byte(m >> (8 * i)); {
+		case c&0x80 != 0:
+			marks[i] = '1'
+		case c != 0:
+			marks[i] = '?'
+		default:
+			marks[i] = '.'
+		}
+	}
+	return fmt.Sprintf("%s 0x%016x", marks[:], m)
+}
+
+// DumpChunks writes b to w one lane per line with its offset, bytes, text and lane value
+// The final partial lane is shown zero padded, exactly as loadLane presents it to a kernel
+func DumpChunks(w io.Writer, b []byte) error {
+	for i := 0; i < len(b); i += 8 {
+		v := loadLane(b, i)
+		var text [8]byte
+		for k := range text {
+			c := byte(v >> (8 * k))
+			switch {
+			case i+k >= len(b):
+				text[k] = ' '
+			case c < ' ' || c > '~':
+				text[k] = '.'
+			default:
+				text[k] = c
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%08x  % x  |%s|  0x%016x\n", i, IntToLanes(v), text[:], v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
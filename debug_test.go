@@ -0,0 +1,60 @@
+package swar
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// TestFormatLane pins the column layout so byte 0 is always the leftmost column, the
+// opposite of how the same value prints with %x.
+func TestFormatLane(t *testing.T) {
+	want := "" +
+		"byte        0        1        2        3        4        5        6        7\n" +
+		"hex        34       12       ff       00       41       7f       01       80\n" +
+		"dec        52       18      255        0       65      127        1      128\n" +
+		"bin  00110100 00010010 11111111 00000000 01000001 01111111 00000001 10000000\n"
+	if got := FormatLane(0x8001_7F41_00FF_1234); got != want {
+		t.Errorf("FormatLane(0x8001_7F41_00FF_1234) =\n%s; want\n%s", got, want)
+	}
+}
+
+// TestFormatMask checks that set high bits, clear bytes and stray low bits each get their
+// own mark, since stray bits are the usual symptom of a missing &HighBits.
+func TestFormatMask(t *testing.T) {
+	run := func(m uint64, want string) {
+		if got := FormatMask(m); got != want {
+			t.Errorf("FormatMask(0x%016x) = %q; want %q", m, got, want)
+		}
+	}
+
+	run(0, "........ 0x0000000000000000")
+	run(HighBits, "11111111 0x8080808080808080")
+	run(0x8000_0100_0000_0080, "1....?.1 0x8000010000000080")
+	run(HighBitWhereEqual(LanesToInt([8]byte{'a', ',', 'b', ','}), Dupe(',')), ".1.1.... 0x0000000080008000")
+}
+
+// TestDumpChunks checks the lane-per-line dump, including a zero padded final lane whose
+// padding is left blank in the text column, and that write errors are returned.
+func TestDumpChunks(t *testing.T) {
+	var buf bytes.Buffer
+	if err := DumpChunks(&buf, []byte("Hello, world!\x00\x01")); err != nil {
+		t.Fatalf("DumpChunks() error = %v", err)
+	}
+	want := "" +
+		"00000000  48 65 6c 6c 6f 2c 20 77  |Hello, w|  0x77202c6f6c6c6548\n" +
+		"00000008  6f 72 6c 64 21 00 01 00  |orld!.. |  0x00010021646c726f\n"
+	if got := buf.String(); got != want {
+		t.Errorf("DumpChunks() =\n%s; want\n%s", got, want)
+	}
+
+	errWrite := errors.New("write failed")
+	if err := DumpChunks(failingWriter{errWrite}, []byte("x")); err != errWrite {
+		t.Errorf("DumpChunks(failing writer) error = %v; want %v", err, errWrite)
+	}
+}
+
+// failingWriter returns err from every Write
+type failingWriter struct{ err error }
+
+func (w failingWriter) Write([]byte) (int, error) { return 0, w.err }
@@ -0,0 +1,23 @@
+// Package swar processes byte slices eight bytes at a time using SIMD Within A Register
+//
+// A lane is a uint64 holding eight bytes in memory order: byte i of a lane is the byte at
+// offset i of the slice it was loaded from, as a little-endian load produces. Comparison
+// functions such as HighBitWhereEqual report results in the high bit (0x80) of each byte,
+// and the Select*, Masked* and Extract* functions consume masks in that form.
+//
+// # Overlapping slices
+//
+// Functions that write a dst slice computed from one or more source slices accept any
+// overlap between them, with the same result as if every source were copied first:
+//
+//   - Full overlap, where dst and src start at the same address, is in-place processing.
+//     Functions whose output can run ahead of their input, such as EncodeHex and
+//     RLEDecode, read from a copy of src instead.
+//   - Partial overlap is handled like memmove. One-for-one transforms walk their lanes
+//     forwards or backwards as the direction of the overlap requires; functions that
+//     compact or expand their input copy the source first when the overlap could let an
+//     early write clobber bytes that have not been read yet.
+//
+// Functions whose dst and src have different element types, such as ThresholdToBitmap and
+// DecodeUvarint64s, must not be given overlapping memory.
+package swar
@@ -0,0 +1,65 @@
+package swar
+
+// EMA8 tracks eight independent exponential moving averages, one per byte of a lane
+// Averages are kept in 8.7 fixed point so small steps are not lost to rounding
+type EMA8 struct {
+	even, odd uint64 // 16-bit slots for bytes 0,2,4,6 and 1,3,5,7
+	shift     uint
+}
+
+// emaSlots masks the 15 bits of each 16-bit slot that hold an 8.7 fixed-point average
+const emaSlots = 0x7FFF_7FFF_7FFF_7FFF
+
+// NewEMA8 returns averages starting at initial with smoothing factor alpha = 1/2^shift
+// shift 1 follows samples closely, larger shifts smooth more; shifts above 7 act as 7
+func NewEMA8(shift uint, initial uint64) *EMA8 {
+	e := &EMA8{shift: min(shift, 7)}
+	e.Reset(initial)
+	return e
+}
+
+// Reset sets every average to the corresponding byte of v
+func (e *EMA8) Reset(v uint64) {
+	e.even, e.odd = (v&mEven)<<7, (v>>8&mEven)<<7
+}
+
+// ResetWhere sets the averages to the bytes of sample where mask has the high bit set
+// Lets a detector re-baseline only the channels that just jumped; other mask bits are ignored
+func (e *EMA8) ResetWhere(sample, mask uint64) {
+	even := (mask >> 7 & LowBits & mEven) * 0xFFFF
+	odd := (mask >> 15 & LowBits & mEven) * 0xFFFF
+	e.even = e.even&^even | (sample&mEven)<<7&even
+	e.odd = e.odd&^odd | (sample>>8&mEven)<<7&odd
+}
+
+// Update moves every average towards the corresponding byte of sample and returns Value
+// Each average gains (sample - average) / 2^shift, computed without leaving its slot
+func (e *EMA8) Update(sample uint64) uint64 {
+	e.even = emaStep(e.even, (sample&mEven)<<7, e.shift)
+	e.odd = emaStep(e.odd, (sample>>8&mEven)<<7, e.shift)
+	return e.Value()
+}
+
+// Value returns the averages rounded to the nearest byte
+func (e *EMA8) Value() uint64 {
+	round := 0x0040 * slots16
+	return (e.even+round)>>7&mEven | (e.odd+round)>>7&mEven<<8
+}
+
+// Deviation returns how far each byte of sample is from its current average
+// Compare with a threshold via HighBitWhereGreater to flag outliers
+func (e *EMA8) Deviation(sample uint64) uint64 {
+	return AbsoluteDifferenceBetweenBytes(sample, e.Value())
+}
+
+// emaStep moves the 15-bit slots of avg towards target by their difference >> shift
+// Setting bit 15 before subtracting keeps each difference inside its slot
+func emaStep(avg, target uint64, shift uint) uint64 {
+	up := (target | 0x8000*slots16) - avg
+	down := (avg | 0x8000*slots16) - target
+	upMask := up >> 15 & slots16 * 0xFFFF
+	downMask := down >> 15 & slots16 * 0xFFFF
+	// Shifting pulls in bits from the slot above; keep only what can belong to this slot
+	keep := 0x7FFF >> shift * slots16
+	return avg + (up&upMask&emaSlots)>>shift&keep - (down&downMask&emaSlots)>>shift&keep
+}
@@ -0,0 +1,71 @@
+package swar
+
+import (
+	"testing"
+)
+
+// emaReference mirrors EMA8 for one channel with plain integers in 8.7 fixed point.
+type emaReference struct {
+	avg   int
+	shift uint
+}
+
+// update applies one step and returns the rounded average.
+func (r *emaReference) update(sample byte) byte {
+	target := int(sample) << 7
+	if target >= r.avg {
+		r.avg += (target - r.avg) >> r.shift
+	} else {
+		r.avg -= (r.avg - target) >> r.shift
+	}
+	return byte((r.avg + 0x40) >> 7)
+}
+
+// TestEMA8 drives all eight channels with different signals, including full-scale jumps,
+// and compares every step with a scalar fixed-point model so channels never interfere.
+func TestEMA8(t *testing.T) {
+	for shift := uint(0); shift < 8; shift++ {
+		initial := uint64(0xFF00_80FF_0001_7F80)
+		e := NewEMA8(shift, initial)
+		var refs [8]emaReference
+		for i, c := range IntToLanes(initial) {
+			refs[i] = emaReference{int(c) << 7, shift}
+		}
+		for step := range 300 {
+			var sample [8]byte
+			for i := range sample {
+				sample[i] = byte(step*(i+1)*37 + i)
+				if step%(i+5) == 0 {
+					sample[i] = 0xFF * byte(step&1)
+				}
+			}
+			var want [8]byte
+			for i := range want {
+				want[i] = refs[i].update(sample[i])
+			}
+			if got := e.Update(LanesToInt(sample)); got != LanesToInt(want) {
+				t.Fatalf("EMA8(shift %d) step %d = 0x%016x; want 0x%016x", shift, step, got, LanesToInt(want))
+			}
+			if got, want := e.Deviation(LanesToInt(sample)), AbsoluteDifferenceBetweenBytes(LanesToInt(sample), LanesToInt(want)); got != want {
+				t.Fatalf("EMA8(shift %d).Deviation step %d = 0x%016x; want 0x%016x", shift, step, got, want)
+			}
+		}
+	}
+}
+
+// TestEMA8ResetWhere checks that only channels selected by the mask's high bits are
+// re-baselined, and that stray low bits in the mask cannot leak into neighbouring slots.
+func TestEMA8ResetWhere(t *testing.T) {
+	run := func(initial, sample, mask, want uint64) {
+		e := NewEMA8(3, initial)
+		if e.ResetWhere(sample, mask); e.Value() != want {
+			t.Errorf("NewEMA8(3, 0x%016x).ResetWhere(0x%016x, 0x%016x) Value = 0x%016x; want 0x%016x",
+				initial, sample, mask, e.Value(), want)
+		}
+	}
+
+	run(Dupe(10), 0x0102_0304_0506_0708, 0x8000_0080_0000_8000, 0x010A_0A04_0A0A_070A)
+	run(Dupe(10), 0x0102_0304_0506_0708, 0xFF7F_01FF_7F00_FF01, 0x010A_0A04_0A0A_070A)
+	run(Dupe(0x11), 0x10000, 0x7070_7070_7070_7070, Dupe(0x11))
+	run(Dupe(0x11), 0x10000, 0x7F7F_7F7F_7F7F_7F7F, Dupe(0x11))
+}
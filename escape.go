@@ -0,0 +1,69 @@
+package swar
+
+// unreservedChars matches the RFC 3986 unreserved set: ALPHA DIGIT - . _ ~
+var unreservedChars = NewRangeMatcher(
+	[2]byte{'-', '.'}, [2]byte{'0', '9'}, [2]byte{'A', 'Z'}, [2]byte{'_', '_'}, [2]byte{'a', 'z'}, [2]byte{'~', '~'},
+)
+
+// HighBitWhereNotUnreserved sets the high bit (0x80) in each byte outside the RFC 3986
+// unreserved set, i.e. every byte a strict URI encoder would percent-escape
+func HighBitWhereNotUnreserved(v uint64) uint64 {
+	return unreservedChars.Mask(v) ^ HighBits
+}
+
+// NeedsPercentEscape reports whether b contains a byte outside the unreserved set
+// Also returns the index of the first such byte, or -1, so clean prefixes can be copied
+func NeedsPercentEscape(b []byte) (bool, int) {
+	i := indexMasked(b, HighBitWhereNotUnreserved)
+	return i >= 0, i
+}
+
+// IndexHTMLEscapable returns the index of the first byte html.EscapeString would
+// replace (& < > " '), or -1 so template engines can copy clean runs untouched
+func IndexHTMLEscapable(b []byte) int {
+	return indexMasked(b, htmlEscapableMask)
+}
+
+// htmlEscapableMask sets the high bit of every & < > " and ' byte in one fused pass
+func htmlEscapableMask(v uint64) uint64 {
+	return HighBitWhereEqual(v, Dupe('&')) | HighBitWhereEqual(v, Dupe('<')) |
+		HighBitWhereEqual(v, Dupe('>')) | HighBitWhereEqual(v, Dupe('"')) |
+		HighBitWhereEqual(v, Dupe('\''))
+}
+
+// ControlAllowance selects control bytes that are permitted by the control byte scans
+// Combine the flags with |, e.g. AllowTab|AllowLF
+type ControlAllowance uint8
+
+const (
+	// AllowTab permits horizontal tab (0x09)
+	AllowTab ControlAllowance = 1 << iota
+	// AllowLF permits line feed (0x0A)
+	AllowLF
+	// AllowCR permits carriage return (0x0D)
+	AllowCR
+)
+
+// ContainsControlBytes reports whether b holds a byte below 0x20, including NUL,
+// other than those permitted by allow; header-injection checks use AllowTab
+func ContainsControlBytes(b []byte, allow ControlAllowance) bool {
+	return IndexControlByte(b, allow) >= 0
+}
+
+// IndexControlByte returns the index of the first byte below 0x20 not permitted by
+// allow, or -1 if there is none
+func IndexControlByte(b []byte, allow ControlAllowance) int {
+	return indexMasked(b, func(v uint64) uint64 {
+		m := HighBitWhereLess(v, Dupe(0x20))
+		if allow&AllowTab != 0 {
+			m &^= HighBitWhereEqual(v, Dupe('\t'))
+		}
+		if allow&AllowLF != 0 {
+			m &^= HighBitWhereEqual(v, Dupe('\n'))
+		}
+		if allow&AllowCR != 0 {
+			m &^= HighBitWhereEqual(v, Dupe('\r'))
+		}
+		return m
+	})
+}
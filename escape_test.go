@@ -0,0 +1,90 @@
+package swar
+
+import (
+	"html"
+	"net/url"
+	"testing"
+)
+
+// TestHighBitWhereNotUnreserved verifies the classifier for every byte value against the
+// escaping net/url.QueryEscape performs on each single byte.
+func TestHighBitWhereNotUnreserved(t *testing.T) {
+	for c := 0; c < 256; c++ {
+		s := string([]byte{byte(c)})
+		want := uint64(0)
+		if url.QueryEscape(s) != s {
+			want = HighBits
+		}
+		if got := HighBitWhereNotUnreserved(Dupe(byte(c))); got != want {
+			t.Errorf("HighBitWhereNotUnreserved(%q) = 0x%016x; want 0x%016x", c, got, want)
+		}
+	}
+}
+
+// TestNeedsPercentEscape verifies that clean strings take the fast path and that the
+// first byte needing an escape is reported at every position.
+func TestNeedsPercentEscape(t *testing.T) {
+	run := func(b string, want bool, wantIndex int) {
+		if got, index := NeedsPercentEscape([]byte(b)); got != want || index != wantIndex {
+			t.Errorf("NeedsPercentEscape(%q) = %v, %d; want %v, %d", b, got, index, want, wantIndex)
+		}
+	}
+
+	run("", false, -1)
+	run("simple-path_segment.v2~", false, -1)
+	run("hello world", true, 5)
+	run("abcdefgh/ijk", true, 8)
+	run("caf\xc3\xa9", true, 3)
+	run("query=1&x=2", true, 5)
+}
+
+// TestIndexHTMLEscapable verifies that the first byte changed by html.EscapeString is
+// found for every byte value at several positions, so no escapable character is missed.
+func TestIndexHTMLEscapable(t *testing.T) {
+	run := func(b string) {
+		want := -1
+		for i := range b {
+			if html.EscapeString(b[i:i+1]) != b[i:i+1] {
+				want = i
+				break
+			}
+		}
+		if got := IndexHTMLEscapable([]byte(b)); got != want {
+			t.Errorf("IndexHTMLEscapable(%q) = %d; want %d", b, got, want)
+		}
+	}
+
+	run("")
+	run("<p>")
+	run("plain text with no markup at all")
+	for c := 0; c < 256; c++ {
+		for _, prefix := range []string{"", "abc", "abcdefgh", "abcdefghijklm"} {
+			run(prefix + string([]byte{byte(c)}) + "tail")
+		}
+	}
+}
+
+// TestIndexControlByte verifies control byte detection for every byte value under every
+// combination of allowances. NUL must always be caught, and input shorter than a lane
+// must not report the zero padding as a control byte.
+func TestIndexControlByte(t *testing.T) {
+	for allow := ControlAllowance(0); allow <= AllowTab|AllowLF|AllowCR; allow++ {
+		for c := 0; c < 256; c++ {
+			permitted := (c == '\t' && allow&AllowTab != 0) || (c == '\n' && allow&AllowLF != 0) || (c == '\r' && allow&AllowCR != 0)
+			want := -1
+			if c < 0x20 && !permitted {
+				want = 9
+			}
+			b := []byte("Header: " + "v" + string([]byte{byte(c)}) + "alue")
+			if got := IndexControlByte(b, allow); got != want {
+				t.Errorf("IndexControlByte(%q, %03b) = %d; want %d", b, allow, got, want)
+			}
+			if got := ContainsControlBytes(b, allow); got != (want >= 0) {
+				t.Errorf("ContainsControlBytes(%q, %03b) = %v; want %v", b, allow, got, want >= 0)
+			}
+		}
+	}
+	if got := IndexControlByte([]byte("ok"), 0); got != -1 {
+		t.Errorf("IndexControlByte(%q, 0) = %d; want -1", "ok", got)
+	}
+}
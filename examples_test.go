@@ -2,12 +2,18 @@ package swar
 
 import (
 	"bytes"
+	"fmt"
+	"iter"
 	"math/bits"
+	"slices"
 	"testing"
+
+	"github.com/dans-stuff/swar/check"
+	"github.com/dans-stuff/swar/ref"
 )
 
 // This file contains examples of how to use SWAR.
-// It also includes reference implementations to show what each operation is doing.
+// Scalar reference implementations of each operation live in the ref subpackage.
 
 // Sample text for benchmark tests - contains spaces and mixed case for testing
 var lotsOfBytes = []byte("Allo Zorld! I am NOT yelling, but I am using SWAR!")
@@ -87,9 +93,9 @@ func BenchmarkUsageVisitCaps(b *testing.B) {
 			for idx, chunk := range chunks {
 				caps := HighBitWhereGreater(chunk, firstCapital) & HighBitWhereLess(chunk, lastCapital)
 				matches := ExtractLowBits(caps >> 7)
-				offsets := Lookup.OnesPositions[matches]
+				offsets := Lookup.OnesPositions[matches][:Lookup.OnesCount[matches]]
 				for _, v := range offsets {
-					sum += v + idx*8
+					sum += int(v) + idx*8
 				}
 			}
 
@@ -242,594 +248,705 @@ func toBytes(v uint64) [8]byte {
 	return b
 }
 
-func fromBytes(b [8]byte) uint64 {
-	var v uint64
-	for i := 0; i < 8; i++ {
-		shift := uint((7 - i) * 8)
-		v |= uint64(b[i]) << shift
+// TestSWARFunctionsRef compares the lane primitives with the scalar versions in package ref
+// using the drivers in package check, so each SWAR trick is checked against the obvious loop.
+// BCD and selection masks are restricted to the inputs those functions are defined for.
+// The slice-level API is then compared with its mirrors on the strings from refInputs.
+func TestSWARFunctionsRef(t *testing.T) {
+	// The adaptors fold extra results and carries into the returned lane and steer inputs
+	// towards the bytes each function cares about, such as digits, quotes and backslashes.
+	digits := func(f func(uint64) (uint32, bool)) func(uint64) uint64 {
+		return func(v uint64) uint64 {
+			n, ok := f(fromAlphabet(v, "0123456789/:0123456789"))
+			return uint64(n) | boolBit(ok, 63)
+		}
+	}
+	hexCase := func(f func(uint64) (uint64, uint64)) func(uint64) uint64 {
+		return func(v uint64) uint64 {
+			upper, lower := f(v)
+			return upper | lower>>1
+		}
+	}
+	escaped := func(f func(uint64, *bool) uint64) func(uint64) uint64 {
+		return func(v uint64) uint64 {
+			carry := v&1 != 0
+			return f(fromAlphabet(v, `\\"a`), &carry) | boolBit(carry, 0)
+		}
+	}
+	quoted := func(f func(uint64, *bool) uint64) func(uint64) uint64 {
+		return func(v uint64) uint64 {
+			inQuote := v&1 != 0
+			return f(v&HighBits, &inQuote) | boolBit(inQuote, 0)
+		}
+	}
+	letters := [][2]byte{{'a', 'z'}, {0, 0x1F}, {0xF0, 0xFF}, {9, 3}}
+	var xorTable [256]byte
+	for c := range xorTable {
+		xorTable[c] = byte(c) ^ 0x5A
+	}
+
+	unary := []struct {
+		name   string
+		f, ref func(uint64) uint64
+	}{
+		{"SwapByteHalves", SwapByteHalves, ref.SwapByteHalves},
+		{"ReverseEachByte", ReverseEachByte, ref.ReverseEachByte},
+		{"CountOnesPerByte", CountOnesPerByte, ref.CountOnesPerByte},
+		{"PrefixSumBytesWrapping", PrefixSumBytesWrapping, ref.PrefixSumBytesWrapping},
+		{"PrefixSumBytesSaturating", PrefixSumBytesSaturating, ref.PrefixSumBytesSaturating},
+		{"ZigZagEncodeBytes", ZigZagEncodeBytes, ref.ZigZagEncodeBytes},
+		{"ZigZagDecodeBytes", ZigZagDecodeBytes, ref.ZigZagDecodeBytes},
+		{"SumBytes", SumBytes, ref.SumBytes},
+		{"NegateBytes", NegateBytes, ref.NegateBytes},
+		{"SortBytesInLane", SortBytesInLane, ref.SortBytesInLane},
+		{"PrefixXorBits", PrefixXorBits, ref.PrefixXorBits},
+		{"ExtractLowBits", func(v uint64) uint64 { return uint64(ExtractLowBits(v & LowBits)) }, func(v uint64) uint64 { return uint64(ref.ExtractLowBits(v & LowBits)) }},
+		{"ExtractLowBitsMSB0", func(v uint64) uint64 { return uint64(ExtractLowBitsMSB0(v & LowBits)) }, func(v uint64) uint64 { return uint64(ref.ExtractLowBitsMSB0(v & LowBits)) }},
+		{"ExtractHighBits", func(v uint64) uint64 { return uint64(ExtractHighBits(v)) }, func(v uint64) uint64 { return uint64(ref.ExtractHighBits(v)) }},
+		{"ExtractHighBitsMSB0", func(v uint64) uint64 { return uint64(ExtractHighBitsMSB0(v)) }, func(v uint64) uint64 { return uint64(ref.ExtractHighBitsMSB0(v)) }},
+		{"SpreadBitsToLanes", func(v uint64) uint64 { return SpreadBitsToLanes(byte(v)) }, func(v uint64) uint64 { return ref.SpreadBitsToLanes(byte(v)) }},
+		{"SpreadBitsToHighBits", func(v uint64) uint64 { return SpreadBitsToHighBits(byte(v)) }, func(v uint64) uint64 { return ref.SpreadBitsToHighBits(byte(v)) }},
+		{"ScaleBytes", func(v uint64) uint64 { return ScaleBytes(v, byte(v>>13)) }, func(v uint64) uint64 { return ref.ScaleBytes(v, byte(v>>13)) }},
+		{"RotateLane", func(v uint64) uint64 { return RotateLane(v, int(v%11)-3) }, func(v uint64) uint64 { return ref.RotateLane(v, int(v%11)-3) }},
+		{"ExtractLowBitsLSB0", func(v uint64) uint64 { return uint64(ExtractLowBitsLSB0(v & LowBits)) }, func(v uint64) uint64 { return uint64(ref.ExtractLowBitsLSB0(v & LowBits)) }},
+		{"ExtractHighBitsLSB0", func(v uint64) uint64 { return uint64(ExtractHighBitsLSB0(v)) }, func(v uint64) uint64 { return uint64(ref.ExtractHighBitsLSB0(v)) }},
+		{"Dupe", func(v uint64) uint64 { return Dupe(byte(v)) }, func(v uint64) uint64 { return ref.Dupe(byte(v)) }},
+		{"Dupe2", func(v uint64) uint64 { return Dupe2(uint16(v)) }, func(v uint64) uint64 { return ref.Dupe2(uint16(v)) }},
+		{"Dupe4", func(v uint64) uint64 { return Dupe4(uint32(v)) }, func(v uint64) uint64 { return ref.Dupe4(uint32(v)) }},
+		{"ParseEightDigits", digits(ParseEightDigits), digits(ref.ParseEightDigits)},
+		{"HexCaseMasks", hexCase(HexCaseMasks), hexCase(ref.HexCaseMasks)},
+		{"EscapedCharMask", escaped(EscapedCharMask), escaped(ref.EscapedCharMask)},
+		{"QuoteRegionMask", quoted(QuoteRegionMask), quoted(ref.QuoteRegionMask)},
+		{"HighBitWhereTokenChar", HighBitWhereTokenChar, ref.HighBitWhereTokenChar},
+		{"HighBitWhereNotUnreserved", HighBitWhereNotUnreserved, ref.HighBitWhereNotUnreserved},
+		{"RangeMatcher", NewRangeMatcher(letters...).Mask, ref.NewRangeMatcher(letters...).Mask},
+		{"RangeMatcher/all", NewRangeMatcher([2]byte{0x80, 0xFF}, [2]byte{0, 0x7F}).Mask, ref.NewRangeMatcher([2]byte{0x80, 0xFF}, [2]byte{0, 0x7F}).Mask},
+		{"BucketMatch", func(v uint64) uint64 { return BucketMatch(v, byte(v>>13)) }, func(v uint64) uint64 { return ref.BucketMatch(v, byte(v>>13)) }},
+		{"FindEmptySlot", func(v uint64) uint64 { return uint64(FindEmptySlot(v)) }, func(v uint64) uint64 { return uint64(ref.FindEmptySlot(v)) }},
+		{"TableCompiler", NewTableCompiler(&xorTable).Lookup, ref.NewTableCompiler(&xorTable).Lookup},
+	}
+	binary := []struct {
+		name   string
+		f, ref func(a, b uint64) uint64
+	}{
+		{"HighBitWhereLess", HighBitWhereLess, ref.HighBitWhereLess},
+		{"HighBitWhereGreater", HighBitWhereGreater, ref.HighBitWhereGreater},
+		{"HighBitWhereEqual", HighBitWhereEqual, ref.HighBitWhereEqual},
+		{"SubtractBytesWithWrapping", SubtractBytesWithWrapping, ref.SubtractBytesWithWrapping},
+		{"SubtractBytesWithMinimum", SubtractBytesWithMinimum, ref.SubtractBytesWithMinimum},
+		{"AddBytesWithWrapping", AddBytesWithWrapping, ref.AddBytesWithWrapping},
+		{"AddBytesWithMaximum", AddBytesWithMaximum, ref.AddBytesWithMaximum},
+		{"AbsoluteDifferenceBetweenBytes", AbsoluteDifferenceBetweenBytes, ref.AbsoluteDifferenceBetweenBytes},
+		{"SelectSmallerBytes", SelectSmallerBytes, ref.SelectSmallerBytes},
+		{"SelectLargerBytes", SelectLargerBytes, ref.SelectLargerBytes},
+		{"AverageBytes", AverageBytes, ref.AverageBytes},
+		{"MultiplyBytesNormalized", MultiplyBytesNormalized, ref.MultiplyBytesNormalized},
+		{"SelectByHighBit", func(a, b uint64) uint64 { return SelectByHighBit(a, b, a^b) }, func(a, b uint64) uint64 { return ref.SelectByHighBit(a, b, a^b) }},
+		{"SelectByLowBit", func(a, b uint64) uint64 { return SelectByLowBit(a, b, (a^b)&LowBits) }, func(a, b uint64) uint64 { return ref.SelectByLowBit(a, b, (a^b)&LowBits) }},
+		{"MaskedAdd", func(a, b uint64) uint64 { return MaskedAdd(a, b, a<<3) }, func(a, b uint64) uint64 { return ref.MaskedAdd(a, b, a<<3) }},
+		{"MaskedSubtract", func(a, b uint64) uint64 { return MaskedSubtract(a, b, a<<3) }, func(a, b uint64) uint64 { return ref.MaskedSubtract(a, b, a<<3) }},
+		{"MaskedAverage", func(a, b uint64) uint64 { return MaskedAverage(a, b, a<<3) }, func(a, b uint64) uint64 { return ref.MaskedAverage(a, b, a<<3) }},
+		{"NegateBytesWhere", NegateBytesWhere, ref.NegateBytesWhere},
+		{"MedianOfThreeBytes", func(a, b uint64) uint64 { return MedianOfThreeBytes(a, b, a*b) }, func(a, b uint64) uint64 { return ref.MedianOfThreeBytes(a, b, a*b) }},
+		{"QuantizeLane", func(a, b uint64) uint64 { return QuantizeLane(a, int(b%256)+1, b&0x100 != 0) }, func(a, b uint64) uint64 { return ref.QuantizeLane(a, int(b%256)+1, b&0x100 != 0) }},
+	}
+	pairs := []struct {
+		name   string
+		f, ref func(a, b uint64) (uint64, uint64)
+	}{
+		{"SubtractBytesWithBorrowOut", SubtractBytesWithBorrowOut, ref.SubtractBytesWithBorrowOut},
+		{"AddBytesWithCarryOut", AddBytesWithCarryOut, ref.AddBytesWithCarryOut},
+		{"MultiplyBytesWidening", MultiplyBytesWidening, ref.MultiplyBytesWidening},
+		{"InterleaveBytes", InterleaveBytes, ref.InterleaveBytes},
+		{"DeinterleaveBytes", DeinterleaveBytes, ref.DeinterleaveBytes},
+		{"AddBCDBytes", func(a, b uint64) (uint64, uint64) { return AddBCDBytes(toBCD(a), toBCD(b)) }, func(a, b uint64) (uint64, uint64) { return ref.AddBCDBytes(toBCD(a), toBCD(b)) }},
+	}
+
+	for _, op := range unary {
+		if err := check.CheckLaneFunc(op.f, op.ref); err != nil {
+			t.Errorf("%s: %v", op.name, err)
+		}
+	}
+	for _, op := range binary {
+		if err := check.CheckLaneFunc2(op.f, op.ref); err != nil {
+			t.Errorf("%s: %v", op.name, err)
+		}
+	}
+	var acc, refAcc [2]uint64
+	var totals, refTotals [8]uint32
+	pending := 0
+	for n := uint64(0); n < 0x_FF_FF_FF_FF_FF; n = (n*12 + 13) / 11 {
+		m := n*0x9E37_79B9_7F4A_7C15 ^ 0x0000_0053_5195_2b76
+		lanes := [8]uint64{n, m, n ^ m, n * m, n >> 3, m >> 5, ^n, ^m}
+		for k := range lanes {
+			lanes[k] &= LowBits
+		}
+		if got, want := ExtractLowBits4([4]uint64(lanes[:4])), ref.ExtractLowBits4([4]uint64(lanes[:4])); got != want {
+			t.Errorf("ExtractLowBits4(%x) = 0x%08x; want 0x%08x", lanes[:4], got, want)
+		}
+		if got, want := ExtractLowBits8(lanes), ref.ExtractLowBits8(lanes); got != want {
+			t.Errorf("ExtractLowBits8(%x) = 0x%016x; want 0x%016x", lanes, got, want)
+		}
+		AccumulateBytesInto16(&acc[0], &acc[1], m)
+		ref.AccumulateBytesInto16(&refAcc[0], &refAcc[1], m)
+		if acc != refAcc {
+			t.Fatalf("AccumulateBytesInto16(0x%016x) = %x; want %x", m, acc, refAcc)
+		}
+		if pending++; pending == MaxAccumulations16 {
+			FlushAccumulators16(&acc[0], &acc[1], &totals)
+			ref.FlushAccumulators16(&refAcc[0], &refAcc[1], &refTotals)
+			if pending = 0; acc != refAcc || totals != refTotals {
+				t.Fatalf("FlushAccumulators16 = %x, %d; want %x, %d", acc, totals, refAcc, refTotals)
+			}
+		}
+		for _, op := range pairs {
+			got0, got1 := op.f(n, m)
+			want0, want1 := op.ref(n, m)
+			if got0 != want0 || got1 != want1 {
+				t.Errorf("%s(0x%016x, 0x%016x) = 0x%016x, 0x%016x; want 0x%016x, 0x%016x", op.name, n, m, got0, got1, want0, want1)
+			}
+		}
+		if got, want := BinaryToBCDBytes(toBCDRange(n)), ref.BinaryToBCDBytes(toBCDRange(n)); got != want {
+			t.Errorf("BinaryToBCDBytes(0x%016x) = 0x%016x; want 0x%016x", toBCDRange(n), got, want)
+		}
+		if got, want := BCDToBinaryBytes(toBCD(n)), ref.BCDToBinaryBytes(toBCD(n)); got != want {
+			t.Errorf("BCDToBinaryBytes(0x%016x) = 0x%016x; want 0x%016x", toBCD(n), got, want)
+		}
 	}
-	return v
-}
 
-func byteFromLowBits(b [8]byte) uint8 {
-	var out uint8
-	if b[0]&1 != 0 {
-		out |= 1 << 7
-	}
-	if b[1]&1 != 0 {
-		out |= 1 << 6
-	}
-	if b[2]&1 != 0 {
-		out |= 1 << 5
-	}
-	if b[3]&1 != 0 {
-		out |= 1 << 4
+	// Slice-level functions are compared by their printed results, so slices of equal
+	// contents match whether nil or empty and errors match by message.
+	same := func(n int) int { return n }
+	into := func(size func(int) int, f func(dst, src []byte)) func([]byte) string {
+		return func(b []byte) string {
+			dst := make([]byte, size(len(b)))
+			f(dst, b)
+			return fmt.Sprint(dst)
+		}
 	}
-	if b[4]&1 != 0 {
-		out |= 1 << 3
+	intoN := func(f func(dst, src []byte) int) func([]byte) string {
+		return func(b []byte) string {
+			dst := make([]byte, len(b))
+			n := f(dst, b)
+			return fmt.Sprint(dst[:n], n)
+		}
 	}
-	if b[5]&1 != 0 {
-		out |= 1 << 2
+	inPlace := func(f func(b []byte)) func([]byte) string {
+		return func(b []byte) string {
+			b = bytes.Clone(b)
+			f(b)
+			return fmt.Sprint(b)
+		}
 	}
-	if b[6]&1 != 0 {
-		out |= 1 << 1
+	halves := func(f func(dst, src []byte)) func([]byte) string {
+		return func(b []byte) string {
+			dst := bytes.Clone(b[:len(b)/2])
+			f(dst, b[len(b)/2:])
+			return fmt.Sprint(dst)
+		}
 	}
-	if b[7]&1 != 0 {
-		out |= 1 << 0
+	pieces := func(seq func([]byte) iter.Seq[[]byte]) func([]byte) string {
+		return func(b []byte) string {
+			var out []string
+			for piece := range seq(b) {
+				out = append(out, string(piece))
+			}
+			return fmt.Sprintf("%q", out)
+		}
+	}
+	combined := func(f func(dst, a, b []uint64) int) func([]byte) string {
+		return func(b []byte) string {
+			w := toWords(b)
+			dst := make([]uint64, len(w))
+			n := f(dst, w[:len(w)/2], w[len(w)/2:])
+			return fmt.Sprint(dst[:n], n)
+		}
+	}
+	var dither [64]byte
+	for i := range dither {
+		dither[i] = byte(i * 37 % 64)
+	}
+	key := [8]byte{0x12, 0x34, 0x56, 0x78, 0x9A, 0xBC, 0xDE, 0xF0}
+	funcs := []struct {
+		name   string
+		f, ref func(b []byte) string
+	}{
+		{"IsASCII", func(b []byte) string { return fmt.Sprint(IsASCII(b)) }, func(b []byte) string { return fmt.Sprint(ref.IsASCII(b)) }},
+		{"IndexNonASCII", func(b []byte) string { return fmt.Sprint(IndexNonASCII(b)) }, func(b []byte) string { return fmt.Sprint(ref.IndexNonASCII(b)) }},
+		{"CountRunes", func(b []byte) string { return fmt.Sprint(CountRunes(b)) }, func(b []byte) string { return fmt.Sprint(ref.CountRunes(b)) }},
+		{"ToUpperASCII", into(same, ToUpperASCII), into(same, ref.ToUpperASCII)},
+		{"ToLowerASCII", into(same, ToLowerASCII), into(same, ref.ToLowerASCII)},
+		{"SwapCaseASCII", into(same, SwapCaseASCII), into(same, ref.SwapCaseASCII)},
+		{"ToUpperASCIIInPlace", inPlace(ToUpperASCIIInPlace), inPlace(ref.ToUpperASCIIInPlace)},
+		{"ToLowerASCIIInPlace", inPlace(ToLowerASCIIInPlace), inPlace(ref.ToLowerASCIIInPlace)},
+		{"TitleCaseASCII", into(same, TitleCaseASCII), into(same, ref.TitleCaseASCII)},
+		{"Rot13", into(same, Rot13), into(same, ref.Rot13)},
+		{"XORMask", into(same, func(d, s []byte) { XORMask(d, s, key) }), into(same, func(d, s []byte) { ref.XORMask(d, s, key) })},
+		{"XORMask4", into(same, func(d, s []byte) { XORMask4(d, s, [4]byte(key[:4])) }), into(same, func(d, s []byte) { ref.XORMask4(d, s, [4]byte(key[:4])) })},
+		{"UnmaskWebSocket", inPlace(func(b []byte) { UnmaskWebSocket(b, [4]byte(key[4:]), len(b)%9-4) }), inPlace(func(b []byte) { ref.UnmaskWebSocket(b, [4]byte(key[4:]), len(b)%9-4) })},
+		{"CountLines", func(b []byte) string { return fmt.Sprint(CountLines(b)) }, func(b []byte) string { return fmt.Sprint(ref.CountLines(b)) }},
+		{"Lines", pieces(Lines), pieces(ref.Lines)},
+		{"LinesCRLF", pieces(LinesCRLF), pieces(ref.LinesCRLF)},
+		{"NormalizeNewlines", intoN(NormalizeNewlines), intoN(ref.NormalizeNewlines)},
+		{"NormalizeAllNewlines", intoN(NormalizeAllNewlines), intoN(ref.NormalizeAllNewlines)},
+		{"SplitIter", pieces(func(b []byte) iter.Seq[[]byte] { return SplitIter(b, ' ') }), pieces(func(b []byte) iter.Seq[[]byte] { return ref.SplitIter(b, ' ') })},
+		{"Fields", pieces(Fields), pieces(ref.Fields)},
+		{"ParseUint", func(b []byte) string { return fmt.Sprint(ParseUint(b)) }, func(b []byte) string { return fmt.Sprint(ref.ParseUint(b)) }},
+		{"ParseIPv4", func(b []byte) string { return fmt.Sprint(ParseIPv4(b)) }, func(b []byte) string { return fmt.Sprint(ref.ParseIPv4(b)) }},
+		{"ParseDateYYYYMMDD", func(b []byte) string { return fmt.Sprint(ParseDateYYYYMMDD(b)) }, func(b []byte) string { return fmt.Sprint(ref.ParseDateYYYYMMDD(b)) }},
+		{"ParseTimeHHMMSS", func(b []byte) string { return fmt.Sprint(ParseTimeHHMMSS(b)) }, func(b []byte) string { return fmt.Sprint(ref.ParseTimeHHMMSS(b)) }},
+		{"ValidateUUID", func(b []byte) string { return fmt.Sprint(ValidateUUID(b)) }, func(b []byte) string { return fmt.Sprint(ref.ValidateUUID(b)) }},
+		{"ParseUUID", func(b []byte) string { return fmt.Sprint(ParseUUID(b)) }, func(b []byte) string { return fmt.Sprint(ref.ParseUUID(b)) }},
+		{"EncodeHex", func(b []byte) string {
+			dst := make([]byte, 2*len(b))
+			return fmt.Sprint(EncodeHex(dst, b), dst)
+		}, func(b []byte) string {
+			dst := make([]byte, 2*len(b))
+			return fmt.Sprint(ref.EncodeHex(dst, b), dst)
+		}},
+		{"DecodeHex", func(b []byte) string {
+			dst := make([]byte, len(b)/2)
+			n, err := DecodeHex(dst, b)
+			return fmt.Sprint(dst[:n], err)
+		}, func(b []byte) string {
+			dst := make([]byte, len(b)/2)
+			n, err := ref.DecodeHex(dst, b)
+			return fmt.Sprint(dst[:n], err)
+		}},
+		{"ValidateHex", func(b []byte) string { return fmt.Sprint(ValidateHex(b)) }, func(b []byte) string { return fmt.Sprint(ref.ValidateHex(b)) }},
+		{"NormalizeHexLower", inPlace(NormalizeHexLower), inPlace(ref.NormalizeHexLower)},
+		{"IsMixedCaseHex", func(b []byte) string { return fmt.Sprint(IsMixedCaseHex(b)) }, func(b []byte) string { return fmt.Sprint(ref.IsMixedCaseHex(b)) }},
+		{"ParseHex16", func(b []byte) string { return fmt.Sprint(ParseHex16(b)) }, func(b []byte) string { return fmt.Sprint(ref.ParseHex16(b)) }},
+		{"FormatHex16", func(b []byte) string { return fmt.Sprint(FormatHex16(HashFNV1a(b))) }, func(b []byte) string { return fmt.Sprint(ref.FormatHex16(ref.HashFNV1a(b))) }},
+		{"ValidateBase64", func(b []byte) string { return fmt.Sprint(ValidateBase64(b, true), ValidateBase64(b, false)) }, func(b []byte) string { return fmt.Sprint(ref.ValidateBase64(b, true), ref.ValidateBase64(b, false)) }},
+		{"StripBase64Whitespace", intoN(StripBase64Whitespace), intoN(ref.StripBase64Whitespace)},
+		{"IndexHeaderEnd", func(b []byte) string { return fmt.Sprint(IndexHeaderEnd(b)) }, func(b []byte) string { return fmt.Sprint(ref.IndexHeaderEnd(b)) }},
+		{"IndexCRLF", func(b []byte) string { return fmt.Sprint(IndexCRLF(b)) }, func(b []byte) string { return fmt.Sprint(ref.IndexCRLF(b)) }},
+		{"IndexColon", func(b []byte) string { return fmt.Sprint(IndexColon(b)) }, func(b []byte) string { return fmt.Sprint(ref.IndexColon(b)) }},
+		{"IsToken", func(b []byte) string { return fmt.Sprint(IsToken(b)) }, func(b []byte) string { return fmt.Sprint(ref.IsToken(b)) }},
+		{"NeedsPercentEscape", func(b []byte) string { return fmt.Sprint(NeedsPercentEscape(b)) }, func(b []byte) string { return fmt.Sprint(ref.NeedsPercentEscape(b)) }},
+		{"IndexHTMLEscapable", func(b []byte) string { return fmt.Sprint(IndexHTMLEscapable(b)) }, func(b []byte) string { return fmt.Sprint(ref.IndexHTMLEscapable(b)) }},
+		{"IndexControlByte", func(b []byte) string {
+			var out []any
+			for allow := range AllowTab | AllowLF | AllowCR + 1 {
+				out = append(out, IndexControlByte(b, allow), ContainsControlBytes(b, allow))
+			}
+			return fmt.Sprint(out)
+		}, func(b []byte) string {
+			var out []any
+			for allow := range ref.AllowTab | ref.AllowLF | ref.AllowCR + 1 {
+				out = append(out, ref.IndexControlByte(b, allow), ref.ContainsControlBytes(b, allow))
+			}
+			return fmt.Sprint(out)
+		}},
+		{"ScanJSONStructure", func(b []byte) string { return fmt.Sprint(ScanJSONStructure(b)) }, func(b []byte) string { return fmt.Sprint(ref.ScanJSONStructure(b)) }},
+		{"ScanState", func(b []byte) string {
+			var s ScanState
+			var out []uint64
+			for _, v := range s.Chunks(b) {
+				out = append(out, s.Previous(v), s.PairMask(v, '\r', '\n'), s.EscapedMask(v), s.QuoteMask(HighBitWhereEqual(v, Dupe('"'))))
+			}
+			return fmt.Sprint(out, s.Escaped, s.InQuote)
+		}, func(b []byte) string {
+			var s ref.ScanState
+			var out []uint64
+			for _, v := range s.Chunks(b) {
+				out = append(out, s.Previous(v), s.PairMask(v, '\r', '\n'), s.EscapedMask(v), s.QuoteMask(ref.HighBitWhereEqual(v, ref.Dupe('"'))))
+			}
+			return fmt.Sprint(out, s.Escaped, s.InQuote)
+		}},
+		{"InternetChecksum", func(b []byte) string { return fmt.Sprint(InternetChecksum(b)) }, func(b []byte) string { return fmt.Sprint(ref.InternetChecksum(b)) }},
+		{"OnesComplementSum", func(b []byte) string { return fmt.Sprint(OnesComplementSum(uint16(len(b)*2459), b, len(b)%3)) }, func(b []byte) string { return fmt.Sprint(ref.OnesComplementSum(uint16(len(b)*2459), b, len(b)%3)) }},
+		{"Adler32", func(b []byte) string { return fmt.Sprint(Adler32(b)) }, func(b []byte) string { return fmt.Sprint(ref.Adler32(b)) }},
+		{"Fletcher16", func(b []byte) string { return fmt.Sprint(Fletcher16(b)) }, func(b []byte) string { return fmt.Sprint(ref.Fletcher16(b)) }},
+		{"Fletcher32", func(b []byte) string { return fmt.Sprint(Fletcher32(b)) }, func(b []byte) string { return fmt.Sprint(ref.Fletcher32(b)) }},
+		{"XorChecksum", func(b []byte) string { return fmt.Sprint(XorChecksum(b)) }, func(b []byte) string { return fmt.Sprint(ref.XorChecksum(b)) }},
+		{"LRC", func(b []byte) string { return fmt.Sprint(LRC(b)) }, func(b []byte) string { return fmt.Sprint(ref.LRC(b)) }},
+		{"Parity", func(b []byte) string { return fmt.Sprint(Parity(b)) }, func(b []byte) string { return fmt.Sprint(ref.Parity(b)) }},
+		{"HashFNV1a", func(b []byte) string { return fmt.Sprint(HashFNV1a(b)) }, func(b []byte) string { return fmt.Sprint(ref.HashFNV1a(b)) }},
+		{"MaxRLEEncodedLen", func(b []byte) string { return fmt.Sprint(MaxRLEEncodedLen(len(b))) }, func(b []byte) string { return fmt.Sprint(ref.MaxRLEEncodedLen(len(b))) }},
+		{"RLEEncode", func(b []byte) string {
+			dst, short := make([]byte, MaxRLEEncodedLen(len(b))), make([]byte, len(b)/2)
+			n, err := RLEEncode(dst, b)
+			m, shortErr := RLEEncode(short, b)
+			return fmt.Sprint(dst[:n], err, short[:m], shortErr)
+		}, func(b []byte) string {
+			dst, short := make([]byte, ref.MaxRLEEncodedLen(len(b))), make([]byte, len(b)/2)
+			n, err := ref.RLEEncode(dst, b)
+			m, shortErr := ref.RLEEncode(short, b)
+			return fmt.Sprint(dst[:n], err, short[:m], shortErr)
+		}},
+		{"RLEDecode", func(b []byte) string {
+			dst, short := make([]byte, 128*len(b)), make([]byte, 64)
+			n, err := RLEDecode(dst, b)
+			m, shortErr := RLEDecode(short, b)
+			return fmt.Sprint(dst[:n], err, short[:m], shortErr)
+		}, func(b []byte) string {
+			dst, short := make([]byte, 128*len(b)), make([]byte, 64)
+			n, err := ref.RLEDecode(dst, b)
+			m, shortErr := ref.RLEDecode(short, b)
+			return fmt.Sprint(dst[:n], err, short[:m], shortErr)
+		}},
+		{"RLEDecodedLen", func(b []byte) string { return fmt.Sprint(RLEDecodedLen(b)) }, func(b []byte) string { return fmt.Sprint(ref.RLEDecodedLen(b)) }},
+		{"DecodeUvarint64s", func(b []byte) string {
+			dst := make([]uint64, len(b)/3)
+			n, read := DecodeUvarint64s(dst, b)
+			return fmt.Sprint(dst[:n], read)
+		}, func(b []byte) string {
+			dst := make([]uint64, len(b)/3)
+			n, read := ref.DecodeUvarint64s(dst, b)
+			return fmt.Sprint(dst[:n], read)
+		}},
+		{"Runs", func(b []byte) string {
+			var out [][2]int
+			for start, n := range Runs(b) {
+				out = append(out, [2]int{start, n})
+			}
+			return fmt.Sprint(out)
+		}, func(b []byte) string {
+			var out [][2]int
+			for start, n := range ref.Runs(b) {
+				out = append(out, [2]int{start, n})
+			}
+			return fmt.Sprint(out)
+		}},
+		{"MatchLen", func(b []byte) string { return fmt.Sprint(MatchLen(b, flipped(b), len(b)-3)) }, func(b []byte) string { return fmt.Sprint(ref.MatchLen(b, flipped(b), len(b)-3)) }},
+		{"LongestRun", func(b []byte) string { return fmt.Sprint(LongestRun(b)) }, func(b []byte) string { return fmt.Sprint(ref.LongestRun(b)) }},
+		{"IsNonDecreasing", func(b []byte) string { return fmt.Sprint(IsNonDecreasing(b)) }, func(b []byte) string { return fmt.Sprint(ref.IsNonDecreasing(b)) }},
+		{"ReplaceByte", intoN(func(d, s []byte) int { return ReplaceByte(d, s, ' ', '_') }), intoN(func(d, s []byte) int { return ref.ReplaceByte(d, s, ' ', '_') })},
+		{"FillBytes", inPlace(func(b []byte) { FillBytes(b, byte(len(b))) }), inPlace(func(b []byte) { ref.FillBytes(b, byte(len(b))) })},
+		{"FillPattern", halves(func(d, s []byte) { FillPattern(d, s[:len(s)%13]) }), halves(func(d, s []byte) { ref.FillPattern(d, s[:len(s)%13]) })},
+		{"DupePatternAt", func(b []byte) string { return fmt.Sprint(DupePattern(b), DupePatternAt(b, len(b)/3)) }, func(b []byte) string { return fmt.Sprint(ref.DupePattern(b), ref.DupePatternAt(b, len(b)/3)) }},
+		{"NthIndexByte", func(b []byte) string {
+			var out []int
+			for n := -1; n < 5; n++ {
+				out = append(out, NthIndexByte(b, 'a', n), NthIndexByte(b, 0, n))
+			}
+			return fmt.Sprint(out)
+		}, func(b []byte) string {
+			var out []int
+			for n := -1; n < 5; n++ {
+				out = append(out, ref.NthIndexByte(b, 'a', n), ref.NthIndexByte(b, 0, n))
+			}
+			return fmt.Sprint(out)
+		}},
+		{"CountAnyBytes", func(b []byte) string { return fmt.Sprint(CountAnyBytes(b, b[:min(len(b), 3)]...)) }, func(b []byte) string { return fmt.Sprint(ref.CountAnyBytes(b, b[:min(len(b), 3)]...)) }},
+		{"CountMatches", func(b []byte) string { return fmt.Sprint(CountMatches(b, HighBitWhereNotUnreserved)) }, func(b []byte) string { return fmt.Sprint(ref.CountMatches(b, ref.HighBitWhereNotUnreserved)) }},
+		{"RemoveByte", intoN(func(d, s []byte) int { return RemoveByte(d, s, 0) }), intoN(func(d, s []byte) int { return ref.RemoveByte(d, s, 0) })},
+		{"Compress", intoN(func(d, s []byte) int { return Compress(d, s, HighBitWhereTokenChar) }), intoN(func(d, s []byte) int { return ref.Compress(d, s, ref.HighBitWhereTokenChar) })},
+		{"Expand", intoN(func(d, s []byte) int { return Expand(d, s[:len(s)/2], toWords(s)) }), intoN(func(d, s []byte) int { return ref.Expand(d, s[:len(s)/2], toWords(s)) })},
+		{"AddSaturating", halves(AddSaturating), halves(ref.AddSaturating)},
+		{"SubtractSaturating", halves(SubtractSaturating), halves(ref.SubtractSaturating)},
+		{"MaxInto", halves(MaxInto), halves(ref.MaxInto)},
+		{"MinInto", halves(MinInto), halves(ref.MinInto)},
+		{"GatherBytes", into(same, func(d, s []byte) { GatherBytes(d, s, indices(s)) }), into(same, func(d, s []byte) { ref.GatherBytes(d, s, indices(s)) })},
+		{"ScatterBytes", into(same, func(d, s []byte) { ScatterBytes(d, s, indices(s)) }), into(same, func(d, s []byte) { ref.ScatterBytes(d, s, indices(s)) })},
+		{"TranslateBytes", into(same, func(d, s []byte) { TranslateBytes(d, s, &xorTable) }), into(same, func(d, s []byte) { ref.TranslateBytes(d, s, &xorTable) })},
+		{"TableCompiler", func(b []byte) string {
+			table := tableOf(b)
+			c := NewTableCompiler(&table)
+			return fmt.Sprint(c.Decomposable(), c.Lookup(ref.Lane([8]byte(append(b[:min(len(b), 8):min(len(b), 8)], make([]byte, 8)...)))))
+		}, func(b []byte) string {
+			table := tableOf(b)
+			c := ref.NewTableCompiler(&table)
+			return fmt.Sprint(c.Decomposable(), c.Lookup(ref.Lane([8]byte(append(b[:min(len(b), 8):min(len(b), 8)], make([]byte, 8)...)))))
+		}},
+		{"Histogram", func(b []byte) string {
+			var counts [256]uint64
+			Histogram(b, &counts)
+			return fmt.Sprint(counts)
+		}, func(b []byte) string {
+			var counts [256]uint64
+			ref.Histogram(b, &counts)
+			return fmt.Sprint(counts)
+		}},
+		{"BlockStats", func(b []byte) string { return fmt.Sprint(BlockStats(b, 7), BlockStats(b, 0)) }, func(b []byte) string { return fmt.Sprint(ref.BlockStats(b, 7), ref.BlockStats(b, 0)) }},
+		{"CountGreater", func(b []byte) string {
+			return fmt.Sprint(CountGreater(b, 'a'), CountLess(b, 'a'), CountBetween(b, '0', '9'), CountBetween(b, '9', '0'))
+		}, func(b []byte) string {
+			return fmt.Sprint(ref.CountGreater(b, 'a'), ref.CountLess(b, 'a'), ref.CountBetween(b, '0', '9'), ref.CountBetween(b, '9', '0'))
+		}},
+		{"IndexOfMax", func(b []byte) string { return fmt.Sprint(IndexOfMax(b), IndexOfMin(b)) }, func(b []byte) string { return fmt.Sprint(ref.IndexOfMax(b), ref.IndexOfMin(b)) }},
+		{"ColumnStats", func(b []byte) string {
+			s := NewColumnStats(11)
+			out := []any{s.Add(b), s.Rows()}
+			for col := range 11 {
+				out = append(out, s.Min(col), s.Max(col), s.Sum(col))
+			}
+			return fmt.Sprint(out)
+		}, func(b []byte) string {
+			s := ref.NewColumnStats(11)
+			out := []any{s.Add(b), s.Rows()}
+			for col := range 11 {
+				out = append(out, s.Min(col), s.Max(col), s.Sum(col))
+			}
+			return fmt.Sprint(out)
+		}},
+		{"EMA8", func(b []byte) string {
+			w := toWords(b)
+			e := NewEMA8(uint(len(b)%9), HashFNV1a(b))
+			out := []uint64{e.Value()}
+			for k, v := range w {
+				out = append(out, e.Update(v), e.Deviation(^v))
+				if e.ResetWhere(v, w[k/2]); k%5 == 4 {
+					e.Reset(v >> 3)
+				}
+			}
+			return fmt.Sprint(out, e.Value())
+		}, func(b []byte) string {
+			w := toWords(b)
+			e := ref.NewEMA8(uint(len(b)%9), ref.HashFNV1a(b))
+			out := []uint64{e.Value()}
+			for k, v := range w {
+				out = append(out, e.Update(v), e.Deviation(^v))
+				if e.ResetWhere(v, w[k/2]); k%5 == 4 {
+					e.Reset(v >> 3)
+				}
+			}
+			return fmt.Sprint(out, e.Value())
+		}},
+		{"MatchBitmap", func(b []byte) string { return fmt.Sprint(MatchBitmap(b, HighBitWhereTokenChar)) }, func(b []byte) string { return fmt.Sprint(ref.MatchBitmap(b, ref.HighBitWhereTokenChar)) }},
+		{"Rank", func(b []byte) string {
+			w := toWords(b)
+			var out []int
+			for i := 0; i <= 64*len(w); i += 5 {
+				out = append(out, Rank(w, i))
+			}
+			for n := -1; n <= PopcountBitmap(w)+1; n++ {
+				out = append(out, Select(w, n))
+			}
+			return fmt.Sprint(out, slices.Collect(SetBits(w)), PopcountBitmap(w))
+		}, func(b []byte) string {
+			w := toWords(b)
+			var out []int
+			for i := 0; i <= 64*len(w); i += 5 {
+				out = append(out, ref.Rank(w, i))
+			}
+			for n := -1; n <= ref.PopcountBitmap(w)+1; n++ {
+				out = append(out, ref.Select(w, n))
+			}
+			return fmt.Sprint(out, slices.Collect(ref.SetBits(w)), ref.PopcountBitmap(w))
+		}},
+		{"AndBitmaps", combined(AndBitmaps), combined(ref.AndBitmaps)},
+		{"OrBitmaps", combined(OrBitmaps), combined(ref.OrBitmaps)},
+		{"AndNotBitmaps", combined(AndNotBitmaps), combined(ref.AndNotBitmaps)},
+		{"BitIndices", func(b []byte) string {
+			var out [][]int
+			for _, c := range b {
+				out = append(out, slices.Collect(BitIndices(c)))
+			}
+			for _, w := range toWords(b) {
+				out = append(out, slices.Collect(BitIndices64(w)))
+			}
+			return fmt.Sprint(out)
+		}, func(b []byte) string {
+			var out [][]int
+			for _, c := range b {
+				out = append(out, slices.Collect(ref.BitIndices(c)))
+			}
+			for _, w := range toWords(b) {
+				out = append(out, slices.Collect(ref.BitIndices64(w)))
+			}
+			return fmt.Sprint(out)
+		}},
+		{"AppendOnesPositions", func(b []byte) string {
+			var out []int
+			for i, c := range b {
+				out = AppendOnesPositions(out, c, 8*i)
+			}
+			return fmt.Sprint(out)
+		}, func(b []byte) string {
+			var out []int
+			for i, c := range b {
+				out = ref.AppendOnesPositions(out, c, 8*i)
+			}
+			return fmt.Sprint(out)
+		}},
+		{"ThresholdToBitmap", func(b []byte) string {
+			dst := make([]uint64, (len(b)+63)/64)
+			ThresholdToBitmap(dst, b, 0x60)
+			return fmt.Sprint(dst)
+		}, func(b []byte) string {
+			dst := make([]uint64, (len(b)+63)/64)
+			ref.ThresholdToBitmap(dst, b, 0x60)
+			return fmt.Sprint(dst)
+		}},
+		{"BlendRGBA", halves(BlendRGBA), halves(ref.BlendRGBA)},
+		{"Blend", halves(func(d, s []byte) { Blend(d, d, s, byte(len(s)*29)) }), halves(func(d, s []byte) { ref.Blend(d, d, s, byte(len(s)*29)) })},
+		{"PremultiplyAlpha", into(same, PremultiplyAlpha), into(same, ref.PremultiplyAlpha)},
+		{"RGBAToGray", into(func(n int) int { return n / 4 }, RGBAToGray), into(func(n int) int { return n / 4 }, ref.RGBAToGray)},
+		{"Threshold", into(same, func(d, s []byte) { Threshold(d, s, byte(len(s)*29)) }), into(same, func(d, s []byte) { ref.Threshold(d, s, byte(len(s)*29)) })},
+		{"AdjustBrightness", into(same, func(d, s []byte) { AdjustBrightness(d, s, int8(len(s)*29)) }), into(same, func(d, s []byte) { ref.AdjustBrightness(d, s, int8(len(s)*29)) })},
+		{"AddDither", into(same, func(d, s []byte) { AddDither(d, s, len(s)%13+1, &dither) }), into(same, func(d, s []byte) { ref.AddDither(d, s, len(s)%13+1, &dither) })},
+		{"QuantizeBytes", into(same, func(d, s []byte) { QuantizeBytes(d, s, len(s)*29%256+1) }), into(same, func(d, s []byte) { ref.QuantizeBytes(d, s, len(s)*29%256+1) })},
+		{"AdjustContrast", into(same, func(d, s []byte) { AdjustContrast(d, s, byte(len(s)*29), byte(len(s)%7+1)) }), into(same, func(d, s []byte) { ref.AdjustContrast(d, s, byte(len(s)*29), byte(len(s)%7+1)) })},
+		{"InvertBytes", into(same, InvertBytes), into(same, ref.InvertBytes)},
+		{"SwizzleRGBA", into(same, func(d, s []byte) { SwizzleRGBA(d, s, [4]byte{2, 1, 4, byte(len(s))}) }), into(same, func(d, s []byte) { ref.SwizzleRGBA(d, s, [4]byte{2, 1, 4, byte(len(s))}) })},
+		{"ExpandRGB565", into(func(n int) int { return n / 2 * 3 }, ExpandRGB565), into(func(n int) int { return n / 2 * 3 }, ref.ExpandRGB565)},
+		{"PackRGB888To565", into(func(n int) int { return n / 3 * 2 }, PackRGB888To565), into(func(n int) int { return n / 3 * 2 }, ref.PackRGB888To565)},
+		{"Interleave4Planes", func(b []byte) string { return fmt.Sprint(interleaved(b, Interleave4Planes, Deinterleave4)) }, func(b []byte) string { return fmt.Sprint(interleaved(b, ref.Interleave4Planes, ref.Deinterleave4)) }},
+		{"MixAudio8", func(b []byte) string {
+			dst := make([]byte, len(b))
+			n := MixAudio8(dst, b[:len(b)/3], b[len(b)/3:])
+			return fmt.Sprint(dst[:n], n)
+		}, func(b []byte) string {
+			dst := make([]byte, len(b))
+			n := ref.MixAudio8(dst, b[:len(b)/3], b[len(b)/3:])
+			return fmt.Sprint(dst[:n], n)
+		}},
+		{"ScaleAudio8", into(same, func(d, s []byte) { ScaleAudio8(d, s, byte(len(s)*29)) }), into(same, func(d, s []byte) { ref.ScaleAudio8(d, s, byte(len(s)*29)) })},
+		{"MuLawToLinear8", into(same, MuLawToLinear8), into(same, ref.MuLawToLinear8)},
+		{"LinearToMuLaw8", into(same, LinearToMuLaw8), into(same, ref.LinearToMuLaw8)},
+		{"MovingAverage", func(b []byte) string {
+			var out []any
+			for _, window := range []int{0, 1, 3, len(b) / 2, len(b), len(b) + 1} {
+				dst := make([]byte, len(b))
+				n := MovingAverage(dst, b, window)
+				out = append(out, dst[:n], n)
+			}
+			return fmt.Sprint(out)
+		}, func(b []byte) string {
+			var out []any
+			for _, window := range []int{0, 1, 3, len(b) / 2, len(b), len(b) + 1} {
+				dst := make([]byte, len(b))
+				n := ref.MovingAverage(dst, b, window)
+				out = append(out, dst[:n], n)
+			}
+			return fmt.Sprint(out)
+		}},
+		{"DownsampleByTwo", into(func(n int) int { return n / 2 }, DownsampleByTwo), into(func(n int) int { return n / 2 }, ref.DownsampleByTwo)},
+		{"UpsampleByTwo", into(func(n int) int { return 2 * n }, UpsampleByTwo), into(func(n int) int { return 2 * n }, ref.UpsampleByTwo)},
+		{"MedianFilter3", into(same, MedianFilter3), into(same, ref.MedianFilter3)},
+		{"Top2Bytes", func(b []byte) string { return fmt.Sprint(Top2Bytes(toWords(b))) }, func(b []byte) string { return fmt.Sprint(ref.Top2Bytes(toWords(b))) }},
+		{"SumAbsoluteDifferences", func(b []byte) string { return fmt.Sprint(SumAbsoluteDifferences(b, b[len(b)/2:])) }, func(b []byte) string { return fmt.Sprint(ref.SumAbsoluteDifferences(b, b[len(b)/2:])) }},
+		{"MeanSquaredError", func(b []byte) string { return fmt.Sprint(MeanSquaredError(b, b[len(b)/2:])) }, func(b []byte) string { return fmt.Sprint(ref.MeanSquaredError(b, b[len(b)/2:])) }},
+		{"PSNR", func(b []byte) string { return fmt.Sprint(PSNR(b, b[len(b)/2:]), PSNR(b, b)) }, func(b []byte) string { return fmt.Sprint(ref.PSNR(b, b[len(b)/2:]), ref.PSNR(b, b)) }},
+		{"DotProductBytes", func(b []byte) string { return fmt.Sprint(DotProductBytes(b, b[len(b)/2:])) }, func(b []byte) string { return fmt.Sprint(ref.DotProductBytes(b, b[len(b)/2:])) }},
+	}
+	for _, b := range refInputs() {
+		for _, op := range funcs {
+			if got, want := op.f(b), op.ref(b); got != want {
+				t.Errorf("%s(%q) = %s; want %s", op.name, b, got, want)
+			}
+		}
 	}
-	return out
 }
 
-func addWrapBytes(ba, bb [8]byte) [8]byte {
-	ba[0] = ba[0] + bb[0]
-	ba[1] = ba[1] + bb[1]
-	ba[2] = ba[2] + bb[2]
-	ba[3] = ba[3] + bb[3]
-	ba[4] = ba[4] + bb[4]
-	ba[5] = ba[5] + bb[5]
-	ba[6] = ba[6] + bb[6]
-	ba[7] = ba[7] + bb[7]
-	return ba
-}
-
-func minBytes(ba, bb [8]byte) [8]byte {
-	if bb[0] < ba[0] {
-		ba[0] = bb[0]
-	}
-	if bb[1] < ba[1] {
-		ba[1] = bb[1]
-	}
-	if bb[2] < ba[2] {
-		ba[2] = bb[2]
-	}
-	if bb[3] < ba[3] {
-		ba[3] = bb[3]
-	}
-	if bb[4] < ba[4] {
-		ba[4] = bb[4]
-	}
-	if bb[5] < ba[5] {
-		ba[5] = bb[5]
+// boolBit returns ok as a single set bit at position bit
+func boolBit(ok bool, bit uint) uint64 {
+	if ok {
+		return 1 << bit
 	}
-	if bb[6] < ba[6] {
-		ba[6] = bb[6]
-	}
-	if bb[7] < ba[7] {
-		ba[7] = bb[7]
-	}
-	return ba
+	return 0
 }
 
-func maxBytes(ba, bb [8]byte) [8]byte {
-	if bb[0] > ba[0] {
-		ba[0] = bb[0]
-	}
-	if bb[1] > ba[1] {
-		ba[1] = bb[1]
-	}
-	if bb[2] > ba[2] {
-		ba[2] = bb[2]
-	}
-	if bb[3] > ba[3] {
-		ba[3] = bb[3]
-	}
-	if bb[4] > ba[4] {
-		ba[4] = bb[4]
-	}
-	if bb[5] > ba[5] {
-		ba[5] = bb[5]
+// fromAlphabet replaces every byte of v with a byte of alphabet chosen by its value
+func fromAlphabet(v uint64, alphabet string) uint64 {
+	b := ref.Bytes(v)
+	for i := range b {
+		b[i] = alphabet[int(b[i])%len(alphabet)]
 	}
-	if bb[6] > ba[6] {
-		ba[6] = bb[6]
-	}
-	if bb[7] > ba[7] {
-		ba[7] = bb[7]
-	}
-	return ba
-}
-
-func averageBytes(ba, bb [8]byte) [8]byte {
-	ba[0] = byte((int(ba[0]) + int(bb[0])) / 2)
-	ba[1] = byte((int(ba[1]) + int(bb[1])) / 2)
-	ba[2] = byte((int(ba[2]) + int(bb[2])) / 2)
-	ba[3] = byte((int(ba[3]) + int(bb[3])) / 2)
-	ba[4] = byte((int(ba[4]) + int(bb[4])) / 2)
-	ba[5] = byte((int(ba[5]) + int(bb[5])) / 2)
-	ba[6] = byte((int(ba[6]) + int(bb[6])) / 2)
-	ba[7] = byte((int(ba[7]) + int(bb[7])) / 2)
-	return ba
-}
-
-func swapNibbles(b [8]byte) [8]byte {
-	b[0] = (b[0]&0x0F)<<4 | (b[0]&0xF0)>>4
-	b[1] = (b[1]&0x0F)<<4 | (b[1]&0xF0)>>4
-	b[2] = (b[2]&0x0F)<<4 | (b[2]&0xF0)>>4
-	b[3] = (b[3]&0x0F)<<4 | (b[3]&0xF0)>>4
-	b[4] = (b[4]&0x0F)<<4 | (b[4]&0xF0)>>4
-	b[5] = (b[5]&0x0F)<<4 | (b[5]&0xF0)>>4
-	b[6] = (b[6]&0x0F)<<4 | (b[6]&0xF0)>>4
-	b[7] = (b[7]&0x0F)<<4 | (b[7]&0xF0)>>4
-	return b
+	return ref.Lane(b)
 }
 
-func reverseBits(b [8]byte) [8]byte {
-	b[0] = bits.Reverse8(b[0])
-	b[1] = bits.Reverse8(b[1])
-	b[2] = bits.Reverse8(b[2])
-	b[3] = bits.Reverse8(b[3])
-	b[4] = bits.Reverse8(b[4])
-	b[5] = bits.Reverse8(b[5])
-	b[6] = bits.Reverse8(b[6])
-	b[7] = bits.Reverse8(b[7])
-	return b
+// toWords packs b into little-endian words, zero-padding the last
+func toWords(b []byte) []uint64 {
+	w := make([]uint64, (len(b)+7)/8)
+	for i, c := range b {
+		w[i/8] |= uint64(c) << (8 * (i % 8))
+	}
+	return w
 }
 
-func popcountPerByte(b [8]byte) [8]byte {
-	b[0] = byte(bits.OnesCount8(b[0]))
-	b[1] = byte(bits.OnesCount8(b[1]))
-	b[2] = byte(bits.OnesCount8(b[2]))
-	b[3] = byte(bits.OnesCount8(b[3]))
-	b[4] = byte(bits.OnesCount8(b[4]))
-	b[5] = byte(bits.OnesCount8(b[5]))
-	b[6] = byte(bits.OnesCount8(b[6]))
-	b[7] = byte(bits.OnesCount8(b[7]))
+// flipped returns a copy of b with one bit changed five sevenths of the way along
+func flipped(b []byte) []byte {
+	b = bytes.Clone(b)
+	if len(b) > 0 {
+		b[len(b)*5/7] ^= 1
+	}
 	return b
 }
 
-func addSatBytes(ba, bb [8]byte) [8]byte {
-	if bb[0] > 0xFF-ba[0] {
-		ba[0] = 0xFF
-	} else {
-		ba[0] += bb[0]
-	}
-	if bb[1] > 0xFF-ba[1] {
-		ba[1] = 0xFF
-	} else {
-		ba[1] += bb[1]
-	}
-	if bb[2] > 0xFF-ba[2] {
-		ba[2] = 0xFF
-	} else {
-		ba[2] += bb[2]
-	}
-	if bb[3] > 0xFF-ba[3] {
-		ba[3] = 0xFF
-	} else {
-		ba[3] += bb[3]
-	}
-	if bb[4] > 0xFF-ba[4] {
-		ba[4] = 0xFF
-	} else {
-		ba[4] += bb[4]
-	}
-	if bb[5] > 0xFF-ba[5] {
-		ba[5] = 0xFF
-	} else {
-		ba[5] += bb[5]
-	}
-	if bb[6] > 0xFF-ba[6] {
-		ba[6] = 0xFF
-	} else {
-		ba[6] += bb[6]
-	}
-	if bb[7] > 0xFF-ba[7] {
-		ba[7] = 0xFF
-	} else {
-		ba[7] += bb[7]
-	}
-	return ba
-}
-
-func selectByLowBits(ba, bb, ma [8]byte) [8]byte {
-	var out [8]byte
-	if ma[0] != 0 {
-		out[0] = ba[0]
-	} else {
-		out[0] = bb[0]
-	}
-	if ma[1] != 0 {
-		out[1] = ba[1]
-	} else {
-		out[1] = bb[1]
-	}
-	if ma[2] != 0 {
-		out[2] = ba[2]
-	} else {
-		out[2] = bb[2]
-	}
-	if ma[3] != 0 {
-		out[3] = ba[3]
-	} else {
-		out[3] = bb[3]
-	}
-	if ma[4] != 0 {
-		out[4] = ba[4]
-	} else {
-		out[4] = bb[4]
-	}
-	if ma[5] != 0 {
-		out[5] = ba[5]
-	} else {
-		out[5] = bb[5]
-	}
-	if ma[6] != 0 {
-		out[6] = ba[6]
-	} else {
-		out[6] = bb[6]
-	}
-	if ma[7] != 0 {
-		out[7] = ba[7]
-	} else {
-		out[7] = bb[7]
-	}
-	return out
-}
-
-func subBytesWrap(aa, bb [8]byte) [8]byte {
-	aa[0] = aa[0] - bb[0]
-	aa[1] = aa[1] - bb[1]
-	aa[2] = aa[2] - bb[2]
-	aa[3] = aa[3] - bb[3]
-	aa[4] = aa[4] - bb[4]
-	aa[5] = aa[5] - bb[5]
-	aa[6] = aa[6] - bb[6]
-	aa[7] = aa[7] - bb[7]
-	return aa
-}
-
-func subBytesSat(aa, bb [8]byte) [8]byte {
-	if bb[0] > aa[0] {
-		aa[0] = 0
-	} else {
-		aa[0] -= bb[0]
-	}
-	if bb[1] > aa[1] {
-		aa[1] = 0
-	} else {
-		aa[1] -= bb[1]
-	}
-	if bb[2] > aa[2] {
-		aa[2] = 0
-	} else {
-		aa[2] -= bb[2]
-	}
-	if bb[3] > aa[3] {
-		aa[3] = 0
-	} else {
-		aa[3] -= bb[3]
-	}
-	if bb[4] > aa[4] {
-		aa[4] = 0
-	} else {
-		aa[4] -= bb[4]
-	}
-	if bb[5] > aa[5] {
-		aa[5] = 0
-	} else {
-		aa[5] -= bb[5]
-	}
-	if bb[6] > aa[6] {
-		aa[6] = 0
-	} else {
-		aa[6] -= bb[6]
-	}
-	if bb[7] > aa[7] {
-		aa[7] = 0
-	} else {
-		aa[7] -= bb[7]
-	}
-	return aa
-}
-
-func addBytesSat(aa, bb [8]byte) [8]byte {
-	s0 := uint16(aa[0]) + uint16(bb[0])
-	if s0 > 255 {
-		aa[0] = 0xFF
-	} else {
-		aa[0] = byte(s0)
-	}
-	s1 := uint16(aa[1]) + uint16(bb[1])
-	if s1 > 255 {
-		aa[1] = 0xFF
-	} else {
-		aa[1] = byte(s1)
-	}
-	s2 := uint16(aa[2]) + uint16(bb[2])
-	if s2 > 255 {
-		aa[2] = 0xFF
-	} else {
-		aa[2] = byte(s2)
-	}
-	s3 := uint16(aa[3]) + uint16(bb[3])
-	if s3 > 255 {
-		aa[3] = 0xFF
-	} else {
-		aa[3] = byte(s3)
-	}
-	s4 := uint16(aa[4]) + uint16(bb[4])
-	if s4 > 255 {
-		aa[4] = 0xFF
-	} else {
-		aa[4] = byte(s4)
-	}
-	s5 := uint16(aa[5]) + uint16(bb[5])
-	if s5 > 255 {
-		aa[5] = 0xFF
-	} else {
-		aa[5] = byte(s5)
-	}
-	s6 := uint16(aa[6]) + uint16(bb[6])
-	if s6 > 255 {
-		aa[6] = 0xFF
-	} else {
-		aa[6] = byte(s6)
-	}
-	s7 := uint16(aa[7]) + uint16(bb[7])
-	if s7 > 255 {
-		aa[7] = 0xFF
-	} else {
-		aa[7] = byte(s7)
-	}
-	return aa
-}
-
-func absDiffBytes(aa, bb [8]byte) [8]byte {
-	if aa[0] >= bb[0] {
-		aa[0] = aa[0] - bb[0]
-	} else {
-		aa[0] = bb[0] - aa[0]
-	}
-	if aa[1] >= bb[1] {
-		aa[1] = aa[1] - bb[1]
-	} else {
-		aa[1] = bb[1] - aa[1]
-	}
-	if aa[2] >= bb[2] {
-		aa[2] = aa[2] - bb[2]
-	} else {
-		aa[2] = bb[2] - aa[2]
-	}
-	if aa[3] >= bb[3] {
-		aa[3] = aa[3] - bb[3]
-	} else {
-		aa[3] = bb[3] - aa[3]
-	}
-	if aa[4] >= bb[4] {
-		aa[4] = aa[4] - bb[4]
-	} else {
-		aa[4] = bb[4] - aa[4]
-	}
-	if aa[5] >= bb[5] {
-		aa[5] = aa[5] - bb[5]
-	} else {
-		aa[5] = bb[5] - aa[5]
-	}
-	if aa[6] >= bb[6] {
-		aa[6] = aa[6] - bb[6]
-	} else {
-		aa[6] = bb[6] - aa[6]
-	}
-	if aa[7] >= bb[7] {
-		aa[7] = aa[7] - bb[7]
-	} else {
-		aa[7] = bb[7] - aa[7]
-	}
-	return aa
-}
-
-func highBitWhereLess(b [8]byte, c [8]byte) [8]byte {
-	if b[0] < c[0] {
-		b[0] = 0x80
-	} else {
-		b[0] = 0
-	}
-	if b[1] < c[1] {
-		b[1] = 0x80
-	} else {
-		b[1] = 0
-	}
-	if b[2] < c[2] {
-		b[2] = 0x80
-	} else {
-		b[2] = 0
-	}
-	if b[3] < c[3] {
-		b[3] = 0x80
-	} else {
-		b[3] = 0
-	}
-	if b[4] < c[4] {
-		b[4] = 0x80
-	} else {
-		b[4] = 0
-	}
-	if b[5] < c[5] {
-		b[5] = 0x80
-	} else {
-		b[5] = 0
-	}
-	if b[6] < c[6] {
-		b[6] = 0x80
-	} else {
-		b[6] = 0
-	}
-	if b[7] < c[7] {
-		b[7] = 0x80
-	} else {
-		b[7] = 0
+// indices derives an in-range index for each byte of b from its value
+func indices(b []byte) []int32 {
+	idx := make([]int32, len(b))
+	for i, c := range b {
+		idx[i] = int32(c) % int32(len(b))
 	}
-	return b
+	return idx
 }
 
-func highBitWhereGreater(b [8]byte, c [8]byte) [8]byte {
-	if b[0] > c[0] {
-		b[0] = 0x80
-	} else {
-		b[0] = 0
-	}
-	if b[1] > c[1] {
-		b[1] = 0x80
-	} else {
-		b[1] = 0
-	}
-	if b[2] > c[2] {
-		b[2] = 0x80
-	} else {
-		b[2] = 0
-	}
-	if b[3] > c[3] {
-		b[3] = 0x80
-	} else {
-		b[3] = 0
-	}
-	if b[4] > c[4] {
-		b[4] = 0x80
-	} else {
-		b[4] = 0
-	}
-	if b[5] > c[5] {
-		b[5] = 0x80
-	} else {
-		b[5] = 0
-	}
-	if b[6] > c[6] {
-		b[6] = 0x80
-	} else {
-		b[6] = 0
-	}
-	if b[7] > c[7] {
-		b[7] = 0x80
-	} else {
-		b[7] = 0
+// tableOf builds a 256-entry table from b, nibble-decomposable when b is shorter than 32 bytes
+func tableOf(b []byte) [256]byte {
+	var table [256]byte
+	for c := range table {
+		table[c] = at(b, c&15) ^ at(b, 16+c>>4)
+		if len(b) >= 32 {
+			table[c] ^= at(b, c)
+		}
 	}
-	return b
+	return table
 }
 
-func highBitWhereEqual(b [8]byte, c [8]byte) [8]byte {
-	if b[0] == c[0] {
-		b[0] = 0x80
-	} else {
-		b[0] = 0
-	}
-	if b[1] == c[1] {
-		b[1] = 0x80
-	} else {
-		b[1] = 0
-	}
-	if b[2] == c[2] {
-		b[2] = 0x80
-	} else {
-		b[2] = 0
-	}
-	if b[3] == c[3] {
-		b[3] = 0x80
-	} else {
-		b[3] = 0
-	}
-	if b[4] == c[4] {
-		b[4] = 0x80
-	} else {
-		b[4] = 0
-	}
-	if b[5] == c[5] {
-		b[5] = 0x80
-	} else {
-		b[5] = 0
-	}
-	if b[6] == c[6] {
-		b[6] = 0x80
-	} else {
-		b[6] = 0
-	}
-	if b[7] == c[7] {
-		b[7] = 0x80
-	} else {
-		b[7] = 0
+// at returns b[i], or 0 past the end of b
+func at(b []byte, i int) byte {
+	if i < len(b) {
+		return b[i]
 	}
-	return b
+	return 0
 }
 
-func TestSWARFunctionsRef(t *testing.T) {
-	for n := uint64(0); n < 0x_FF_FF_FF_FF_FF; n = (n*12 + 13) / 11 {
-		nA := toBytes(n)
-		if a, b := SwapByteHalves(n), swapNibbles(nA); a != fromBytes(b) {
-			t.Errorf("SwapByteHalves(0x%016x) = 0x%016x; want 0x%016x", n, a, fromBytes(b))
-		}
-		if a, b := ReverseEachByte(n), reverseBits(nA); a != fromBytes(b) {
-			t.Errorf("ReverseEachByte(0x%016x) = 0x%016x; want 0x%016x", n, a, fromBytes(b))
-		}
-		if a, b := CountOnesPerByte(n), popcountPerByte(nA); a != fromBytes(b) {
-			t.Errorf("CountOnesPerByte(0x%016x) = 0x%016x; want 0x%016x", n, a, fromBytes(b))
-		}
-		if a, b := ExtractLowBits(n&LowBits), byteFromLowBits(toBytes(n&LowBits)); a != b {
-			t.Errorf("ExtractLowBits(0x%016x) = 0b%08b; want 0b%08b", n, a, b)
-		}
-
-		m := n ^ 0x0000005351952b76
-		mA := toBytes(m)
-		if a, b := SelectSmallerBytes(n, m), minBytes(nA, mA); a != fromBytes(b) {
-			t.Errorf("SelectSmallerBytes(0x%016x, 0x%016x) = 0x%016x; want 0x%016x", n, m, a, fromBytes(b))
-		}
-		if a, b := SelectLargerBytes(n, m), maxBytes(nA, mA); a != fromBytes(b) {
-			t.Errorf("SelectLargerBytes(0x%016x, 0x%016x) = 0x%016x; want 0x%016x", n, m, a, fromBytes(b))
-		}
-		if a, b := AverageBytes(n, m), averageBytes(nA, mA); a != fromBytes(b) {
-			t.Errorf("AverageBytes(0x%016x, 0x%016x) = 0x%016x; want 0x%016x", n, m, a, fromBytes(b))
-		}
-		if a, b := AbsoluteDifferenceBetweenBytes(n, m), absDiffBytes(nA, mA); a != fromBytes(b) {
-			t.Errorf("AbsoluteDifferenceBetweenBytes(0x%016x, 0x%016x) = 0x%016x; want 0x%016x", n, m, a, fromBytes(b))
-		}
-		if a, b := AddBytesWithWrapping(n, m), addWrapBytes(nA, mA); a != fromBytes(b) {
-			t.Errorf("AddWrapBytes(0x%016x, 0x%016x) = 0x%016x; want 0x%016x", n, m, a, fromBytes(b))
-		}
-		if a, b := SubtractBytesWithWrapping(n, m), subBytesWrap(nA, mA); a != fromBytes(b) {
-			t.Errorf("SubtractBytesWithWrapping(0x%016x, 0x%016x) = 0x%016x; want 0x%016x", n, m, a, fromBytes(b))
-		}
-		if a, b := AddBytesWithMaximum(n, m), addSatBytes(nA, mA); a != fromBytes(b) {
-			t.Errorf("AddBytesWithMaximum(0x%016x, 0x%016x) = 0x%016x; want 0x%016x", n, m, a, fromBytes(b))
-		}
-		if a, b := SubtractBytesWithMinimum(n, m), subBytesSat(nA, mA); a != fromBytes(b) {
-			t.Errorf("SubtractBytesWithMinimum(0x%016x, 0x%016x) = 0x%016x; want 0x%016x", n, m, a, fromBytes(b))
-		}
+// interleaved packs the quarters of b into pixels with interleave and splits them again with deinterleave
+func interleaved(b []byte, interleave func(dst, r, g, b, a []byte), deinterleave func(r, g, b, a, src []byte)) [][]byte {
+	q := len(b) / 4
+	pixels := make([]byte, 4*q)
+	interleave(pixels, b[:q], b[q:2*q], b[2*q:3*q], b[3*q:])
+	planes := [][]byte{make([]byte, len(b)/4), make([]byte, len(b)/4), make([]byte, len(b)/4), make([]byte, len(b)/4)}
+	deinterleave(planes[0], planes[1], planes[2], planes[3], b)
+	return append(planes, pixels)
+}
 
-		c := Dupe(byte(m % 0x_FE))
-		if a, b := HighBitWhereLess(n, c), highBitWhereLess(nA, toBytes(c)); a != fromBytes(b) {
-			t.Errorf("HighBitWhereLess(0x%016x, %2x) = 0x%016x; want 0x%016x", n, c, a, fromBytes(b))
-		}
-		if a, b := HighBitWhereGreater(n, c), highBitWhereGreater(nA, toBytes(c)); a != fromBytes(b) {
-			t.Errorf("HighBitWhereGreater(0x%016x, %2x) = 0x%016x; want 0x%016x", n, c, a, fromBytes(b))
-		}
-		if a, b := HighBitWhereEqual(n, c), highBitWhereEqual(nA, toBytes(c)); a != fromBytes(b) {
-			t.Errorf("HighBitWhereEqual(0x%016x, %2x) = 0x%016x; want 0x%016x", n, c, a, fromBytes(b))
-		}
+// refInputs returns the byte strings the slice-level functions are compared on: every length
+// up to 70 of random bytes, sparse bytes, JSON punctuation and a mixed text corpus, plus
+// valid and nearly valid addresses, dates, times, UUIDs, hex and base64.
+func refInputs() [][]byte {
+	corpus := []byte("{\"id\": \"123e4567-e89b-12d3-a456-426614174000\", \"s\": \"a\\\"b\\\\\"}\r\n" +
+		"GET /a%20b?x=1 HTTP/1.1\r\nHost: example.com\r\n\r\n\tDEADbeef 0123456789abcdef " +
+		"Ünïcödé it's (foo) bar-baz\r\r\nSGVsbG8gV29ybGQ=\n\x00\x01\x7f\xff")
+	var random, sparse, json []byte
+	for v := range check.Random(3, 10) {
+		r, s, j := ref.Bytes(v), ref.Bytes(v&0x0101_0101_0101_0101), ref.Bytes(fromAlphabet(v, `\\"a{:,[] `))
+		random, sparse, json = append(random, r[:]...), append(sparse, s[:]...), append(json, j[:]...)
+	}
+	var inputs [][]byte
+	for n := range 71 {
+		inputs = append(inputs, random[:n], sparse[:n], json[:n], corpus[:n], corpus[len(corpus)-n:])
+	}
+	for _, s := range []string{
+		"192.168.0.1", "255.255.255.255", "256.1.1.1", "01.2.3.4", "1.2.3", "1..2.3",
+		"2024-02-29", "2023-02-29", "2024-13-01", "23:59:60", "23:59:61", "24:00:00",
+		"123e4567-e89b-12d3-a456-426614174000", "123E4567-E89B-12D3-A456-42661417400G",
+		"0123456789abcdef", "0123456789ABCDEf", "18446744073709551615", "18446744073709551616",
+		"SGVsbG8gV29ybGQ=", "SGVsbG8gV29ybA==", "SGVsbG8gV29ybGQ", "SGVs bG8g\nV29y",
+		"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaabbbbbbbbbbbbbbbbbbbbbbbbc",
+		"0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz", "!#$%&'*+-.^_`|~09AZaz",
+	} {
+		inputs = append(inputs, []byte(s))
+	}
+	return inputs
+}
 
-		d := uint64(0x_01_00_01_01_00_00_01_00)
-		dA := toBytes(d)
-		if a, b := SelectByLowBit(n, m, d), selectByLowBits(nA, mA, dA); a != fromBytes(b) {
-			t.Errorf("SelectByLowBit(0x%016x, 0x%016x, 0x%016x) = 0x%016x; want 0x%016x (%v)", n, m, d, a, fromBytes(b), dA)
+// toBCDRange reduces every byte of v to 0-99
+func toBCDRange(v uint64) uint64 {
+	return ref.Lane(func(b [8]byte) [8]byte {
+		for i := range b {
+			b[i] %= 100
 		}
+		return b
+	}(ref.Bytes(v)))
+}
 
-		// t.Logf("Tested with n=0x%016x, m=0x%016x, c=%02x, d=0x%016x", n, m, c, d)
-		// t.Logf("As arrays: n=%v, m=%v, d=%v", nA, mA, dA)
-	}
+// toBCD turns every byte of v into two valid packed decimal digits
+func toBCD(v uint64) uint64 {
+	return ref.BinaryToBCDBytes(toBCDRange(v))
 }
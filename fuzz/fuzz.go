@@ -0,0 +1,362 @@
+// Package fuzz exposes the swar slice-level APIs as targets for native Go fuzzing
+//
+// RoundTrip and CompareToRef take arbitrary input, run it through the swar functions
+// at every starting alignment within a lane, both into a separate buffer and in place
+// where the API allows it, and return an error describing the first discrepancy. Call
+// them from a testing.F fuzz target; the package's own tests do exactly that:
+//
+//	f.Fuzz(func(t *testing.T, data []byte) {
+//		if err := fuzz.RoundTrip(data); err != nil {
+//			t.Fatal(err)
+//		}
+//	})
+package fuzz
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"hash/adler32"
+	"hash/fnv"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/dans-stuff/swar"
+	"github.com/dans-stuff/swar/ref"
+)
+
+// RoundTrip checks that every encode, transform and split in swar undoes or agrees with its inverse
+// Covers hex, RLE, Compress/Expand, case swapping, Rot13, XOR masks and plane interleaving
+func RoundTrip(data []byte) error {
+	for k := range min(8, len(data)+1) {
+		for _, f := range roundTrips {
+			if err := f(data[k:]); err != nil {
+				return fmt.Errorf("at offset %d: %w", k, err)
+			}
+		}
+	}
+	return nil
+}
+
+// CompareToRef checks the swar slice and lane functions against the standard library and package ref
+// Lane functions see data as consecutive lanes, with the final lane zero padded
+func CompareToRef(data []byte) error {
+	for k := range min(8, len(data)+1) {
+		for _, f := range comparisons {
+			if err := f(data[k:]); err != nil {
+				return fmt.Errorf("at offset %d: %w", k, err)
+			}
+		}
+	}
+	return nil
+}
+
+// roundTrips each check one inverse pair on a single input
+var roundTrips = []func(src []byte) error{
+	func(src []byte) error {
+		enc := make([]byte, 2*len(src))
+		swar.EncodeHex(enc, src)
+		dec := make([]byte, len(src))
+		if _, err := swar.DecodeHex(dec, enc); err != nil || !bytes.Equal(dec, src) {
+			return fmt.Errorf("DecodeHex(EncodeHex(%x)) = %x, %v; want %x, nil", src, dec, err, src)
+		}
+		return nil
+	},
+	func(src []byte) error {
+		enc := make([]byte, swar.MaxRLEEncodedLen(len(src)))
+		n, err := swar.RLEEncode(enc, src)
+		if err != nil {
+			return fmt.Errorf("RLEEncode(%x) error = %v", src, err)
+		}
+		if size, err := swar.RLEDecodedLen(enc[:n]); size != len(src) || err != nil {
+			return fmt.Errorf("RLEDecodedLen(RLEEncode(%x)) = %d, %v; want %d, nil", src, size, err, len(src))
+		}
+		dec := make([]byte, len(src))
+		if m, err := swar.RLEDecode(dec, enc[:n]); err != nil || !bytes.Equal(dec[:m], src) {
+			return fmt.Errorf("RLEDecode(RLEEncode(%x)) = %x, %v; want %x, nil", src, dec[:m], err, src)
+		}
+		return nil
+	},
+	func(src []byte) error {
+		// Compress drops the bytes below 0x40; Expand puts the rest back around zeroed gaps
+		low := func(v uint64) uint64 { return swar.HighBitWhereLess(v, swar.Dupe(0x40)) }
+		kept := bytes.Clone(src)
+		n := swar.Compress(kept, kept, low)
+		bitmap := make([]uint64, len(src)/64+1)
+		want := make([]byte, len(src))
+		for i, c := range src {
+			if c >= 0x40 {
+				bitmap[i/64] |= 1 << (i % 64)
+				want[i] = c
+			}
+		}
+		got := make([]byte, len(src))
+		if used := swar.Expand(got, kept[:n], bitmap); used != n || !bytes.Equal(got, want) {
+			return fmt.Errorf("Expand(Compress(%x)) = %x, %d; want %x, %d", src, got, used, want, n)
+		}
+		return nil
+	},
+	func(src []byte) error {
+		return involution("SwapCaseASCII", src, swar.SwapCaseASCII)
+	},
+	func(src []byte) error {
+		return involution("Rot13", src, swar.Rot13)
+	},
+	func(src []byte) error {
+		var key [8]byte
+		copy(key[:], src)
+		return involution("XORMask", src, func(dst, src []byte) { swar.XORMask(dst, src, key) })
+	},
+	func(src []byte) error {
+		var key [4]byte
+		copy(key[:], src)
+		return involution("XORMask4", src, func(dst, src []byte) { swar.XORMask4(dst, src, key) })
+	},
+	func(src []byte) error {
+		// Unmasking in two fragments must match unmasking the whole payload at once
+		var key [4]byte
+		copy(key[:], src)
+		whole, split := bytes.Clone(src), bytes.Clone(src)
+		swar.UnmaskWebSocket(whole, key, 0)
+		cut := len(src) / 3
+		swar.UnmaskWebSocket(split[:cut], key, 0)
+		swar.UnmaskWebSocket(split[cut:], key, cut)
+		if !bytes.Equal(whole, split) {
+			return fmt.Errorf("UnmaskWebSocket(%x) split at %d = %x; want %x", src, cut, split, whole)
+		}
+		return nil
+	},
+	func(src []byte) error {
+		n := len(src) / 4
+		planes := [4][]byte{make([]byte, n), make([]byte, n), make([]byte, n), make([]byte, n)}
+		swar.Deinterleave4(planes[0], planes[1], planes[2], planes[3], src[:4*n])
+		got := make([]byte, 4*n)
+		swar.Interleave4Planes(got, planes[0], planes[1], planes[2], planes[3])
+		if !bytes.Equal(got, src[:4*n]) {
+			return fmt.Errorf("Interleave4Planes(Deinterleave4(%x)) = %x; want %x", src[:4*n], got, src[:4*n])
+		}
+		return nil
+	},
+}
+
+// involution checks that f applied twice restores src, both via a copy and in place
+func involution(name string, src []byte, f func(dst, src []byte)) error {
+	once := make([]byte, len(src))
+	f(once, src)
+	twice := make([]byte, len(src))
+	f(twice, once)
+	if !bytes.Equal(twice, src) {
+		return fmt.Errorf("%s(%s(%x)) = %x; want %x", name, name, src, twice, src)
+	}
+	inPlace := bytes.Clone(src)
+	f(inPlace, inPlace)
+	if !bytes.Equal(inPlace, once) {
+		return fmt.Errorf("%s(%x) in place = %x; want %x", name, src, inPlace, once)
+	}
+	return nil
+}
+
+// comparisons each check one swar function against an independent implementation
+var comparisons = []func(src []byte) error{
+	func(src []byte) error {
+		want := bytes.Count(src, []byte("\n"))
+		if len(src) > 0 && src[len(src)-1] != '\n' {
+			want++
+		}
+		if got := swar.CountLines(src); got != want {
+			return fmt.Errorf("CountLines(%q) = %d; want %d", src, got, want)
+		}
+		return nil
+	},
+	func(src []byte) error {
+		want := -1
+		for i, c := range src {
+			if c >= utf8.RuneSelf {
+				want = i
+				break
+			}
+		}
+		if got := swar.IndexNonASCII(src); got != want {
+			return fmt.Errorf("IndexNonASCII(%q) = %d; want %d", src, got, want)
+		}
+		if got := swar.IsASCII(src); got != (want == -1) {
+			return fmt.Errorf("IsASCII(%q) = %v; want %v", src, got, want == -1)
+		}
+		return nil
+	},
+	func(src []byte) error {
+		if !utf8.Valid(src) {
+			return nil
+		}
+		if got, want := swar.CountRunes(src), utf8.RuneCount(src); got != want {
+			return fmt.Errorf("CountRunes(%q) = %d; want %d", src, got, want)
+		}
+		return nil
+	},
+	func(src []byte) error {
+		want := bytes.Clone(src)
+		for i, c := range want {
+			if 'a' <= c && c <= 'z' {
+				want[i] = c - 'a' + 'A'
+			}
+		}
+		return compareTransform("ToUpperASCII", src, want, swar.ToUpperASCII)
+	},
+	func(src []byte) error {
+		if len(src) == 0 {
+			return nil
+		}
+		old, new := src[0], src[len(src)-1]^0x20
+		want := bytes.ReplaceAll(src, []byte{old}, []byte{new})
+		return compareTransform("ReplaceByte", src, want, func(dst, src []byte) { swar.ReplaceByte(dst, src, old, new) })
+	},
+	func(src []byte) error {
+		var table [256]byte
+		for c := range table {
+			table[c] = byte(c) * 167
+		}
+		want := make([]byte, len(src))
+		for i, c := range src {
+			want[i] = table[c]
+		}
+		return compareTransform("TranslateBytes", src, want, func(dst, src []byte) { swar.TranslateBytes(dst, src, &table) })
+	},
+	func(src []byte) error {
+		if len(src) == 0 {
+			return nil
+		}
+		c := src[len(src)/2]
+		want := bytes.ReplaceAll(src, []byte{c}, nil)
+		return compareTransformN("RemoveByte", src, want, func(dst, src []byte) int { return swar.RemoveByte(dst, src, c) })
+	},
+	func(src []byte) error {
+		want := bytes.ReplaceAll(src, []byte("\r\n"), []byte("\n"))
+		return compareTransformN("NormalizeNewlines", src, want, swar.NormalizeNewlines)
+	},
+	func(src []byte) error {
+		enc := make([]byte, 2*len(src))
+		swar.EncodeHex(enc, src)
+		if want := hex.EncodeToString(src); string(enc) != want {
+			return fmt.Errorf("EncodeHex(%x) = %s; want %s", src, enc, want)
+		}
+		_, err := hex.DecodeString(string(src))
+		if got := swar.ValidateHex(src); got != (err == nil) {
+			return fmt.Errorf("ValidateHex(%q) = %v; hex.DecodeString error = %v", src, got, err)
+		}
+		return nil
+	},
+	func(src []byte) error {
+		h := fnv.New64a()
+		h.Write(src)
+		if got, want := swar.HashFNV1a(src), h.Sum64(); got != want {
+			return fmt.Errorf("HashFNV1a(%x) = 0x%016x; want 0x%016x", src, got, want)
+		}
+		if got, want := swar.Adler32(src), adler32.Checksum(src); got != want {
+			return fmt.Errorf("Adler32(%x) = 0x%08x; want 0x%08x", src, got, want)
+		}
+		return nil
+	},
+	func(src []byte) error {
+		var got, want [256]uint64
+		swar.Histogram(src, &got)
+		for _, c := range src {
+			want[c]++
+		}
+		if got != want {
+			return fmt.Errorf("Histogram(%x) differs from a byte loop", src)
+		}
+		return nil
+	},
+	func(src []byte) error {
+		want, err := strconv.ParseUint(string(src), 10, 64)
+		if got, ok := swar.ParseUint(src); ok != (err == nil) || got != want && ok {
+			return fmt.Errorf("ParseUint(%q) = %d, %v; want %d, %v", src, got, ok, want, err == nil)
+		}
+		return nil
+	},
+	func(src []byte) error {
+		if len(src) == 0 {
+			return nil
+		}
+		delim := src[0]
+		i := 0
+		want := bytes.Split(src, []byte{delim})
+		for field := range swar.SplitIter(src, delim) {
+			if i >= len(want) || !bytes.Equal(field, want[i]) {
+				return fmt.Errorf("SplitIter(%q, %q) field %d = %q; want %q", src, delim, i, field, want)
+			}
+			i++
+		}
+		if i != len(want) {
+			return fmt.Errorf("SplitIter(%q, %q) yielded %d fields; want %d", src, delim, i, len(want))
+		}
+		return nil
+	},
+	func(src []byte) error {
+		// Each pair of adjacent lanes feeds the lane primitives as a and b
+		for i := 0; i < len(src); i += 8 {
+			a, b := loadLane(src, i), loadLane(src, i+8)
+			for _, op := range laneOps {
+				if got, want := op.f(a, b), op.ref(a, b); got != want {
+					return fmt.Errorf("%s(0x%016x, 0x%016x) = 0x%016x; want 0x%016x", op.name, a, b, got, want)
+				}
+			}
+		}
+		return nil
+	},
+}
+
+// compareTransform checks f(dst, src) against want, both via a copy and in place
+func compareTransform(name string, src, want []byte, f func(dst, src []byte)) error {
+	return compareTransformN(name, src, want, func(dst, src []byte) int {
+		f(dst, src)
+		return len(src)
+	})
+}
+
+// compareTransformN checks the first n bytes written by f(dst, src) against want, via a copy and in place
+func compareTransformN(name string, src, want []byte, f func(dst, src []byte) int) error {
+	dst := make([]byte, len(src))
+	if n := f(dst, src); !bytes.Equal(dst[:n], want) {
+		return fmt.Errorf("%s(%q) = %q; want %q", name, src, dst[:n], want)
+	}
+	inPlace := bytes.Clone(src)
+	if n := f(inPlace, inPlace); !bytes.Equal(inPlace[:n], want) {
+		return fmt.Errorf("%s(%q) in place = %q; want %q", name, src, inPlace[:n], want)
+	}
+	return nil
+}
+
+// loadLane reads up to 8 bytes of b from i as a lane, zero padding past the end
+func loadLane(b []byte, i int) uint64 {
+	var lane [8]byte
+	if i < len(b) {
+		copy(lane[:], b[i:])
+	}
+	return ref.Lane(lane)
+}
+
+// laneOps pairs lane primitives with their references; masks are derived from b
+var laneOps = []struct {
+	name   string
+	f, ref func(a, b uint64) uint64
+}{
+	{"HighBitWhereLess", swar.HighBitWhereLess, ref.HighBitWhereLess},
+	{"HighBitWhereGreater", swar.HighBitWhereGreater, ref.HighBitWhereGreater},
+	{"HighBitWhereEqual", swar.HighBitWhereEqual, ref.HighBitWhereEqual},
+	{"AddBytesWithWrapping", swar.AddBytesWithWrapping, ref.AddBytesWithWrapping},
+	{"AddBytesWithMaximum", swar.AddBytesWithMaximum, ref.AddBytesWithMaximum},
+	{"SubtractBytesWithWrapping", swar.SubtractBytesWithWrapping, ref.SubtractBytesWithWrapping},
+	{"SubtractBytesWithMinimum", swar.SubtractBytesWithMinimum, ref.SubtractBytesWithMinimum},
+	{"AbsoluteDifferenceBetweenBytes", swar.AbsoluteDifferenceBetweenBytes, ref.AbsoluteDifferenceBetweenBytes},
+	{"SelectSmallerBytes", swar.SelectSmallerBytes, ref.SelectSmallerBytes},
+	{"SelectLargerBytes", swar.SelectLargerBytes, ref.SelectLargerBytes},
+	{"AverageBytes", swar.AverageBytes, ref.AverageBytes},
+	{"MultiplyBytesNormalized", swar.MultiplyBytesNormalized, ref.MultiplyBytesNormalized},
+	{"NegateBytesWhere", swar.NegateBytesWhere, ref.NegateBytesWhere},
+	{"SelectByHighBit", func(a, b uint64) uint64 { return swar.SelectByHighBit(a, b, a^b) }, func(a, b uint64) uint64 { return ref.SelectByHighBit(a, b, a^b) }},
+	{"SortBytesInLane", func(a, _ uint64) uint64 { return swar.SortBytesInLane(a) }, func(a, _ uint64) uint64 { return ref.SortBytesInLane(a) }},
+	{"PrefixSumBytesSaturating", func(a, _ uint64) uint64 { return swar.PrefixSumBytesSaturating(a) }, func(a, _ uint64) uint64 { return ref.PrefixSumBytesSaturating(a) }},
+	{"ZigZagDecodeBytes", func(a, _ uint64) uint64 { return swar.ZigZagDecodeBytes(a) }, func(a, _ uint64) uint64 { return ref.ZigZagDecodeBytes(a) }},
+	{"MixBytes", func(a, _ uint64) uint64 { return swar.MixBytes(a) }, func(a, _ uint64) uint64 { return ref.MixBytes(a) }},
+}
@@ -0,0 +1,66 @@
+package fuzz_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dans-stuff/swar/fuzz"
+)
+
+// maxLen bounds fuzz inputs, since the engine minimizes each new interesting input with
+// O(n²) candidate runs of O(n) each and stops reporting execs while it does
+const maxLen = 128
+
+// seeds covers empty input, lane-sized edges, every byte value, CRLF text, digits, hex,
+// multi-byte UTF-8 and long runs, so plain go test already exercises each target broadly
+func seeds(f *testing.F) {
+	all := make([]byte, 256)
+	for i := range all {
+		all[i] = byte(i)
+	}
+	for _, s := range [][]byte{
+		nil,
+		[]byte("x"),
+		[]byte("12345678"),
+		[]byte("123456789"),
+		all[:maxLen],
+		all[maxLen:],
+		[]byte("GET / HTTP/1.1\r\nHost: example.com\r\n\r\n"),
+		[]byte("18446744073709551615"),
+		[]byte("18446744073709551616"),
+		[]byte("deadBEEF0123456789abcdef"),
+		[]byte("héllo, 世界! ☃\n\n"),
+		bytes.Repeat([]byte{'a'}, maxLen-1),
+		bytes.Repeat([]byte("\r\n\r"), 30),
+	} {
+		f.Add(s)
+	}
+}
+
+// FuzzRoundTrip checks that every inverse pair in swar restores arbitrary input at every
+// alignment, so the unsafe lane loads and partial tail stores see odd offsets and lengths
+func FuzzRoundTrip(f *testing.F) {
+	seeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if len(data) > maxLen {
+			return
+		}
+		if err := fuzz.RoundTrip(data); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+// FuzzCompareToRef checks the swar functions against the standard library and package ref
+// on arbitrary input, both through separate buffers and in place
+func FuzzCompareToRef(f *testing.F) {
+	seeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if len(data) > maxLen {
+			return
+		}
+		if err := fuzz.CompareToRef(data); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
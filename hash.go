@@ -0,0 +1,101 @@
+package swar
+
+import (
+	"iter"
+	"math/bits"
+)
+
+const (
+	fnvOffset64 uint64 = 0xcbf2_9ce4_8422_2325
+	fnvPrime64  uint64 = 0x0000_0100_0000_01b3
+
+	// hashP* are odd constants with balanced bits, as used by wyhash
+	hashP0 uint64 = 0xa076_1d64_78bd_642f
+	hashP1 uint64 = 0xe703_7ed1_a0b4_28db
+	hashP2 uint64 = 0x8ebc_6af0_9c88_c6db
+)
+
+// HashFNV1a returns the 64-bit FNV-1a hash of b, matching hash/fnv.New64a
+// Inherently serial, but a stable and widely understood choice for small keys
+func HashFNV1a(b []byte) uint64 {
+	h := fnvOffset64
+	for _, c := range b {
+		h ^= uint64(c)
+		h *= fnvPrime64
+	}
+	return h
+}
+
+// Hash64 returns a fast non-cryptographic hash of b that consumes 16 bytes per step
+// Each step is a 64x64->128 multiply folded to 64 bits; not stable across package versions
+func Hash64(b []byte, seed uint64) uint64 {
+	h := hashMix(seed^hashP0, hashP1)
+	i := 0
+	for ; i+16 <= len(b); i += 16 {
+		h = hashMix(loadLane(b, i)^hashP1, loadLane(b, i+8)^h)
+	}
+	if i < len(b) {
+		// Zero padding is safe because the length is mixed in below
+		h = hashMix(loadLane(b, i)^hashP2, loadLane(b, i+8)^h)
+	}
+	return hashMix(h^hashP0, uint64(len(b))^hashP1)
+}
+
+// hashMix multiplies a by b and folds the 128-bit product into 64 bits
+// Every input bit influences the middle of the product, which the XOR spreads out
+func hashMix(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return hi ^ lo
+}
+
+// SimHash64 returns the SimHash of features: bit i is set when most feature hashes set bit i
+// Near-duplicates differ in few bits by OnesCount64(a^b); like Hash64, not stable across versions
+func SimHash64(features iter.Seq[[]byte]) uint64 {
+	// counters[k] holds one byte-wide count per bit of byte k of the hashes, widened every 255 features
+	var counters [8]uint64
+	var totals [64]int
+	n, pending := 0, 0
+	flush := func() {
+		for k, lane := range counters {
+			for i, c := range IntToLanes(lane) {
+				totals[8*k+i] += int(c)
+			}
+		}
+		counters = [8]uint64{}
+		pending = 0
+	}
+	for f := range features {
+		h := Hash64(f, 0)
+		for k := range counters {
+			counters[k] += SpreadBitsToLanes(byte(h >> (8 * k)))
+		}
+		n++
+		if pending++; pending == 255 {
+			flush()
+		}
+	}
+	flush()
+	var sim uint64
+	for i, c := range totals {
+		if 2*c > n {
+			sim |= 1 << i
+		}
+	}
+	return sim
+}
+
+// MixBytes scrambles each byte of v independently with xor-shifts and odd multiplies
+// A bijection per byte, so distinct fingerprints stay distinct; diffuses without mixing lanes
+func MixBytes(v uint64) uint64 {
+	v ^= v >> 4 & 0x0F0F_0F0F_0F0F_0F0F
+	v = multiplyBytesBy(v, 0x9D)
+	v ^= v >> 3 & 0x1F1F_1F1F_1F1F_1F1F
+	v = multiplyBytesBy(v, 0x5B)
+	return v ^ v>>4&0x0F0F_0F0F_0F0F_0F0F
+}
+
+// multiplyBytesBy multiplies each byte of v by k modulo 256
+// Even and odd bytes are multiplied separately so no product spills into a live neighbour
+func multiplyBytesBy(v uint64, k uint64) uint64 {
+	return (v&mEven)*k&mEven | (v&^mEven)*k&^mEven
+}
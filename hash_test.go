@@ -0,0 +1,116 @@
+package swar
+
+import (
+	"fmt"
+	"hash/fnv"
+	"iter"
+	"math/bits"
+	"testing"
+
+	"github.com/dans-stuff/swar/check"
+	"github.com/dans-stuff/swar/ref"
+)
+
+// TestHashFNV1a compares with hash/fnv for every prefix of a mixed buffer so the constants
+// and byte order can never drift from the standard definition.
+func TestHashFNV1a(t *testing.T) {
+	b := []byte("The quick brown fox jumps over the lazy dog\x00\xff")
+	for n := 0; n <= len(b); n++ {
+		h := fnv.New64a()
+		h.Write(b[:n])
+		if got, want := HashFNV1a(b[:n]), h.Sum64(); got != want {
+			t.Errorf("HashFNV1a(%q) = 0x%016x; want 0x%016x", b[:n], got, want)
+		}
+	}
+}
+
+// TestHash64 checks that zero-filled inputs of every length, every single-bit flip, and
+// different seeds all hash differently. Tail padding collisions would show up here first.
+func TestHash64(t *testing.T) {
+	seen := map[uint64]string{}
+	run := func(b []byte, seed uint64, name string) {
+		h := Hash64(b, seed)
+		if h != Hash64(b, seed) {
+			t.Errorf("Hash64(%s) is not deterministic", name)
+		}
+		if prev, ok := seen[h]; ok {
+			t.Errorf("Hash64(%s) = 0x%016x; collides with %s", name, h, prev)
+		}
+		seen[h] = name
+	}
+
+	zeros := make([]byte, 64)
+	for n := 0; n <= len(zeros); n++ {
+		run(zeros[:n], 0, fmt.Sprintf("zeros[:%d]", n))
+		run(zeros[:n], 1, fmt.Sprintf("zeros[:%d] seed 1", n))
+	}
+	b := make([]byte, 40)
+	for i := 0; i < 8*len(b); i++ {
+		b[i/8] ^= 1 << (i % 8)
+		run(b, 0, fmt.Sprintf("bit %d", i))
+		b[i/8] ^= 1 << (i % 8)
+	}
+}
+
+// TestSimHash64 compares with a bit-by-bit majority vote over more than 255 features, so the
+// byte counters must be widened, and checks that a near-duplicate feature set lands much
+// closer in Hamming distance than an unrelated one.
+func TestSimHash64(t *testing.T) {
+	words := func(prefix string, n int) iter.Seq[[]byte] {
+		return func(yield func([]byte) bool) {
+			for i := range n {
+				if !yield(fmt.Appendf(nil, "%s%d", prefix, i)) {
+					return
+				}
+			}
+		}
+	}
+
+	for _, n := range []int{0, 1, 2, 255, 256, 1000} {
+		var want uint64
+		for bit := range 64 {
+			set := 0
+			for w := range words("w", n) {
+				set += int(Hash64(w, 0) >> bit & 1)
+			}
+			if 2*set > n {
+				want |= 1 << bit
+			}
+		}
+		if got := SimHash64(words("w", n)); got != want {
+			t.Errorf("SimHash64(%d words) = 0x%016x; want 0x%016x", n, got, want)
+		}
+	}
+
+	base := SimHash64(words("w", 1000))
+	near := SimHash64(func(yield func([]byte) bool) {
+		for w := range words("w", 1000) {
+			if w[len(w)-1] == '7' {
+				w = append(w, 'x')
+			}
+			if !yield(w) {
+				return
+			}
+		}
+	})
+	far := SimHash64(words("other", 1000))
+	if d, dFar := bits.OnesCount64(base^near), bits.OnesCount64(base^far); d >= 16 || dFar <= 16 {
+		t.Errorf("SimHash64 distances: near-duplicate %d, unrelated %d; want under and over 16", d, dFar)
+	}
+}
+
+// TestMixBytes compares with the per-byte reference, so no byte leaks into its neighbours,
+// and checks that each byte is mixed by a permutation, which keeps fingerprints distinct.
+func TestMixBytes(t *testing.T) {
+	if err := check.CheckLaneFunc(MixBytes, ref.MixBytes); err != nil {
+		t.Errorf("MixBytes: %v", err)
+	}
+	var seen [256]bool
+	for c := range 256 {
+		m := byte(MixBytes(uint64(c)))
+		if seen[m] {
+			t.Errorf("MixBytes maps two bytes to 0x%02x", m)
+		}
+		seen[m] = true
+	}
+}
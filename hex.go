@@ -0,0 +1,163 @@
+package swar
+
+import (
+	"encoding/hex"
+	"math/bits"
+)
+
+// EncodeHex writes the lower case hexadecimal encoding of src into dst
+// dst must hold 2*len(src) bytes; returns the number of bytes written
+func EncodeHex(dst, src []byte) int {
+	dst = dst[:2*len(src)]
+	src = unaliased(dst, src, false)
+	for i := 0; i < len(src); i += 8 {
+		v := loadLane(src, i)
+		storeLane(dst, 2*i, encodeHexLane(uint32(v)))
+		if 2*i+8 < len(dst) {
+			storeLane(dst, 2*i+8, encodeHexLane(uint32(v>>32)))
+		}
+	}
+	return len(dst)
+}
+
+// DecodeHex decodes the hexadecimal src into dst, accepting either letter case
+// Mirrors encoding/hex.Decode: returns bytes written plus an InvalidByteError or ErrLength
+func DecodeHex(dst, src []byte) (int, error) {
+	n := len(src) / 2
+	dst = dst[:n]
+	src = unaliased(dst, src, true)
+	i := 0
+	for ; i+8 <= len(src); i += 8 {
+		v, ok := decodeHexLane(loadLane(src, i))
+		if !ok {
+			break
+		}
+		storeLane(dst, i/2, uint64(v))
+	}
+	for ; i+1 < len(src); i += 2 {
+		hi, ok := hexValue(src[i])
+		if !ok {
+			return i / 2, hex.InvalidByteError(src[i])
+		}
+		lo, ok := hexValue(src[i+1])
+		if !ok {
+			return i / 2, hex.InvalidByteError(src[i+1])
+		}
+		dst[i/2] = hi<<4 | lo
+	}
+	if len(src)%2 == 1 {
+		if _, ok := hexValue(src[len(src)-1]); !ok {
+			return n, hex.InvalidByteError(src[len(src)-1])
+		}
+		return n, hex.ErrLength
+	}
+	return n, nil
+}
+
+// ValidateHex reports whether b is an even-length run of hexadecimal digits
+// Either letter case is accepted, matching DecodeHex
+func ValidateHex(b []byte) bool {
+	return len(b)%2 == 0 && indexMasked(b, notHexMask) < 0
+}
+
+// NormalizeHexLower lower cases the hexadecimal letters A-F in b in place
+// Every other byte, including G-Z, is left untouched
+func NormalizeHexLower(b []byte) {
+	transformLanes(b, b, func(v uint64) uint64 {
+		upper, _ := HexCaseMasks(v)
+		return v | upper>>2
+	})
+}
+
+// HexCaseMasks sets the high bit of bytes in A-F (upper) and in a-f (lower)
+// A lane holding both is mixed case and must be normalized before comparison
+func HexCaseMasks(v uint64) (upper, lower uint64) {
+	folded := v | 0x2020_2020_2020_2020
+	letters := HighBitWhereGreater(folded, Dupe('a'-1)) & HighBitWhereLess(folded, Dupe('f'+1))
+	lower = letters & (v << 2) // 0x20 moved up to the high bit
+	return letters &^ lower, lower
+}
+
+// IsMixedCaseHex reports whether b contains both A-F and a-f letters
+// Canonical hashes and UUIDs use a single case throughout
+func IsMixedCaseHex(b []byte) bool {
+	var seenUpper, seenLower uint64
+	for i := 0; i < len(b); i += 8 {
+		upper, lower := HexCaseMasks(loadLane(b, i))
+		seenUpper |= upper
+		seenLower |= lower
+	}
+	return seenUpper != 0 && seenLower != 0
+}
+
+// ParseHex16 parses exactly 16 hexadecimal characters as a big-endian uint64
+// Decodes both halves in registers; reports false on bad length or characters
+func ParseHex16(b []byte) (uint64, bool) {
+	if len(b) != 16 {
+		return 0, false
+	}
+	hi, okHi := decodeHexLane(loadLane(b, 0))
+	lo, okLo := decodeHexLane(loadLane(b, 8))
+	if !okHi || !okLo {
+		return 0, false
+	}
+	return uint64(bits.ReverseBytes32(hi))<<32 | uint64(bits.ReverseBytes32(lo)), true
+}
+
+// FormatHex16 formats v as 16 lower case hexadecimal characters with leading zeros
+// The inverse of ParseHex16, as used by W3C traceparent span and trace IDs
+func FormatHex16(v uint64) [16]byte {
+	var out [16]byte
+	storeLane(out[:], 0, encodeHexLane(bits.ReverseBytes32(uint32(v>>32))))
+	storeLane(out[:], 8, encodeHexLane(bits.ReverseBytes32(uint32(v))))
+	return out
+}
+
+// hexDigitMasks sets the high bit of decimal digits and of letters a-f in either case
+func hexDigitMasks(v uint64) (digits, letters uint64) {
+	digits = digitMask(v)
+	folded := v | 0x2020_2020_2020_2020
+	letters = HighBitWhereGreater(folded, Dupe('a'-1)) & HighBitWhereLess(folded, Dupe('f'+1))
+	return digits, letters
+}
+
+// notHexMask sets the high bit of every byte that is not a hexadecimal digit
+func notHexMask(v uint64) uint64 {
+	digits, letters := hexDigitMasks(v)
+	return (digits | letters) ^ HighBits
+}
+
+// encodeHexLane expands 4 bytes into 8 hexadecimal characters, high nibble first
+func encodeHexLane(x uint32) uint64 {
+	v := uint64(x)
+	v = (v | v<<16) & 0x0000_FFFF_0000_FFFF
+	v = (v | v<<8) & 0x00FF_00FF_00FF_00FF
+	n := (v>>4)&0x000F_000F_000F_000F | (v&0x000F_000F_000F_000F)<<8
+	letters := HighBitWhereGreater(n, Dupe(9)) >> 7
+	return n + Dupe('0') + letters*('a'-'0'-10)
+}
+
+// decodeHexLane compacts 8 hexadecimal characters into 4 bytes
+// Reports false if any byte of the lane is not a hexadecimal digit
+func decodeHexLane(v uint64) (uint32, bool) {
+	digits, letters := hexDigitMasks(v)
+	if digits|letters != HighBits {
+		return 0, false
+	}
+	n := v&0x0F0F_0F0F_0F0F_0F0F + (letters>>7)*9
+	w := (n&0x00FF_00FF_00FF_00FF)<<4 | (n>>8)&0x00FF_00FF_00FF_00FF
+	w = (w | w>>8) & 0x0000_FFFF_0000_FFFF
+	w = (w | w>>16) & 0xFFFF_FFFF
+	return uint32(w), true
+}
+
+// hexValue converts a single hexadecimal character to its value
+func hexValue(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c|0x20 >= 'a' && c|0x20 <= 'f':
+		return (c | 0x20) - 'a' + 10, true
+	}
+	return 0, false
+}
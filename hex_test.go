@@ -0,0 +1,150 @@
+package swar
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+)
+
+// TestEncodeHex verifies that hex encoding matches encoding/hex for every length around
+// the lane boundaries. Each output lane is built from half an input lane, so lengths
+// that are not multiples of 4 or 8 exercise the partial stores.
+func TestEncodeHex(t *testing.T) {
+	src := make([]byte, 40)
+	for i := range src {
+		src[i] = byte(i*37 + 11)
+	}
+	for n := 0; n <= len(src); n++ {
+		dst := make([]byte, 2*n+3)
+		if got := EncodeHex(dst, src[:n]); got != 2*n {
+			t.Errorf("EncodeHex(len %d) returned %d; want %d", n, got, 2*n)
+		}
+		if want := hex.EncodeToString(src[:n]); string(dst[:2*n]) != want {
+			t.Errorf("EncodeHex(%x) = %q; want %q", src[:n], dst[:2*n], want)
+		}
+		if string(dst[2*n:]) != "\x00\x00\x00" {
+			t.Errorf("EncodeHex(len %d) wrote past the encoding: %q", n, dst[2*n:])
+		}
+	}
+}
+
+// TestDecodeHex verifies that decoding matches encoding/hex in both the written bytes and
+// the reported errors. Errors are resolved to the exact offending byte even when they are
+// first detected a whole lane at a time.
+func TestDecodeHex(t *testing.T) {
+	run := func(src string) {
+		dst := make([]byte, len(src)/2)
+		want := make([]byte, len(src)/2)
+		n, err := DecodeHex(dst, []byte(src))
+		wantN, wantErr := hex.Decode(want, []byte(src))
+		if n != wantN || err != wantErr || string(dst[:n]) != string(want[:wantN]) {
+			t.Errorf("DecodeHex(%q) = %x, %v; want %x, %v", src, dst[:n], err, want[:wantN], wantErr)
+		}
+	}
+
+	run("")
+	run("00")
+	run("0123456789abcdef")
+	run("0123456789ABCDEFabcdefABCDEF0a1B")
+	run("deadbeefcafebabe0")
+	run("deadbeefcafebabe0g")
+	run("deadbeXfcafebabe")
+	run("deadbeefcafebabe12345G78")
+	run("@`gG/:")
+}
+
+// TestValidateHex verifies that validation accepts exactly the inputs DecodeHex accepts.
+// Every byte value is tried in every position of a lane and in the tail.
+func TestValidateHex(t *testing.T) {
+	base := []byte("0123456789abcdefABCDEF")
+	for i := range base {
+		for c := 0; c < 256; c++ {
+			b := append([]byte(nil), base...)
+			b[i] = byte(c)
+			_, err := hex.DecodeString(string(b))
+			if got, want := ValidateHex(b), err == nil; got != want {
+				t.Errorf("ValidateHex(%q) = %v; want %v", b, got, want)
+			}
+		}
+	}
+	if ValidateHex([]byte("abc")) {
+		t.Errorf("ValidateHex(%q) = true; want false", "abc")
+	}
+}
+
+// TestNormalizeHexLower verifies that only the letters A-F change case. Identifiers that
+// embed other upper case letters must not be altered, and the tail must be normalized too.
+func TestNormalizeHexLower(t *testing.T) {
+	run := func(b, want string) {
+		got := []byte(b)
+		if NormalizeHexLower(got); string(got) != want {
+			t.Errorf("NormalizeHexLower(%q) = %q; want %q", b, got, want)
+		}
+	}
+
+	run("", "")
+	run("DEADBEEF-CAFE", "deadbeef-cafe")
+	run("0123456789ABCDEFGHabcdef@`", "0123456789abcdefGHabcdef@`")
+	run("123E4567-E89B-12D3-A456-426614174000", "123e4567-e89b-12d3-a456-426614174000")
+}
+
+// TestHexCaseMasks verifies per-byte detection of upper and lower case hex letters and the
+// slice-level mixed case check built on it. Neighbouring bytes such as '@' and 'G' must
+// not be classified as hex letters.
+func TestHexCaseMasks(t *testing.T) {
+	run := func(v, wantUpper, wantLower uint64) {
+		if upper, lower := HexCaseMasks(v); upper != wantUpper || lower != wantLower {
+			t.Errorf("HexCaseMasks(0x%016x) = 0x%016x, 0x%016x; want 0x%016x, 0x%016x", v, upper, lower, wantUpper, wantLower)
+		}
+	}
+
+	run(LanesToInt([8]byte{'A', 'a', 'F', 'f', 'G', 'g', '@', '`'}), 0x00_00_00_00_00_80_00_80, 0x00_00_00_00_80_00_80_00)
+	run(Dupe('0'), 0, 0)
+
+	mixed := func(b string, want bool) {
+		if got := IsMixedCaseHex([]byte(b)); got != want {
+			t.Errorf("IsMixedCaseHex(%q) = %v; want %v", b, got, want)
+		}
+	}
+	mixed("deadbeef", false)
+	mixed("DEADBEEF", false)
+	mixed("DEADBEEFcafe", true)
+	mixed("0123456789abcdeF", true)
+	mixed("GHIJ ghij", false)
+}
+
+// TestParseHex16 verifies fixed-width hex parsing against strconv, including both letter
+// cases, the byte order of the two halves and rejection of wrong lengths or characters.
+func TestParseHex16(t *testing.T) {
+	run := func(s string, want uint64, wantOK bool) {
+		if got, ok := ParseHex16([]byte(s)); got != want || ok != wantOK {
+			t.Errorf("ParseHex16(%q) = 0x%016x, %v; want 0x%016x, %v", s, got, ok, want, wantOK)
+		}
+	}
+
+	run("0000000000000000", 0, true)
+	run("0123456789abcdef", 0x0123456789abcdef, true)
+	run("FEDCBA9876543210", 0xfedcba9876543210, true)
+	run("00f067aa0ba902b7", 0x00f067aa0ba902b7, true)
+	run("ffffffffffffffff", 0xffffffffffffffff, true)
+	run("0123456789abcde", 0, false)
+	run("0123456789abcdef0", 0, false)
+	run("0123456789abcdeg", 0, false)
+	run("g123456789abcdef", 0, false)
+}
+
+// TestFormatHex16 verifies that formatting matches strconv with zero padding and that
+// every formatted value parses back to itself.
+func TestFormatHex16(t *testing.T) {
+	for v := uint64(0); v < 0xFF_FF_FF_FF_FF; v = (v*12 + 13) / 11 {
+		for _, x := range []uint64{v, v << 24, ^v} {
+			got := FormatHex16(x)
+			if want := fmt.Sprintf("%016x", x); string(got[:]) != want {
+				t.Errorf("FormatHex16(0x%016x) = %q; want %q", x, got, want)
+			}
+			if back, ok := ParseHex16(got[:]); !ok || back != x {
+				t.Errorf("ParseHex16(FormatHex16(0x%016x)) = 0x%016x, %v", x, back, ok)
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package swar
+
+import "math/bits"
+
+// tokenChars matches the RFC 7230 tchar set used in HTTP methods and header names
+var tokenChars = NewRangeMatcher(
+	[2]byte{'!', '!'}, [2]byte{'#', '\''}, [2]byte{'*', '+'}, [2]byte{'-', '.'},
+	[2]byte{'0', '9'}, [2]byte{'A', 'Z'}, [2]byte{'^', '`'}, [2]byte{'a', 'z'},
+	[2]byte{'|', '|'}, [2]byte{'~', '~'},
+)
+
+// IndexHeaderEnd returns the index of the "\r\n\r\n" that ends an HTTP/1 header block
+// Compares four overlapping lanes at once; returns -1 if the block is incomplete
+func IndexHeaderEnd(b []byte) int {
+	for i := 0; i < len(b); i += 8 {
+		m := HighBitWhereEqual(loadLane(b, i), Dupe('\r')) &
+			HighBitWhereEqual(loadLane(b, i+1), Dupe('\n')) &
+			HighBitWhereEqual(loadLane(b, i+2), Dupe('\r')) &
+			HighBitWhereEqual(loadLane(b, i+3), Dupe('\n'))
+		if m != 0 {
+			return i + bits.TrailingZeros64(m)>>3
+		}
+	}
+	return -1
+}
+
+// IndexCRLF returns the index of the first "\r\n" in b, or -1 if there is none
+// Suits splitting request and status lines and header fields
+func IndexCRLF(b []byte) int {
+	for i := 0; i < len(b); i += 8 {
+		m := HighBitWhereEqual(loadLane(b, i), Dupe('\r')) & HighBitWhereEqual(loadLane(b, i+1), Dupe('\n'))
+		if m != 0 {
+			return i + bits.TrailingZeros64(m)>>3
+		}
+	}
+	return -1
+}
+
+// IndexColon returns the index of the first ':' in b, or -1 if there is none
+// Separates a header field name from its value
+func IndexColon(b []byte) int {
+	return indexMasked(b, func(v uint64) uint64 {
+		return HighBitWhereEqual(v, Dupe(':'))
+	})
+}
+
+// HighBitWhereTokenChar sets the high bit (0x80) in each byte that is an RFC 7230 tchar
+// Letters, digits and !#$%&'*+-.^_`|~ are token characters
+func HighBitWhereTokenChar(v uint64) uint64 {
+	return tokenChars.Mask(v)
+}
+
+// IsToken reports whether b is a non-empty RFC 7230 token, such as a header field name
+// Checks a whole lane of bytes per step
+func IsToken(b []byte) bool {
+	return len(b) > 0 && indexMasked(b, func(v uint64) uint64 {
+		return tokenChars.Mask(v) ^ HighBits
+	}) < 0
+}
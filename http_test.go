@@ -0,0 +1,83 @@
+package swar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestIndexHeaderEnd verifies that the end of a header block is found at every alignment
+// and that partial terminators, including one cut off by the end of input, are ignored.
+func TestIndexHeaderEnd(t *testing.T) {
+	run := func(b string) {
+		if got, want := IndexHeaderEnd([]byte(b)), strings.Index(b, "\r\n\r\n"); got != want {
+			t.Errorf("IndexHeaderEnd(%q) = %d; want %d", b, got, want)
+		}
+	}
+
+	run("")
+	run("\r\n\r\n")
+	run("GET / HTTP/1.1\r\nHost: example.com\r\n\r\nbody")
+	run("GET / HTTP/1.1\r\nHost: example.com\r\n\r")
+	run("\r\n\r\r\n\n\r\n\r\n")
+	for i := 0; i < 20; i++ {
+		run(strings.Repeat("h", i) + "\r\n\r" + strings.Repeat("x", 20-i) + "\r\n\r\n")
+	}
+}
+
+// TestIndexCRLF verifies locating CRLF pairs that straddle lanes while skipping a lone
+// '\r' or '\n'.
+func TestIndexCRLF(t *testing.T) {
+	run := func(b string) {
+		if got, want := IndexCRLF([]byte(b)), strings.Index(b, "\r\n"); got != want {
+			t.Errorf("IndexCRLF(%q) = %d; want %d", b, got, want)
+		}
+	}
+
+	run("")
+	run("\r")
+	run("\n\r")
+	run("1234567\r\n")
+	run("a\rb\nc\r\rd\r\n")
+}
+
+// TestIndexColon verifies locating the header name separator in full lanes and tails.
+func TestIndexColon(t *testing.T) {
+	run := func(b string) {
+		if got, want := IndexColon([]byte(b)), strings.IndexByte(b, ':'); got != want {
+			t.Errorf("IndexColon(%q) = %d; want %d", b, got, want)
+		}
+	}
+
+	run("")
+	run("Host: example.com")
+	run("X-A-Very-Long-Header-Name: value")
+	run("no colon here")
+}
+
+// TestHighBitWhereTokenChar verifies the tchar classifier for every byte value against the
+// RFC 7230 grammar, and token validation built on it.
+func TestHighBitWhereTokenChar(t *testing.T) {
+	const tchars = "!#$%&'*+-.^_`|~0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	for c := 0; c < 256; c++ {
+		want := uint64(0)
+		if bytes.IndexByte([]byte(tchars), byte(c)) >= 0 {
+			want = HighBits
+		}
+		if got := HighBitWhereTokenChar(Dupe(byte(c))); got != want {
+			t.Errorf("HighBitWhereTokenChar(%q) = 0x%016x; want 0x%016x", c, got, want)
+		}
+	}
+
+	run := func(b string, want bool) {
+		if got := IsToken([]byte(b)); got != want {
+			t.Errorf("IsToken(%q) = %v; want %v", b, got, want)
+		}
+	}
+	run("", false)
+	run("Content-Type", true)
+	run("X-Forwarded-For", true)
+	run("Bad Header", false)
+	run("Bad:Header", false)
+	run("Header\x00", false)
+}
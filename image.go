@@ -0,0 +1,261 @@
+package swar
+
+// alphaSpread copies the alpha byte of each RGBA pixel in v to all four of its bytes
+// A lane holds two pixels with alpha in bytes 3 and 7
+func alphaSpread(v uint64) uint64 {
+	return v >> 24 & 0x0000_00FF_0000_00FF * 0x0101_0101
+}
+
+// BlendRGBA composites premultiplied RGBA pixels of src over dst in place
+// Each channel becomes src + dst*(255-srcAlpha)/255; covers the common length of dst and src
+func BlendRGBA(dst, src []byte) {
+	transformLanes2(dst, src, dst, func(s, d uint64) uint64 {
+		// Saturating so non-premultiplied input clips instead of carrying into the next channel
+		return AddBytesWithMaximum(s, MultiplyBytesNormalized(d, ^alphaSpread(s)))
+	})
+}
+
+// Blend writes (a*weightA + b*(255-weightA))/255 rounded to dst for each pair of bytes
+// Covers the common length of a and b, which dst must hold; for crossfades and opacity layers
+func Blend(dst, a, b []byte, weightA uint8) {
+	wa, wb := uint64(weightA), uint64(255-weightA)
+	transformLanes2(dst, a, b, func(x, y uint64) uint64 {
+		// Both weighted sums stay within 255*255, so each fits its 16-bit slot
+		even := divide255Slots((x&mEven)*wa + (y&mEven)*wb)
+		odd := divide255Slots((x>>8&mEven)*wa + (y>>8&mEven)*wb)
+		return even | odd<<8
+	})
+}
+
+// PremultiplyAlpha scales the colour channels of straight RGBA pixels by their alpha
+// Alpha itself is kept; dst must hold len(src) bytes and may equal src
+func PremultiplyAlpha(dst, src []byte) {
+	transformLanes(dst, src, func(v uint64) uint64 {
+		const alpha = 0xFF00_0000_FF00_0000
+		return MultiplyBytesNormalized(v, alphaSpread(v)|alpha)
+	})
+}
+
+// RGBAToGray writes the luma of each RGBA pixel in src to one byte of dst
+// Uses BT.601 weights 77/150/29 out of 256; dst must hold len(src)/4 bytes
+func RGBAToGray(dst, src []byte) {
+	n := len(src) / 4
+	dst = dst[:n]
+	src = unaliased(dst, src, true)
+	for i := 0; i < n; i += 2 {
+		g := grayPair(loadLane(src[:4*n], 4*i))
+		dst[i] = byte(g)
+		if i+1 < n {
+			dst[i+1] = byte(g >> 32)
+		}
+	}
+}
+
+// grayPair returns the luma of the two pixels of v in bits 0-7 and 32-39
+// One multiply per 16-bit slot set gathers R*77+B*29 and G*150 into the same slot
+func grayPair(v uint64) uint64 {
+	const slots = 0x00FF_00FF_00FF_00FF
+	rb := (v & slots) * (29 | 77<<16)
+	ga := (v >> 8 & slots) * (150 << 16)
+	return (rb + ga + 0x0080_0000_0080_0000) >> 24 & 0x0000_00FF_0000_00FF
+}
+
+// Threshold writes 0xFF to dst where src is above t and 0x00 elsewhere
+// Binarises a greyscale image; dst must hold len(src) bytes and may equal src
+func Threshold(dst, src []byte, t byte) {
+	limit := Dupe(t)
+	transformLanes(dst, src, func(v uint64) uint64 {
+		return HighBitWhereGreater(v, limit) >> 7 * 0xFF
+	})
+}
+
+// ThresholdToBitmap sets bit i of dst where src[i] is above t, one bit per pixel
+// Same layout as MatchBitmap; dst must hold (len(src)+63)/64 words
+func ThresholdToBitmap(dst []uint64, src []byte, t byte) {
+	limit := Dupe(t)
+	matchBitmapInto(dst, src, func(v uint64) uint64 {
+		return HighBitWhereGreater(v, limit)
+	})
+}
+
+// AdjustBrightness adds delta to every byte of src, clamping at 0 and 255
+// Image-safe: saturating arithmetic never wraps bright pixels to black; dst may equal src
+func AdjustBrightness(dst, src []byte, delta int8) {
+	if delta >= 0 {
+		d := Dupe(byte(delta))
+		transformLanes(dst, src, func(v uint64) uint64 { return AddBytesWithMaximum(v, d) })
+		return
+	}
+	d := Dupe(byte(-int(delta)))
+	transformLanes(dst, src, func(v uint64) uint64 { return SubtractBytesWithMinimum(v, d) })
+}
+
+// AddDither adds pattern[8*(y%8)+x%8] to pixel (x, y) of a greyscale image width bytes wide, saturating
+// An 8x8 Bayer matrix added before dropping low bits trades banding for noise; dst may equal src
+func AddDither(dst, src []byte, width int, pattern *[64]byte) {
+	if width <= 0 {
+		panic("swar: AddDither with non-positive width")
+	}
+	var rows [8]uint64
+	for r := range rows {
+		rows[r] = DupePattern(pattern[8*r : 8*r+8])
+	}
+	dst = dst[:len(src)]
+	src = unaliased(dst, src, true)
+	for y, start := 0, 0; start < len(src); y, start = y+1, start+width {
+		end := min(start+width, len(src))
+		in, out, row := src[start:end], dst[start:end], rows[y%8]
+		for x := 0; x < len(in); x += 8 {
+			storeLane(out, x, AddBytesWithMaximum(loadLane(in, x), row))
+		}
+	}
+}
+
+// QuantizeBytes writes the bucket index of each byte of src to dst, using levels equal-width buckets
+// levels runs from 1 to 256; indices pick palette entries or sensor bins. dst may equal src
+func QuantizeBytes(dst, src []byte, levels int) {
+	if levels < 1 || levels > 256 {
+		panic("swar: QuantizeBytes with levels outside 1 to 256")
+	}
+	transformLanes(dst, src, func(v uint64) uint64 {
+		return QuantizeLane(v, levels, false)
+	})
+}
+
+// AdjustContrast scales the distance of every byte from 128 by num/den, clamping at 0 and 255
+// Image-safe and rounded to nearest; num > den raises contrast, num < den lowers it; panics if den is 0
+func AdjustContrast(dst, src []byte, num, den uint8) {
+	if den == 0 {
+		panic("swar: AdjustContrast with zero denominator")
+	}
+	div := newDivider15(uint64(den))
+	bias := Dupe(0x80)
+	transformLanes(dst, src, func(v uint64) uint64 {
+		up := SubtractBytesWithMinimum(v, bias)
+		down := SubtractBytesWithMinimum(bias, v)
+		up = scaleRatio(up, num, den, div)
+		down = scaleRatio(down, num, den, div)
+		return SubtractBytesWithMinimum(AddBytesWithMaximum(bias, up), down)
+	})
+}
+
+// scaleRatio returns round(b*num/den) for each byte of v, clamped to 255
+// Bytes are spread into 32-bit slots two at a time so the reciprocal product cannot overflow
+func scaleRatio(v uint64, num, den uint8, div divider15) uint64 {
+	const slots = 0x0000_00FF_0000_00FF
+	var out uint64
+	for k := range 4 {
+		p := (v>>(8*k)&slots)*uint64(num) + uint64(den/2)*0x0000_0001_0000_0001
+		q := div.divide(p)
+		// Clamp each slot to 255: any bit above the low byte means overflow
+		over := (q + 0x7FFF_FF00_7FFF_FF00) >> 31 & 0x0000_0001_0000_0001 * 0xFF
+		out |= (q | over) & slots << (8 * k)
+	}
+	return out
+}
+
+// InvertBytes writes 255-x for every byte of src to dst
+// A photographic negative is a single XOR per lane; dst may equal src
+func InvertBytes(dst, src []byte) {
+	transformLanes(dst, src, func(v uint64) uint64 { return ^v })
+}
+
+// SwizzleRGBA reorders the four channels of each pixel so output channel i is input channel order[i]
+// {2, 1, 0, 3} converts RGBA to BGRA and back; entries are taken modulo 4 and a partial last pixel is skipped
+func SwizzleRGBA(dst, src []byte, order [4]byte) {
+	const channel = 0x0000_00FF_0000_00FF
+	transformLanes(dst, src[:len(src)&^3], func(v uint64) uint64 {
+		var out uint64
+		for i, from := range order {
+			out |= v >> (8 * (from & 3)) & channel << (8 * i)
+		}
+		return out
+	})
+}
+
+// ExpandRGB565 converts little-endian RGB565 pixels in src to RGB888 in dst of len(src)/2*3 bytes
+// Channels are widened by bit replication so 0 stays 0 and full scale becomes 255
+func ExpandRGB565(dst, src []byte) {
+	n := len(src) / 2
+	src, dst = src[:2*n], dst[:3*n]
+	src = unaliased(dst, src, false)
+	for p := 0; p < n; p += 4 {
+		// Four pixels per lane, one per 16-bit slot
+		v := loadLane(src, 2*p)
+		r := v >> 11 & (0x1F * slots16)
+		g := v >> 5 & (0x3F * slots16)
+		b := v & (0x1F * slots16)
+		r = r<<3 | r>>2
+		g = g<<2 | g>>4
+		b = b<<3 | b>>2
+		lo := r&0xFF | g&0xFF<<8 | b&0xFF<<16 | r>>16&0xFF<<24 |
+			g>>16&0xFF<<32 | b>>16&0xFF<<40 | r>>32&0xFF<<48 | g>>32&0xFF<<56
+		hi := b>>32&0xFF | r>>48<<8 | g>>48<<16 | b>>48<<24
+		storeLane(dst, 3*p, lo)
+		if 3*p+8 < len(dst) {
+			storeLane(dst, 3*p+8, hi)
+		}
+	}
+}
+
+// PackRGB888To565 converts RGB888 pixels in src to little-endian RGB565 in dst of len(src)/3*2 bytes
+// Low bits of each channel are truncated, so ExpandRGB565 output packs back exactly
+func PackRGB888To565(dst, src []byte) {
+	n := len(src) / 3
+	src, dst = src[:3*n], dst[:2*n]
+	src = unaliased(dst, src, true)
+	for p := 0; p < n; p += 4 {
+		lo, hi := loadLane(src, 3*p), loadLane(src, 3*p+8)
+		r := lo&0xFF | lo>>24&0xFF<<16 | lo>>48&0xFF<<32 | hi>>8&0xFF<<48
+		g := lo>>8&0xFF | lo>>32&0xFF<<16 | lo>>56<<32 | hi>>16&0xFF<<48
+		b := lo>>16&0xFF | lo>>40&0xFF<<16 | hi&0xFF<<32 | hi>>24&0xFF<<48
+		r, g, b = r>>3&(0x1F*slots16), g>>2&(0x3F*slots16), b>>3&(0x1F*slots16)
+		storeLane(dst, 2*p, r<<11|g<<5|b)
+	}
+}
+
+// Interleave4Planes packs R, G, B and A planes into RGBA pixels in dst, 4 bytes per pixel
+// Two rounds of InterleaveBytes build 8 pixels at once; covers the shortest plane
+func Interleave4Planes(dst, r, g, b, a []byte) {
+	n := min(len(r), len(g), len(b), len(a))
+	dst = dst[:4*n]
+	r, g, b, a = unaliased(dst, r, false), unaliased(dst, g, false), unaliased(dst, b, false), unaliased(dst, a, false)
+	i := 0
+	for ; i+8 <= n; i += 8 {
+		rbLo, rbHi := InterleaveBytes(loadLane(r, i), loadLane(b, i))
+		gaLo, gaHi := InterleaveBytes(loadLane(g, i), loadLane(a, i))
+		p0, p1 := InterleaveBytes(rbLo, gaLo)
+		p2, p3 := InterleaveBytes(rbHi, gaHi)
+		storeLane(dst, 4*i, p0)
+		storeLane(dst, 4*i+8, p1)
+		storeLane(dst, 4*i+16, p2)
+		storeLane(dst, 4*i+24, p3)
+	}
+	for ; i < n; i++ {
+		dst[4*i], dst[4*i+1], dst[4*i+2], dst[4*i+3] = r[i], g[i], b[i], a[i]
+	}
+}
+
+// Deinterleave4 splits RGBA pixels from src into separate R, G, B and A planes
+// Inverse of Interleave4Planes; each plane must hold len(src)/4 bytes
+func Deinterleave4(r, g, b, a, src []byte) {
+	n := len(src) / 4
+	r, g, b, a = r[:n], g[:n], b[:n], a[:n]
+	for _, plane := range [][]byte{r, g, b, a} {
+		src = unaliased(plane, src, false)
+	}
+	i := 0
+	for ; i+8 <= n; i += 8 {
+		rbLo, gaLo := DeinterleaveBytes(loadLane(src, 4*i), loadLane(src, 4*i+8))
+		rbHi, gaHi := DeinterleaveBytes(loadLane(src, 4*i+16), loadLane(src, 4*i+24))
+		rv, bv := DeinterleaveBytes(rbLo, rbHi)
+		gv, av := DeinterleaveBytes(gaLo, gaHi)
+		storeLane(r, i, rv)
+		storeLane(g, i, gv)
+		storeLane(b, i, bv)
+		storeLane(a, i, av)
+	}
+	for ; i < n; i++ {
+		r[i], g[i], b[i], a[i] = src[4*i], src[4*i+1], src[4*i+2], src[4*i+3]
+	}
+}
@@ -0,0 +1,346 @@
+package swar
+
+import (
+	"bytes"
+	"testing"
+)
+
+// testPixels returns n RGBA pixels with varied channels and a spread of alpha values,
+// premultiplied when premul is set so the colour channels never exceed alpha.
+func testPixels(n int, seed int, premul bool) []byte {
+	px := make([]byte, 4*n)
+	for i := range n {
+		a := byte(i*53 + seed)
+		if i%7 == 0 {
+			a = 0xFF
+		} else if i%11 == 0 {
+			a = 0
+		}
+		for c := range 3 {
+			v := byte(i*31 + c*97 + seed)
+			if premul {
+				v = byte(int(v) * int(a) / 255)
+			}
+			px[4*i+c] = v
+		}
+		px[4*i+3] = a
+	}
+	return px
+}
+
+// TestBlendRGBA compares src-over compositing with the per-channel formula for pixel
+// counts that leave a half-filled final lane, which must not disturb dst past the end.
+func TestBlendRGBA(t *testing.T) {
+	for n := 0; n < 40; n++ {
+		src := testPixels(n, 5, true)
+		dst := testPixels(n+1, 99, true)
+		want := append([]byte(nil), dst...)
+		for i := range src {
+			sa := int(src[i|3])
+			want[i] = byte(int(src[i]) + (int(dst[i])*(255-sa)+127)/255)
+		}
+		BlendRGBA(dst, src)
+		for i := range want {
+			if dst[i] != want[i] {
+				t.Errorf("BlendRGBA(%d pixels) byte %d = %d; want %d", n, i, dst[i], want[i])
+			}
+		}
+	}
+}
+
+// TestBlend compares the crossfade with the rounded per-byte formula at every weight, so
+// weights 255 and 0 must reproduce a and b exactly, over lengths that end mid-lane.
+func TestBlend(t *testing.T) {
+	a := testPixels(9, 3, false)
+	b := testPixels(9, 41, false)
+	for w := range 256 {
+		for _, n := range []int{0, 5, 8, len(a)} {
+			dst := make([]byte, n)
+			Blend(dst, a[:n], b, uint8(w))
+			for i := range dst {
+				want := byte((int(a[i])*w + int(b[i])*(255-w) + 127) / 255)
+				if dst[i] != want {
+					t.Errorf("Blend(%d, %d, weight %d) = %d; want %d", a[i], b[i], w, dst[i], want)
+				}
+			}
+		}
+	}
+}
+
+// TestPremultiplyAlpha compares with rounded per-channel scaling and checks that alpha
+// is untouched, including fully opaque and fully transparent pixels.
+func TestPremultiplyAlpha(t *testing.T) {
+	for n := 0; n < 40; n++ {
+		src := testPixels(n, 17, false)
+		dst := make([]byte, len(src))
+		PremultiplyAlpha(dst, src)
+		for i, c := range src {
+			want := c
+			if i%4 != 3 {
+				want = byte((int(c)*int(src[i|3]) + 127) / 255)
+			}
+			if dst[i] != want {
+				t.Errorf("PremultiplyAlpha(%d pixels) byte %d = %d; want %d", n, i, dst[i], want)
+			}
+		}
+	}
+}
+
+// TestRGBAToGray compares with the fixed-point luma formula for odd and even pixel
+// counts, and checks that white, black and grey survive conversion unchanged.
+func TestRGBAToGray(t *testing.T) {
+	for n := 0; n < 40; n++ {
+		src := testPixels(n, 3, false)
+		dst := make([]byte, n+1)
+		RGBAToGray(dst, src)
+		for i := range n {
+			r, g, b := int(src[4*i]), int(src[4*i+1]), int(src[4*i+2])
+			if want := byte((77*r + 150*g + 29*b + 128) >> 8); dst[i] != want {
+				t.Errorf("RGBAToGray pixel %d of %d = %d; want %d", i, n, dst[i], want)
+			}
+		}
+		if dst[n] != 0 {
+			t.Errorf("RGBAToGray(%d pixels) wrote past the end", n)
+		}
+	}
+	for _, c := range []byte{0, 0x80, 0xFF} {
+		dst := make([]byte, 1)
+		RGBAToGray(dst, []byte{c, c, c, 0xFF})
+		if dst[0] != c {
+			t.Errorf("RGBAToGray(grey %d) = %d; want %d", c, dst[0], c)
+		}
+	}
+}
+
+// TestThreshold checks both output forms against a scalar comparison at the extremes and
+// in between, so t=255 yields nothing and t=0 keeps every non-zero pixel.
+func TestThreshold(t *testing.T) {
+	src := make([]byte, 300)
+	for i := range src {
+		src[i] = byte(i * 7)
+	}
+	for _, limit := range []byte{0, 1, 100, 127, 128, 254, 255} {
+		for _, n := range []int{0, 5, 64, 100, len(src)} {
+			dst := make([]byte, n)
+			Threshold(dst, src[:n], limit)
+			bitmap := make([]uint64, (n+63)/64)
+			ThresholdToBitmap(bitmap, src[:n], limit)
+			for i, c := range src[:n] {
+				want := byte(0)
+				if c > limit {
+					want = 0xFF
+				}
+				if dst[i] != want {
+					t.Errorf("Threshold(%d, %d) = 0x%02x; want 0x%02x", c, limit, dst[i], want)
+				}
+				if bit := byte(bitmap[i/64]>>(i%64)&1) * 0xFF; bit != want {
+					t.Errorf("ThresholdToBitmap(%d, %d) bit = %d; want %d", c, limit, bit&1, want&1)
+				}
+			}
+		}
+	}
+}
+
+// TestAdjustBrightness checks every byte value at the extremes of delta, where the
+// saturating arithmetic must clamp rather than wrap.
+func TestAdjustBrightness(t *testing.T) {
+	src := make([]byte, 256+3)
+	for i := range src {
+		src[i] = byte(i)
+	}
+	for _, delta := range []int8{-128, -100, -1, 0, 1, 50, 127} {
+		dst := make([]byte, len(src))
+		AdjustBrightness(dst, src, delta)
+		for i, c := range src {
+			if want := byte(min(max(int(c)+int(delta), 0), 255)); dst[i] != want {
+				t.Errorf("AdjustBrightness(%d, %d) = %d; want %d", c, delta, dst[i], want)
+			}
+		}
+	}
+}
+
+// TestAddDither compares with a saturating per-pixel add of the matrix entry for each
+// pixel's row and column, for widths below, at and above a lane, widths that leave a row
+// ending mid-lane, and a short final row, both into a separate buffer and in place, and
+// checks that a zero width panics.
+func TestAddDither(t *testing.T) {
+	var pattern [64]byte
+	for i := range pattern {
+		pattern[i] = byte(i * 37 % 64)
+	}
+	src := make([]byte, 150)
+	for i := range src {
+		src[i] = byte(i * 29)
+	}
+	for _, width := range []int{1, 3, 8, 13, 16, 150} {
+		for _, n := range []int{0, 5, 8, 64, 71, len(src)} {
+			want := make([]byte, n)
+			for i := range want {
+				x, y := i%width, i/width
+				want[i] = byte(min(int(src[i])+int(pattern[8*(y%8)+x%8]), 255))
+			}
+			dst := make([]byte, n)
+			if AddDither(dst, src[:n], width, &pattern); !bytes.Equal(dst, want) {
+				t.Errorf("AddDither(len %d, width %d) = %v; want %v", n, width, dst, want)
+			}
+			inPlace := bytes.Clone(src[:n])
+			if AddDither(inPlace, inPlace, width, &pattern); !bytes.Equal(inPlace, want) {
+				t.Errorf("AddDither(len %d, width %d) in place = %v; want %v", n, width, inPlace, want)
+			}
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("AddDither(width 0) did not panic")
+		}
+	}()
+	AddDither(src, src, 0, &pattern)
+}
+
+// TestQuantizeBytes checks bucket indices over every byte value for a few level counts,
+// including 1 and 256, and that out of range level counts panic instead of wrapping.
+func TestQuantizeBytes(t *testing.T) {
+	src := make([]byte, 256)
+	for i := range src {
+		src[i] = byte(i)
+	}
+	for _, levels := range []int{1, 2, 3, 16, 100, 256} {
+		dst := make([]byte, len(src))
+		QuantizeBytes(dst, src, levels)
+		for i, c := range dst {
+			if want := byte(i * levels / 256); c != want {
+				t.Errorf("QuantizeBytes(%d, %d levels) = %d; want %d", i, levels, c, want)
+			}
+		}
+	}
+	for _, levels := range []int{0, 257} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("QuantizeBytes(%d levels) did not panic", levels)
+				}
+			}()
+			QuantizeBytes(src, src, levels)
+		}()
+	}
+}
+
+// TestAdjustContrast checks every byte value for a range of ratios against rounded,
+// clamped integer arithmetic. Denominators up to 255 exercise the reciprocal's precision.
+func TestAdjustContrast(t *testing.T) {
+	src := make([]byte, 256+3)
+	for i := range src {
+		src[i] = byte(i)
+	}
+	for _, num := range []int{0, 1, 2, 3, 100, 128, 200, 255} {
+		for _, den := range []int{1, 2, 3, 7, 100, 127, 128, 200, 255} {
+			dst := make([]byte, len(src))
+			AdjustContrast(dst, src, uint8(num), uint8(den))
+			for i, c := range src {
+				d := int(c) - 128
+				q := (max(d, -d)*num + den/2) / den
+				if d < 0 {
+					q = -q
+				}
+				if want := byte(min(max(128+q, 0), 255)); dst[i] != want {
+					t.Errorf("AdjustContrast(%d, %d, %d) = %d; want %d", c, num, den, dst[i], want)
+				}
+			}
+		}
+	}
+}
+
+// TestInvertSwizzle checks inversion on every byte value and channel reordering for
+// common layouts plus a broadcast order that duplicates one channel.
+func TestInvertSwizzle(t *testing.T) {
+	src := make([]byte, 256+4)
+	for i := range src {
+		src[i] = byte(i*13 + 7)
+	}
+	dst := make([]byte, len(src))
+	InvertBytes(dst, src)
+	for i, c := range src {
+		if dst[i] != 255-c {
+			t.Errorf("InvertBytes(%d) = %d; want %d", c, dst[i], 255-c)
+		}
+	}
+
+	for _, order := range [][4]byte{{0, 1, 2, 3}, {2, 1, 0, 3}, {3, 0, 1, 2}, {1, 1, 1, 1}} {
+		for _, n := range []int{0, 4, 8, 12, 36, len(src)} {
+			dst := make([]byte, n)
+			SwizzleRGBA(dst, src[:n], order)
+			for i := range dst {
+				if want := src[i&^3+int(order[i&3])]; dst[i] != want {
+					t.Errorf("SwizzleRGBA(%v) byte %d of %d = %d; want %d", order, i, n, dst[i], want)
+				}
+			}
+		}
+	}
+}
+
+// TestRGB565 checks every RGB565 value against scalar bit replication and that packing
+// the expanded result gives back the original, including a partial final lane.
+func TestRGB565(t *testing.T) {
+	src := make([]byte, 2*65536)
+	for w := range 65536 {
+		src[2*w], src[2*w+1] = byte(w), byte(w>>8)
+	}
+	for _, n := range []int{0, 1, 3, 4, 5, 65536} {
+		rgb := make([]byte, 3*n)
+		ExpandRGB565(rgb, src[:2*n])
+		for w := range n {
+			r, g, b := w>>11, w>>5&0x3F, w&0x1F
+			want := [3]byte{byte(r<<3 | r>>2), byte(g<<2 | g>>4), byte(b<<3 | b>>2)}
+			if got := [3]byte(rgb[3*w:]); got != want {
+				t.Errorf("ExpandRGB565(0x%04x) = %v; want %v", w, got, want)
+			}
+		}
+		packed := make([]byte, 2*n)
+		PackRGB888To565(packed, rgb)
+		if !bytes.Equal(packed, src[:2*n]) {
+			t.Errorf("PackRGB888To565(ExpandRGB565(%d pixels)) did not round trip", n)
+		}
+	}
+
+	rgb := []byte{0xFF, 0x80, 0x07, 0x12, 0x34, 0x56, 0, 0}
+	packed := make([]byte, 4)
+	PackRGB888To565(packed, rgb)
+	for p := range 2 {
+		r, g, b := int(rgb[3*p]), int(rgb[3*p+1]), int(rgb[3*p+2])
+		want := r>>3<<11 | g>>2<<5 | b>>3
+		if got := int(packed[2*p]) | int(packed[2*p+1])<<8; got != want {
+			t.Errorf("PackRGB888To565(%v) = 0x%04x; want 0x%04x", rgb[3*p:3*p+3], got, want)
+		}
+	}
+}
+
+// TestInterleave4Planes converts planes to packed pixels and back for pixel counts on
+// both sides of the 8-pixel block, comparing with a per-pixel copy.
+func TestInterleave4Planes(t *testing.T) {
+	for n := 0; n < 40; n++ {
+		planes := [4][]byte{}
+		for c := range planes {
+			planes[c] = make([]byte, n)
+			for i := range planes[c] {
+				planes[c][i] = byte(i*7 + c*61)
+			}
+		}
+		packed := make([]byte, 4*n)
+		Interleave4Planes(packed, planes[0], planes[1], planes[2], planes[3])
+		for i := range packed {
+			if want := planes[i%4][i/4]; packed[i] != want {
+				t.Errorf("Interleave4Planes(%d pixels) byte %d = %d; want %d", n, i, packed[i], want)
+			}
+		}
+		var split [4][]byte
+		for c := range split {
+			split[c] = make([]byte, n)
+		}
+		Deinterleave4(split[0], split[1], split[2], split[3], packed)
+		for c := range split {
+			if !bytes.Equal(split[c], planes[c]) {
+				t.Errorf("Deinterleave4(%d pixels) plane %d = %v; want %v", n, c, split[c], planes[c])
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package swar
+
+import "math/bits"
+
+// evenBits selects the even bit positions of a 64-bit bitmap
+const evenBits uint64 = 0x5555_5555_5555_5555
+
+// JSONIndex holds bitmaps with one bit per input byte: bit i%64 of word i/64 is b[i]
+// It is the output of stage 1 of a simdjson-style parser
+type JSONIndex struct {
+	// Structural marks { } [ ] , : outside strings and every unescaped quote
+	Structural []uint64
+	// Backslash marks every '\' byte, escaped or not
+	Backslash []uint64
+	// Quoted marks bytes inside strings, including the opening but not the closing quote
+	Quoted []uint64
+}
+
+// ScanJSONStructure indexes the structural characters, backslashes and strings of b
+// Works on 64-byte blocks, carrying escape and in-string state between them
+func ScanJSONStructure(b []byte) JSONIndex {
+	words := (len(b) + 63) / 64
+	idx := JSONIndex{
+		Structural: make([]uint64, words),
+		Backslash:  make([]uint64, words),
+		Quoted:     make([]uint64, words),
+	}
+	var escaped, inString bool
+	for w := range words {
+		var backslash, quote, op uint64
+		for k := 0; k < 8 && 64*w+8*k < len(b); k++ {
+			v := loadLane(b, 64*w+8*k)
+			shift := uint(8 * k)
+			backslash |= uint64(ExtractLowBits(HighBitWhereEqual(v, Dupe('\\'))>>7)) << shift
+			quote |= uint64(ExtractLowBits(HighBitWhereEqual(v, Dupe('"'))>>7)) << shift
+			op |= uint64(ExtractLowBits(jsonOperatorMask(v)>>7)) << shift
+		}
+		quote &^= escapedBits(backslash, 1, &escaped)
+		quoted := quotedBits(quote, &inString)
+		if rest := len(b) - 64*w; rest < 64 {
+			quoted &= uint64(1)<<rest - 1
+		}
+		idx.Backslash[w] = backslash
+		idx.Quoted[w] = quoted
+		idx.Structural[w] = op&^quoted | quote
+	}
+	return idx
+}
+
+// jsonOperatorMask sets the high bit of every { } [ ] , and : byte
+func jsonOperatorMask(v uint64) uint64 {
+	folded := v | 0x2020_2020_2020_2020 // folds [ and ] onto { and }
+	return HighBitWhereEqual(folded, Dupe('{')) | HighBitWhereEqual(folded, Dupe('}')) |
+		HighBitWhereEqual(v, Dupe(',')) | HighBitWhereEqual(v, Dupe(':'))
+}
+
+// EscapedCharMask sets the high bit of each byte in chunk escaped by a backslash
+// *carryIn says whether the first byte is escaped and is updated for the next chunk
+func EscapedCharMask(chunk uint64, carryIn *bool) uint64 {
+	backslash := uint64(ExtractLowBits(HighBitWhereEqual(chunk, Dupe('\\')) >> 7))
+	escaped := escapedBits(backslash<<56, 1<<56, carryIn) >> 56
+	return SpreadBitsToHighBits(byte(escaped))
+}
+
+// QuoteRegionMask sets the high bit of each byte inside a quoted region of a lane
+// quotes marks unescaped quotes; *inQuote carries the open-string state between chunks
+func QuoteRegionMask(quotes uint64, inQuote *bool) uint64 {
+	q := uint64(ExtractLowBits(quotes>>7&LowBits)) << 56
+	return SpreadBitsToHighBits(byte(quotedBits(q, inQuote) >> 56))
+}
+
+// escapedBits marks bytes after an odd-length backslash run; runs start at bit first and end by bit 63
+// Runs are measured with a carrying add, and *escaped carries a pending escape between blocks
+func escapedBits(backslash, first uint64, escaped *bool) uint64 {
+	var prev uint64
+	if *escaped {
+		prev = first
+	}
+	backslash &^= prev // an escaped backslash does not start a run
+	followsEscape := backslash<<1 | prev
+	oddStarts := backslash &^ evenBits &^ followsEscape
+	sequencesStartingOnEven, carry := bits.Add64(oddStarts, backslash, 0)
+	invert := sequencesStartingOnEven << 1
+	*escaped = carry == 1
+	return (evenBits ^ invert) & followsEscape
+}
+
+// quotedBits turns a bitmap of unescaped quotes into a bitmap of in-string bytes
+// A running XOR toggles at every quote; *inString carries the state between blocks
+func quotedBits(quote uint64, inString *bool) uint64 {
+	x := PrefixXorBits(quote)
+	if *inString {
+		x = ^x
+	}
+	*inString = x>>63 == 1
+	return x
+}
@@ -0,0 +1,115 @@
+package swar
+
+import (
+	"strings"
+	"testing"
+)
+
+// scanJSONReference is a byte-at-a-time stage 1 scanner used to check the bitmaps
+func scanJSONReference(b []byte) (structural, backslash, quoted []bool) {
+	structural, backslash, quoted = make([]bool, len(b)), make([]bool, len(b)), make([]bool, len(b))
+	inString, escaped := false, false
+	for i, c := range b {
+		backslash[i] = c == '\\'
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '"':
+			structural[i] = true
+			inString = !inString
+			quoted[i] = inString
+			continue
+		case !inString && strings.IndexByte("{}[],:", c) >= 0:
+			structural[i] = true
+		}
+		quoted[i] = inString
+	}
+	return structural, backslash, quoted
+}
+
+// TestScanJSONStructure verifies the structural, backslash and in-string bitmaps against a
+// byte-at-a-time scanner. Backslash runs of every length and strings that cross 64-byte
+// blocks check that escape and quote state is carried correctly.
+func TestScanJSONStructure(t *testing.T) {
+	run := func(doc string) {
+		idx := ScanJSONStructure([]byte(doc))
+		structural, backslash, quoted := scanJSONReference([]byte(doc))
+		if words := (len(doc) + 63) / 64; len(idx.Structural) != words || len(idx.Backslash) != words || len(idx.Quoted) != words {
+			t.Fatalf("ScanJSONStructure(%q) returned %d/%d/%d words; want %d", doc, len(idx.Structural), len(idx.Backslash), len(idx.Quoted), words)
+		}
+		bit := func(m []uint64, i int) bool { return m[i/64]>>(i%64)&1 == 1 }
+		for i := range doc {
+			if bit(idx.Structural, i) != structural[i] || bit(idx.Backslash, i) != backslash[i] || bit(idx.Quoted, i) != quoted[i] {
+				t.Errorf("ScanJSONStructure(%q) byte %d (%q) = %v/%v/%v; want %v/%v/%v", doc, i, doc[i],
+					bit(idx.Structural, i), bit(idx.Backslash, i), bit(idx.Quoted, i), structural[i], backslash[i], quoted[i])
+				return
+			}
+		}
+	}
+
+	run("")
+	run(`{"a":1,"b":[true,false,null],"c":{"d":"e"}}`)
+	run(`{"key with , and : and {}":"value [with] brackets"}`)
+	run(`["\"", "\\", "\\\"", "\\\\\"", "a\\\\"]`)
+	run(`{"unterminated`)
+	for n := 0; n < 140; n++ {
+		run(`{"` + strings.Repeat("x", n) + strings.Repeat(`\`, n%7) + `":"` + strings.Repeat(`\\`, n%5) + `",[1,2]}`)
+		run(strings.Repeat(`\`, n) + `"a":[` + strings.Repeat(`"\"",`, n%9) + `]`)
+	}
+}
+
+// TestEscapedCharMask verifies the lane-level escape detector on every arrangement of
+// backslashes in a lane, with and without an escape carried in from the previous chunk.
+// The carry out must say whether the byte after the lane is escaped.
+func TestEscapedCharMask(t *testing.T) {
+	for pattern := 0; pattern < 256; pattern++ {
+		for _, carry := range []bool{false, true} {
+			var lane [8]byte
+			var want uint64
+			escaped := carry
+			for i := range lane {
+				lane[i] = 'x'
+				if pattern>>i&1 == 1 {
+					lane[i] = '\\'
+				}
+				switch {
+				case escaped:
+					want |= 0x80 << (8 * i)
+					escaped = false
+				case lane[i] == '\\':
+					escaped = true
+				}
+			}
+			gotCarry := carry
+			if got := EscapedCharMask(LanesToInt(lane), &gotCarry); got != want || gotCarry != escaped {
+				t.Errorf("EscapedCharMask(%q, %v) = 0x%016x, %v; want 0x%016x, %v", lane, carry, got, gotCarry, want, escaped)
+			}
+		}
+	}
+}
+
+// TestQuoteRegionMask verifies the lane-level in-quote tracker on every arrangement of
+// quotes in a lane, starting both outside and inside a string.
+func TestQuoteRegionMask(t *testing.T) {
+	for pattern := 0; pattern < 256; pattern++ {
+		for _, carry := range []bool{false, true} {
+			quotes := SpreadBitsToHighBits(byte(pattern))
+			var want uint64
+			in := carry
+			for i := 0; i < 8; i++ {
+				if pattern>>i&1 == 1 {
+					in = !in
+				}
+				if in {
+					want |= 0x80 << (8 * i)
+				}
+			}
+			gotIn := carry
+			if got := QuoteRegionMask(quotes, &gotIn); got != want || gotIn != in {
+				t.Errorf("QuoteRegionMask(0x%016x, %v) = 0x%016x, %v; want 0x%016x, %v", quotes, carry, got, gotIn, want, in)
+			}
+		}
+	}
+}
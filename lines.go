@@ -0,0 +1,99 @@
+package swar
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// CountLines counts the lines in b, including a final line without a trailing newline
+// Newlines are counted a lane at a time with HighBitWhereEqual
+func CountLines(b []byte) int {
+	n := countMasked(b, newlineMask)
+	if len(b) > 0 && b[len(b)-1] != '\n' {
+		n++
+	}
+	return n
+}
+
+// Lines iterates over the lines of b without their terminating '\n'
+// A trailing newline does not start an extra empty line; yielded slices alias b
+func Lines(b []byte) iter.Seq[[]byte] {
+	return lines(b, false)
+}
+
+// LinesCRLF iterates like Lines but also strips a '\r' preceding each '\n'
+// Handles input that mixes Unix and Windows line endings
+func LinesCRLF(b []byte) iter.Seq[[]byte] {
+	return lines(b, true)
+}
+
+// lines walks the newline mask of each lane, yielding one line per set bit
+func lines(b []byte, trimCR bool) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		emit := func(line []byte, terminated bool) bool {
+			if trimCR && terminated && len(line) > 0 && line[len(line)-1] == '\r' {
+				line = line[:len(line)-1]
+			}
+			return yield(line[:len(line):len(line)])
+		}
+		start := 0
+		for i := 0; i < len(b); i += 8 {
+			for m := newlineMask(loadLane(b, i)); m != 0; m &= m - 1 {
+				end := i + bits.TrailingZeros64(m)>>3
+				if !emit(b[start:end], true) {
+					return
+				}
+				start = end + 1
+			}
+		}
+		if start < len(b) {
+			emit(b[start:], false)
+		}
+	}
+}
+
+// newlineMask sets the high bit of every '\n' byte
+func newlineMask(v uint64) uint64 {
+	return HighBitWhereEqual(v, Dupe('\n'))
+}
+
+// NormalizeNewlines copies src to dst converting every "\r\n" pair to "\n"
+// Returns the bytes written; dst must hold len(src) bytes and may be the same slice as src
+func NormalizeNewlines(dst, src []byte) int {
+	return normalizeNewlines(dst, src, false)
+}
+
+// NormalizeAllNewlines is like NormalizeNewlines but also turns a lone '\r' into '\n'
+// Suits input written on classic Mac OS as well as Windows
+func NormalizeAllNewlines(dst, src []byte) int {
+	return normalizeNewlines(dst, src, true)
+}
+
+// normalizeNewlines finds CRLF pairs by comparing each lane with the lane one byte
+// ahead, then left-packs the surviving bytes of lanes that contained a '\r'
+func normalizeNewlines(dst, src []byte, loneCR bool) int {
+	dst = dst[:len(src)]
+	src = unaliased(dst, src, true)
+	n := 0
+	for i := 0; i < len(src); i += 8 {
+		v := loadLane(src, i)
+		cr := HighBitWhereEqual(v, Dupe('\r'))
+		if cr == 0 && i+8 <= len(src) {
+			storeLane(dst, n, v)
+			n += 8
+			continue
+		}
+		pairs := cr & HighBitWhereEqual(loadLane(src, i+1), Dupe('\n'))
+		if loneCR {
+			v ^= ((cr &^ pairs) >> 7) * ('\r' ^ '\n')
+		}
+		keep := ExtractLowBits((pairs ^ HighBits) >> 7)
+		for _, p := range Lookup.OnesPositions[keep][:Lookup.OnesCount[keep]] {
+			if i+int(p) < len(src) {
+				dst[n] = byte(v >> (8 * p))
+				n++
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,100 @@
+package swar
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestCountLines verifies line counting with and without a trailing newline, including
+// blank lines and newlines on lane boundaries.
+func TestCountLines(t *testing.T) {
+	run := func(b string, want int) {
+		if got := CountLines([]byte(b)); got != want {
+			t.Errorf("CountLines(%q) = %d; want %d", b, got, want)
+		}
+	}
+
+	run("", 0)
+	run("one", 1)
+	run("one\n", 1)
+	run("one\ntwo", 2)
+	run("\n\n\n", 3)
+	run("1234567\n1234567\n", 2)
+	run("12345678\n12345678", 2)
+}
+
+// TestLines verifies that the line iterator agrees with bytes.Lines once terminators are
+// removed, and that the CRLF variant strips carriage returns only before a newline.
+// Breaking out of the loop early must stop iteration cleanly.
+func TestLines(t *testing.T) {
+	run := func(b string, crlf bool, want ...string) {
+		seq := Lines([]byte(b))
+		if crlf {
+			seq = LinesCRLF([]byte(b))
+		}
+		var got []string
+		for line := range seq {
+			got = append(got, string(line))
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("Lines(%q, crlf=%v) = %q; want %q", b, crlf, got, want)
+		}
+	}
+
+	run("", false)
+	run("one", false, "one")
+	run("one\ntwo\n", false, "one", "two")
+	run("\n\nx", false, "", "", "x")
+	run("a\r\nb\rc\r\n", false, "a\r", "b\rc\r")
+	run("a\r\nb\rc\r\n\r", true, "a", "b\rc", "\r")
+
+	text := "a first line\nsecond\n\nfourth line is a little bit longer\r\nlast"
+	var want []string
+	for line := range bytes.Lines([]byte(text)) {
+		want = append(want, strings.TrimSuffix(string(line), "\n"))
+	}
+	run(text, false, want...)
+
+	for line := range Lines([]byte(text)) {
+		if string(line) != "a first line" {
+			t.Errorf("first line = %q; want %q", line, "a first line")
+		}
+		break
+	}
+}
+
+// TestNormalizeNewlines verifies CRLF collapsing against strings.ReplaceAll, including
+// pairs split across lanes, runs of carriage returns and in-place operation. The lone CR
+// variant must additionally turn every remaining '\r' into '\n'.
+func TestNormalizeNewlines(t *testing.T) {
+	run := func(src string) {
+		for _, all := range []bool{false, true} {
+			want := strings.ReplaceAll(src, "\r\n", "\n")
+			f := NormalizeNewlines
+			if all {
+				want = strings.ReplaceAll(want, "\r", "\n")
+				f = NormalizeAllNewlines
+			}
+			dst := make([]byte, len(src))
+			if n := f(dst, []byte(src)); string(dst[:n]) != want {
+				t.Errorf("normalize(%q, all=%v) = %q; want %q", src, all, dst[:n], want)
+			}
+			inPlace := []byte(src)
+			if n := f(inPlace, inPlace); string(inPlace[:n]) != want {
+				t.Errorf("normalize(%q, all=%v) in place = %q; want %q", src, all, inPlace[:n], want)
+			}
+		}
+	}
+
+	run("")
+	run("no newlines here at all")
+	run("a\r\nb\r\nc")
+	run("1234567\r\n1234567\r\n")
+	run("\r\r\n\n\r\r\r\n\r")
+	run("line one\r\nline two\rline three\nline four\r\n\r\n")
+	for i := 0; i < 20; i++ {
+		run(strings.Repeat("x", i) + "\r\n" + strings.Repeat("y", 20-i) + "\r")
+	}
+}
@@ -31,3 +31,53 @@ func HighBitWhereEqual(v, cm uint64) uint64 {
 	hi := ^y & HighBits  // 0x80 where x==0 (v==cm)
 	return hi & HighBits // mask off other bits
 }
+
+// RangeMatcher flags bytes that fall inside any of a set of inclusive ranges
+// Built once by NewRangeMatcher and reused across many lanes
+type RangeMatcher struct {
+	lo   []uint64 // lower bound of each range, duplicated across lanes
+	span []uint64 // hi-lo of each range, duplicated across lanes
+	all  bool     // a single range covers every byte value
+}
+
+// NewRangeMatcher creates a matcher for the given inclusive [lo, hi] ranges
+// Overlapping and adjacent ranges are merged so each byte is tested once per range
+func NewRangeMatcher(ranges ...[2]byte) RangeMatcher {
+	var m RangeMatcher
+	var members [256]bool
+	for _, r := range ranges {
+		for c := int(r[0]); c <= int(r[1]); c++ {
+			members[c] = true
+		}
+	}
+	for c := 0; c < 256; {
+		if !members[c] {
+			c++
+			continue
+		}
+		lo := c
+		for c < 256 && members[c] {
+			c++
+		}
+		if lo == 0 && c == 256 {
+			m.all = true
+			break
+		}
+		m.lo = append(m.lo, Dupe(byte(lo)))
+		m.span = append(m.span, Dupe(byte(c-1-lo)))
+	}
+	return m
+}
+
+// Mask sets the high bit (0x80) in each byte of v inside any of the ranges
+// Each range costs one wrapping subtract and one comparison per lane
+func (m RangeMatcher) Mask(v uint64) uint64 {
+	if m.all {
+		return HighBits
+	}
+	outside := HighBits
+	for i, lo := range m.lo {
+		outside &= HighBitWhereGreater(SubtractBytesWithWrapping(v, lo), m.span[i])
+	}
+	return outside ^ HighBits
+}
@@ -131,3 +131,47 @@ func TestPopcountPerByte(t *testing.T) {
 
 	run(0x0F_F0_55_AA_00_FF_33_CC, 0x04_04_04_04_00_08_04_04)
 }
+
+// TestRangeMatcher verifies that the fused multi-range matcher flags exactly the bytes
+// inside any of its ranges. Identifier scanning relies on several ranges being merged
+// correctly, including overlapping, adjacent, single-byte and full-width ranges.
+func TestRangeMatcher(t *testing.T) {
+	run := func(m RangeMatcher, v, want uint64) {
+		if got := m.Mask(v); got != want {
+			t.Errorf("Mask(0x%016x) = 0x%016x; want 0x%016x", v, got, want)
+		}
+	}
+
+	ident := NewRangeMatcher([2]byte{'a', 'z'}, [2]byte{'A', 'Z'}, [2]byte{'0', '9'}, [2]byte{'_', '_'})
+	run(ident, LanesToInt([8]byte{'a', 'Z', '0', '_', ' ', '-', '9', 0xFF}), 0x00_80_00_00_80_80_80_80)
+	run(ident, Dupe('{'), 0)
+	run(NewRangeMatcher(), Dupe('a'), 0)
+	run(NewRangeMatcher([2]byte{0, 0x7F}, [2]byte{0x80, 0xFF}), 0x00_FF_12, HighBits)
+	run(NewRangeMatcher([2]byte{'z', 'a'}), Dupe('m'), 0)
+
+	ranges := [][2]byte{{0x00, 0x03}, {0x02, 0x10}, {0x11, 0x11}, {0x40, 0x40}, {0xF0, 0xFF}}
+	m := NewRangeMatcher(ranges...)
+	for c := 0; c < 256; c++ {
+		want := uint64(0)
+		for _, r := range ranges {
+			if byte(c) >= r[0] && byte(c) <= r[1] {
+				want = HighBits
+			}
+		}
+		run(m, Dupe(byte(c)), want)
+	}
+}
+
+// TestSelectByHighBit verifies that selection is driven only by the high bit of each mask
+// byte, so comparison results can be used without shifting or cleaning them first.
+func TestSelectByHighBit(t *testing.T) {
+	run := func(a, b, mask, want uint64) {
+		if got := SelectByHighBit(a, b, mask); got != want {
+			t.Errorf("SelectByHighBit(0x%016x, 0x%016x, 0x%016x) = 0x%016x; want 0x%016x", a, b, mask, got, want)
+		}
+	}
+
+	run(0x11_11_11_11, 0x22_22_22_22, 0x80_00_80_00, 0x11_22_11_22)
+	run(0x11_11_11_11, 0x22_22_22_22, 0xFF_7F_C0_01, 0x11_22_11_22)
+	run(Dupe(0xAA), Dupe(0x55), HighBits, Dupe(0xAA))
+}
@@ -0,0 +1,88 @@
+package swar
+
+// LookupBytes replaces each byte c of v with table[c]
+// All eight loads are issued together and merged in one expression, so they overlap in flight
+func LookupBytes(v uint64, table *[256]byte) uint64 {
+	return uint64(table[byte(v)]) |
+		uint64(table[byte(v>>8)])<<8 |
+		uint64(table[byte(v>>16)])<<16 |
+		uint64(table[byte(v>>24)])<<24 |
+		uint64(table[byte(v>>32)])<<32 |
+		uint64(table[byte(v>>40)])<<40 |
+		uint64(table[byte(v>>48)])<<48 |
+		uint64(table[byte(v>>56)])<<56
+}
+
+// TableCompiler prepares a 256-entry byte table for repeated lookups
+// Built once by NewTableCompiler; Lookup picks the register-only path when the table allows it
+type TableCompiler struct {
+	table *[256]byte
+	lo    [16]uint64 // table[n] for each low nibble n, duplicated across lanes
+	hi    [16]uint64 // table[n<<4] ^ table[0] for each high nibble n, duplicated across lanes
+	split bool       // table[c] == lo[c&15] ^ hi[c>>4] for every c
+}
+
+// NewTableCompiler compiles table, which must not be modified while the compiler is in use
+// Checks whether every entry is the XOR of a low nibble and a high nibble contribution
+func NewTableCompiler(table *[256]byte) *TableCompiler {
+	t := &TableCompiler{table: table, split: true}
+	for n := range 16 {
+		t.lo[n] = Dupe(table[n])
+		t.hi[n] = Dupe(table[n<<4] ^ table[0])
+	}
+	for c := range 256 {
+		if table[c] != byte(t.lo[c&15]^t.hi[c>>4]) {
+			t.split = false
+			break
+		}
+	}
+	return t
+}
+
+// Decomposable reports whether the table splits into two 16-entry nibble tables
+// True for XOR keys, bit permutations, complements and any independent substitution of each nibble
+func (t *TableCompiler) Decomposable() bool {
+	return t.split
+}
+
+// Lookup replaces each byte c of v with table[c]
+// Decomposable tables avoid memory loads and data-dependent timing; the gather is faster when cached
+func (t *TableCompiler) Lookup(v uint64) uint64 {
+	if !t.split {
+		return LookupBytes(v, t.table)
+	}
+	return nibbleLookup(&t.lo, v) ^ nibbleLookup(&t.hi, v>>4)
+}
+
+// nibbleLookup picks e[n] for the low nibble n of each byte of v
+// A four-level select tree halves the candidates once per nibble bit
+func nibbleLookup(e *[16]uint64, v uint64) uint64 {
+	m := (v & LowBits) * 0xFF
+	a0, a1 := e[0]^(e[0]^e[1])&m, e[2]^(e[2]^e[3])&m
+	a2, a3 := e[4]^(e[4]^e[5])&m, e[6]^(e[6]^e[7])&m
+	a4, a5 := e[8]^(e[8]^e[9])&m, e[10]^(e[10]^e[11])&m
+	a6, a7 := e[12]^(e[12]^e[13])&m, e[14]^(e[14]^e[15])&m
+	m = (v >> 1 & LowBits) * 0xFF
+	b0, b1 := a0^(a0^a1)&m, a2^(a2^a3)&m
+	b2, b3 := a4^(a4^a5)&m, a6^(a6^a7)&m
+	m = (v >> 2 & LowBits) * 0xFF
+	c0, c1 := b0^(b0^b1)&m, b2^(b2^b3)&m
+	m = (v >> 3 & LowBits) * 0xFF
+	return c0 ^ (c0^c1)&m
+}
+
+// TranslateBytes writes table[c] to dst for each byte c of src, like strings.Map for bytes
+// Unrolled eight bytes at a time with every lookup issued before any store, so the loads overlap
+func TranslateBytes(dst, src []byte, table *[256]byte) {
+	dst = dst[:len(src)]
+	src = unaliased(dst, src, true)
+	i := 0
+	for ; i+8 <= len(src); i += 8 {
+		s, d := src[i:i+8:i+8], dst[i:i+8:i+8]
+		d[0], d[1], d[2], d[3], d[4], d[5], d[6], d[7] =
+			table[s[0]], table[s[1]], table[s[2]], table[s[3]], table[s[4]], table[s[5]], table[s[6]], table[s[7]]
+	}
+	for ; i < len(src); i++ {
+		dst[i] = table[src[i]]
+	}
+}
@@ -0,0 +1,86 @@
+package swar
+
+import (
+	"bytes"
+	"math"
+	"math/bits"
+	"testing"
+
+	"github.com/dans-stuff/swar/check"
+	"github.com/dans-stuff/swar/ref"
+)
+
+// lookupTables are tables with a known answer to Decomposable: nibble-wise ones such as XOR
+// keys and bit reversal, and byte-wise ones such as gamma curves and ASCII case mapping.
+var lookupTables = []struct {
+	name  string
+	split bool
+	f     func(c byte) byte
+}{
+	{"identity", true, func(c byte) byte { return c }},
+	{"constant", true, func(byte) byte { return 0xA5 }},
+	{"xor key", true, func(c byte) byte { return c ^ 0x5C }},
+	{"complement", true, func(c byte) byte { return ^c }},
+	{"reverse bits", true, bits.Reverse8},
+	{"swap nibbles", true, func(c byte) byte { return c<<4 | c>>4 }},
+	{"nibble sbox", true, func(c byte) byte {
+		sbox := [16]byte{0xC, 5, 6, 0xB, 9, 0, 0xA, 0xD, 3, 0xE, 0xF, 8, 4, 7, 1, 2}
+		return sbox[c>>4]<<4 | sbox[c&15]
+	}},
+	{"gamma 2.2", false, func(c byte) byte { return byte(math.Round(255 * math.Pow(float64(c)/255, 2.2))) }},
+	{"upper case", false, func(c byte) byte {
+		if c >= 'a' && c <= 'z' {
+			return c - 32
+		}
+		return c
+	}},
+	{"add 1", false, func(c byte) byte { return c + 1 }},
+}
+
+// TestLookupBytes compares LookupBytes and both TableCompiler paths with the scalar
+// reference for every table, and checks that Decomposable classifies each table correctly.
+func TestLookupBytes(t *testing.T) {
+	for _, tc := range lookupTables {
+		var table [256]byte
+		for c := range table {
+			table[c] = tc.f(byte(c))
+		}
+		want := func(v uint64) uint64 { return ref.LookupBytes(v, &table) }
+		if err := check.CheckLaneFunc(func(v uint64) uint64 { return LookupBytes(v, &table) }, want); err != nil {
+			t.Errorf("LookupBytes(%s): %v", tc.name, err)
+		}
+		compiled := NewTableCompiler(&table)
+		if got := compiled.Decomposable(); got != tc.split {
+			t.Errorf("NewTableCompiler(%s).Decomposable() = %v; want %v", tc.name, got, tc.split)
+		}
+		if err := check.CheckLaneFunc(compiled.Lookup, want); err != nil {
+			t.Errorf("NewTableCompiler(%s).Lookup: %v", tc.name, err)
+		}
+	}
+}
+
+// TestTranslateBytes runs TranslateBytes over every length around the unrolled block size,
+// into a separate buffer and in place, and compares each byte with a direct table lookup.
+func TestTranslateBytes(t *testing.T) {
+	var table [256]byte
+	for c := range table {
+		table[c] = byte(c*7 + 3)
+	}
+	for n := range 40 {
+		src := make([]byte, n)
+		want := make([]byte, n)
+		for i := range src {
+			src[i] = byte(i*37 + n)
+			want[i] = table[src[i]]
+		}
+		dst := make([]byte, n)
+		TranslateBytes(dst, src, &table)
+		if !bytes.Equal(dst, want) {
+			t.Errorf("TranslateBytes(%v) = %v; want %v", src, dst, want)
+		}
+		TranslateBytes(src, src, &table)
+		if !bytes.Equal(src, want) {
+			t.Errorf("TranslateBytes in place (n=%d) = %v; want %v", n, src, want)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package swar
 
+import "math/bits"
+
 const (
 	// mEven selects even bytes in a uint64
 	mEven uint64 = 0x00FF_00FF_00FF_00FF
@@ -18,11 +20,18 @@ func SubtractBytesWithWrapping(a, b uint64) uint64 {
 // SubtractBytesWithMinimum performs byte-wise subtraction clamped at zero
 // Provides saturating subtraction to prevent underflow in all 8 bytes
 func SubtractBytesWithMinimum(a, b uint64) uint64 {
-	diff := ((a | HighBits) - (b &^ HighBits)) ^ ((a ^ ^b) & HighBits)
-	bo := ((^a & b) | ((^a | b) & diff)) & HighBits
+	diff, bo := SubtractBytesWithBorrowOut(a, b)
 	return diff &^ ((bo >> 7) * 0xFF)
 }
 
+// SubtractBytesWithBorrowOut performs byte-wise wrapping subtraction and reports borrows
+// borrows has the high bit set in each byte where b was larger than a
+func SubtractBytesWithBorrowOut(a, b uint64) (diff, borrows uint64) {
+	diff = ((a | HighBits) - (b &^ HighBits)) ^ ((a ^ ^b) & HighBits)
+	borrows = ((^a & b) | ((^a | b) & diff)) & HighBits
+	return diff, borrows
+}
+
 // AddBytesWithWrapping performs byte-wise addition with wrap-around
 // Parallel addition across all 8 bytes with overflow wrapping to zero
 func AddBytesWithWrapping(a, b uint64) uint64 {
@@ -33,12 +42,18 @@ func AddBytesWithWrapping(a, b uint64) uint64 {
 // AddBytesWithMaximum performs byte-wise addition clamped at 255
 // Saturating addition to prevent overflow in all 8 bytes
 func AddBytesWithMaximum(a, b uint64) uint64 {
-	preSum := (a & laneNotHigh) + (b & laneNotHigh)
-	sum := preSum ^ ((a ^ b) & HighBits)
-	carry := ((a & b) | ((a | b) & ^sum)) & HighBits
+	sum, carry := AddBytesWithCarryOut(a, b)
 	return sum | (carry>>7)*0xFF
 }
 
+// AddBytesWithCarryOut performs byte-wise wrapping addition and reports carries
+// carries has the high bit set in each byte that overflowed, ready to add into the next byte up
+func AddBytesWithCarryOut(a, b uint64) (sum, carries uint64) {
+	sum = ((a & laneNotHigh) + (b & laneNotHigh)) ^ ((a ^ b) & HighBits)
+	carries = ((a & b) | ((a | b) & ^sum)) & HighBits
+	return sum, carries
+}
+
 // AbsoluteDifferenceBetweenBytes calculates |a-b| for each byte
 // Computes unsigned distances for metrics and signal processing
 func AbsoluteDifferenceBetweenBytes(a, b uint64) uint64 {
@@ -100,6 +115,13 @@ func SelectByLowBit(a, b, mask uint64) uint64 {
 	return (a & byteMask) | (b &^ byteMask)
 }
 
+// SelectByHighBit selects bytes from a where mask has the high bit set, otherwise from b
+// Consumes HighBitWhere* results directly without shifting them down first
+func SelectByHighBit(a, b, mask uint64) uint64 {
+	byteMask := (mask >> 7 & LowBits) * 0xFF
+	return (a & byteMask) | (b &^ byteMask)
+}
+
 // CountOnesPerByte counts set bits in each byte
 // Parallel population count for hamming distance and feature extraction
 func CountOnesPerByte(v uint64) uint64 {
@@ -107,3 +129,182 @@ func CountOnesPerByte(v uint64) uint64 {
 	m2 := (m1 & 0x3333_3333_3333_3333) + ((m1 >> 2) & 0x3333_3333_3333_3333)
 	return (m2 + (m2 >> 4)) & 0x0F0F_0F0F_0F0F_0F0F
 }
+
+// BinaryToBCDBytes converts each byte from binary 0-99 to two packed BCD digits
+// Tens are found with a reciprocal multiply in 16-bit lanes; bytes above 99 are undefined
+func BinaryToBCDBytes(v uint64) uint64 {
+	toBCD := func(w uint64) uint64 {
+		tens := (w * 205 >> 11) & 0x000F_000F_000F_000F
+		return tens<<4 | (w - tens*10)
+	}
+	return toBCD(v&mEven) | toBCD(v>>8&mEven)<<8
+}
+
+// BCDToBinaryBytes converts each byte from two packed BCD digits to binary 0-99
+// Computes tens*10 + ones per byte; nibbles above 9 give undefined results
+func BCDToBinaryBytes(v uint64) uint64 {
+	tens := v >> 4 & 0x0F0F_0F0F_0F0F_0F0F
+	return tens*10 + v&0x0F0F_0F0F_0F0F_0F0F
+}
+
+// AddBCDBytes adds packed BCD bytes with decimal carries between digits
+// Each byte wraps at 100; carries has 0x80 set in every byte that overflowed
+func AddBCDBytes(a, b uint64) (sum, carries uint64) {
+	s := BCDToBinaryBytes(a) + BCDToBinaryBytes(b) // at most 198 per byte
+	carries = HighBitWhereGreater(s, Dupe(99))
+	return BinaryToBCDBytes(s - (carries>>7)*100), carries
+}
+
+// PrefixSumBytesWrapping replaces each byte with the wrapping sum of itself and all lower bytes
+// Three shift-and-add steps; turns a lane of deltas back into absolute values
+func PrefixSumBytesWrapping(v uint64) uint64 {
+	v = AddBytesWithWrapping(v, v<<8)
+	v = AddBytesWithWrapping(v, v<<16)
+	return AddBytesWithWrapping(v, v<<32)
+}
+
+// PrefixSumBytesSaturating replaces each byte with the sum of itself and all lower bytes, capped at 255
+// Running counts for small histogram offsets where overflow must stick at the maximum
+func PrefixSumBytesSaturating(v uint64) uint64 {
+	v = AddBytesWithMaximum(v, v<<8)
+	v = AddBytesWithMaximum(v, v<<16)
+	return AddBytesWithMaximum(v, v<<32)
+}
+
+// ZigZagEncodeBytes maps each signed byte to unsigned as 0,-1,1,-2,... -> 0,1,2,3,...
+// Small deltas of either sign become small values that varint and RLE stages favour
+func ZigZagEncodeBytes(v uint64) uint64 {
+	sign := (v >> 7 & LowBits) * 0xFF
+	return (v << 1 &^ LowBits) ^ sign
+}
+
+// ZigZagDecodeBytes inverts ZigZagEncodeBytes in each byte
+// Restores signed deltas before PrefixSumBytesWrapping rebuilds the original values
+func ZigZagDecodeBytes(v uint64) uint64 {
+	sign := (v & LowBits) * 0xFF
+	return (v >> 1 &^ HighBits) ^ sign
+}
+
+// SumBytes returns the sum of all 8 bytes of v
+// Pairs are widened to 16 bits first so the total (at most 2040) never overflows a lane
+func SumBytes(v uint64) uint64 {
+	v = v&mEven + v>>8&mEven
+	return v * 0x0001_0001_0001_0001 >> 48
+}
+
+// MultiplyBytesWidening multiplies corresponding bytes into full 16-bit products
+// even holds the products of bytes 0,2,4,6 and odd of bytes 1,3,5,7, each in a 16-bit slot
+func MultiplyBytesWidening(a, b uint64) (even, odd uint64) {
+	for k := 0; k < 64; k += 16 {
+		// One hardware multiply per slot; a byte product always fits its 16 bits
+		even |= (a >> k & 0xFF) * (b >> k & 0xFF) << k
+		odd |= (a >> (k + 8) & 0xFF) * (b >> (k + 8) & 0xFF) << k
+	}
+	return even, odd
+}
+
+// ScaleBytes multiplies each byte by scale/255 with rounding to nearest
+// scale 255 leaves v unchanged and 0 clears it; used for volume, opacity and contrast
+func ScaleBytes(v uint64, scale uint8) uint64 {
+	// Byte products fit their 16-bit slot, so a plain multiply by the scalar cannot carry
+	even := divide255Slots((v & mEven) * uint64(scale))
+	odd := divide255Slots((v >> 8 & mEven) * uint64(scale))
+	return even | odd<<8
+}
+
+// QuantizeLane maps each byte c of v to a level index below levels, for levels from 1 to 256
+// Buckets of equal width give c*levels/256 by multiply-high; round picks round(c*(levels-1)/255)
+func QuantizeLane(v uint64, levels int, round bool) uint64 {
+	if round {
+		return ScaleBytes(v, uint8(levels-1))
+	}
+	n := uint64(levels)
+	even := (v & mEven) * n >> 8 & mEven
+	odd := (v >> 8 & mEven) * n & (mEven << 8)
+	return even | odd
+}
+
+// MultiplyBytesNormalized multiplies corresponding bytes as fractions of 255 with rounding
+// The core of alpha compositing: round(a*b/255), so 255 is one and 0 is zero
+func MultiplyBytesNormalized(a, b uint64) uint64 {
+	even, odd := MultiplyBytesWidening(a, b)
+	return divide255Slots(even) | divide255Slots(odd)<<8
+}
+
+// divide255Slots divides each 16-bit slot of x, at most 255*255, by 255 rounding to nearest
+// Exact via round(x/255) = (x + 128 + (x+128)>>8) >> 8, with no division instruction
+func divide255Slots(x uint64) uint64 {
+	x += 0x0080_0080_0080_0080
+	return (x + x>>8&mEven) >> 8 & mEven
+}
+
+// divider15 divides by a fixed integer using a precomputed reciprocal
+// Exact for dividends below 2^15, so products stay inside 32-bit slots
+type divider15 struct {
+	recip uint64
+	shift int
+}
+
+// newDivider15 prepares division by d, which must be between 1 and 2^16
+// The reciprocal is ceil(2^(15+l)/d) with l = ceil(log2 d), per Granlund and Montgomery
+func newDivider15(d uint64) divider15 {
+	shift := 15 + bits.Len64(d-1)
+	return divider15{(uint64(1)<<shift + d - 1) / d, shift}
+}
+
+// divide returns the quotient of each 32-bit slot of p, each of which must be below 2^15
+// The slots are multiplied separately since their products could run into each other
+func (d divider15) divide(p uint64) uint64 {
+	return (p&0xFFFF_FFFF*d.recip)>>d.shift | (p>>32*d.recip)>>d.shift<<32
+}
+
+// MaskedAdd returns a+b with wrapping in bytes where mask has the high bit set, and a elsewhere
+// Predicated form of AddBytesWithWrapping that takes HighBitWhere* results directly
+func MaskedAdd(a, b, mask uint64) uint64 {
+	return AddBytesWithWrapping(a, b&((mask&HighBits)>>7*0xFF))
+}
+
+// MaskedSubtract returns a-b with wrapping in bytes where mask has the high bit set, and a elsewhere
+// Predicated form of SubtractBytesWithWrapping; subtracting zero leaves the other bytes alone
+func MaskedSubtract(a, b, mask uint64) uint64 {
+	return SubtractBytesWithWrapping(a, b&((mask&HighBits)>>7*0xFF))
+}
+
+// MaskedAverage returns the average of a and b in bytes where mask has the high bit set, and a elsewhere
+// Predicated form of AverageBytes for smoothing only the selected channels
+func MaskedAverage(a, b, mask uint64) uint64 {
+	return SelectByHighBit(AverageBytes(a, b), a, mask)
+}
+
+// NegateBytes returns the two's-complement negation of each byte
+// 0 and 0x80 map to themselves, as with int8 negation
+func NegateBytes(v uint64) uint64 {
+	return SubtractBytesWithWrapping(0, v)
+}
+
+// NegateBytesWhere negates the bytes of v where mask has the high bit set
+// Conditional sign flips for butterfly steps and sign-magnitude conversion
+func NegateBytesWhere(v, mask uint64) uint64 {
+	return SelectByHighBit(NegateBytes(v), v, mask)
+}
+
+// MaxAccumulations16 is how many lanes AccumulateBytesInto16 can add before a flush
+// 257 bytes of 255 sum to exactly 65535, the most a 16-bit slot holds
+const MaxAccumulations16 = 257
+
+// AccumulateBytesInto16 widens the bytes of v and adds them to two 16-bit-slot accumulators
+// acc0 collects bytes 0,2,4,6 and acc1 bytes 1,3,5,7; flush at least every MaxAccumulations16 calls
+func AccumulateBytesInto16(acc0, acc1 *uint64, v uint64) {
+	*acc0 += v & mEven
+	*acc1 += v >> 8 & mEven
+}
+
+// FlushAccumulators16 adds the 16-bit slot totals to totals in byte order and clears the accumulators
+// totals[i] receives the running sum for byte position i of the accumulated lanes
+func FlushAccumulators16(acc0, acc1 *uint64, totals *[8]uint32) {
+	for i := range 4 {
+		totals[2*i] += uint32(*acc0 >> (16 * i) & 0xFFFF)
+		totals[2*i+1] += uint32(*acc1 >> (16 * i) & 0xFFFF)
+	}
+	*acc0, *acc1 = 0, 0
+}
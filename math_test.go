@@ -50,3 +50,298 @@ func TestAddBytesWithWrapping(t *testing.T) {
 	run(0xF4_F9, 0x0F_01, 0x03_FA)
 	run(0xFF_0F_FF, 0x01_F0_00, 0x00_FF_FF)
 }
+
+// TestBCDBytes verifies conversion between binary and packed BCD for every value 0-99 in
+// every lane, and decimal addition with carry-out against plain integer arithmetic.
+// Telecom and metering protocols depend on these digits being exact.
+func TestBCDBytes(t *testing.T) {
+	bcd := func(n int) uint64 { return uint64(n/10<<4 | n%10) }
+	for n := 0; n < 100; n++ {
+		for lane := 0; lane < 8; lane++ {
+			shift := uint(8 * lane)
+			bin := Dupe(99)&^(0xFF<<shift) | uint64(n)<<shift
+			want := Dupe(0x99)&^(0xFF<<shift) | bcd(n)<<shift
+			if got := BinaryToBCDBytes(bin); got != want {
+				t.Errorf("BinaryToBCDBytes(0x%016x) = 0x%016x; want 0x%016x", bin, got, want)
+			}
+			if got := BCDToBinaryBytes(want); got != bin {
+				t.Errorf("BCDToBinaryBytes(0x%016x) = 0x%016x; want 0x%016x", want, got, bin)
+			}
+		}
+		for m := 0; m < 100; m++ {
+			a, b := Dupe(byte(bcd(n))), Dupe(byte(bcd(m)))
+			wantSum, wantCarries := Dupe(byte(bcd((n+m)%100))), uint64(0)
+			if n+m > 99 {
+				wantCarries = HighBits
+			}
+			if sum, carries := AddBCDBytes(a, b); sum != wantSum || carries != wantCarries {
+				t.Errorf("AddBCDBytes(0x%016x, 0x%016x) = 0x%016x, 0x%016x; want 0x%016x, 0x%016x", a, b, sum, carries, wantSum, wantCarries)
+			}
+		}
+	}
+}
+
+// TestPrefixSumBytes compares both running-sum variants with a byte loop in memory order.
+// Delta decoding relies on the wrapping form being exact; offsets rely on saturation sticking.
+func TestPrefixSumBytes(t *testing.T) {
+	run := func(v uint64) {
+		lanes := IntToLanes(v)
+		var wrap, sat [8]byte
+		accWrap, accSat := byte(0), 0
+		for i, c := range lanes {
+			accWrap += c
+			accSat = min(accSat+int(c), 255)
+			wrap[i], sat[i] = accWrap, byte(accSat)
+		}
+		if got, want := PrefixSumBytesWrapping(v), LanesToInt(wrap); got != want {
+			t.Errorf("PrefixSumBytesWrapping(0x%016x) = 0x%016x; want 0x%016x", v, got, want)
+		}
+		if got, want := PrefixSumBytesSaturating(v), LanesToInt(sat); got != want {
+			t.Errorf("PrefixSumBytesSaturating(0x%016x) = 0x%016x; want 0x%016x", v, got, want)
+		}
+	}
+
+	run(0)
+	run(LowBits)
+	run(0xFFFF_FFFF_FFFF_FFFF)
+	run(0x0000_0000_0000_00FF)
+	for n := uint64(0); n < 0xFF_FF_FF_FF_FF; n = (n*12 + 13) / 11 {
+		run(n)
+		run(n * 0x9E37_79B9_7F4A_7C15)
+	}
+}
+
+// TestZigZagBytes checks the encoding of every byte value in every lane against the
+// scalar formula and that decoding restores the original, so neighbouring bytes never mix.
+func TestZigZagBytes(t *testing.T) {
+	for n := 0; n < 256; n++ {
+		s := int8(n)
+		enc := byte(s<<1) ^ byte(s>>7)
+		for lane := 0; lane < 8; lane++ {
+			shift := uint(8 * lane)
+			v := Dupe(0x81)&^(0xFF<<shift) | uint64(n)<<shift
+			want := Dupe(0xFD)&^(0xFF<<shift) | uint64(enc)<<shift
+			if got := ZigZagEncodeBytes(v); got != want {
+				t.Errorf("ZigZagEncodeBytes(0x%016x) = 0x%016x; want 0x%016x", v, got, want)
+			}
+			if got := ZigZagDecodeBytes(want); got != v {
+				t.Errorf("ZigZagDecodeBytes(0x%016x) = 0x%016x; want 0x%016x", want, got, v)
+			}
+		}
+	}
+}
+
+// TestSumBytes compares the horizontal sum with a byte loop, including all-0xFF lanes
+// where a naive single multiply would overflow the top byte.
+func TestSumBytes(t *testing.T) {
+	run := func(v uint64) {
+		want := uint64(0)
+		for _, c := range IntToLanes(v) {
+			want += uint64(c)
+		}
+		if got := SumBytes(v); got != want {
+			t.Errorf("SumBytes(0x%016x) = %d; want %d", v, got, want)
+		}
+	}
+
+	run(0)
+	run(0xFFFF_FFFF_FFFF_FFFF)
+	run(LowBits)
+	for n := uint64(0); n < 0xFF_FF_FF_FF_FF; n = (n*12 + 13) / 11 {
+		run(n)
+		run(n * 0x9E37_79B9_7F4A_7C15)
+	}
+}
+
+// TestMultiplyBytesWidening checks every product of two byte values in every lane against
+// integer multiplication, so no partial product leaks into a neighbouring 16-bit slot.
+func TestMultiplyBytesWidening(t *testing.T) {
+	for x := 0; x < 256; x++ {
+		for y := 0; y < 256; y += 3 {
+			a := Dupe(byte(x))
+			b := LanesToInt([8]byte{byte(y), 0xFF, byte(y / 2), 1, 0, byte(y), 0x80, byte(255 - y)})
+			lb := IntToLanes(b)
+			var wantEven, wantOdd uint64
+			for i := 0; i < 4; i++ {
+				wantEven |= uint64(x*int(lb[2*i])) << (16 * i)
+				wantOdd |= uint64(x*int(lb[2*i+1])) << (16 * i)
+			}
+			if even, odd := MultiplyBytesWidening(a, b); even != wantEven || odd != wantOdd {
+				t.Errorf("MultiplyBytesWidening(0x%016x, 0x%016x) = 0x%016x, 0x%016x; want 0x%016x, 0x%016x", a, b, even, odd, wantEven, wantOdd)
+			}
+		}
+	}
+}
+
+// TestScaleBytes checks every byte and scale pair against rounded integer division in
+// every lane position. Fades and alpha depend on 255 being identity and 0 being silence.
+func TestScaleBytes(t *testing.T) {
+	for s := 0; s < 256; s++ {
+		for x := 0; x < 256; x++ {
+			v := LanesToInt([8]byte{byte(x), 0xFF, byte(x), 0, byte(x), 0x80, byte(255 - x), byte(x)})
+			lanes := IntToLanes(v)
+			for i, c := range lanes {
+				lanes[i] = byte((int(c)*s + 127) / 255)
+			}
+			if got, want := ScaleBytes(v, uint8(s)), LanesToInt(lanes); got != want {
+				t.Errorf("ScaleBytes(0x%016x, %d) = 0x%016x; want 0x%016x", v, s, got, want)
+			}
+		}
+	}
+}
+
+// TestQuantizeLane checks every byte and level count in both modes against integer
+// formulas, in every lane position, so bucket edges and the top level are exact.
+func TestQuantizeLane(t *testing.T) {
+	for levels := 1; levels <= 256; levels++ {
+		for x := 0; x < 256; x++ {
+			v := LanesToInt([8]byte{byte(x), 0xFF, byte(x), 0, byte(x), 0x80, byte(255 - x), byte(x)})
+			floor, nearest := IntToLanes(v), IntToLanes(v)
+			for i, c := range IntToLanes(v) {
+				floor[i] = byte(int(c) * levels / 256)
+				nearest[i] = byte((int(c)*(levels-1) + 127) / 255)
+			}
+			if got, want := QuantizeLane(v, levels, false), LanesToInt(floor); got != want {
+				t.Errorf("QuantizeLane(0x%016x, %d, false) = 0x%016x; want 0x%016x", v, levels, got, want)
+			}
+			if got, want := QuantizeLane(v, levels, true), LanesToInt(nearest); got != want {
+				t.Errorf("QuantizeLane(0x%016x, %d, true) = 0x%016x; want 0x%016x", v, levels, got, want)
+			}
+		}
+	}
+}
+
+// TestMultiplyBytesNormalized checks every pair of byte values against rounded integer
+// division, so compositing with 255 is exact and with 0 gives zero.
+func TestMultiplyBytesNormalized(t *testing.T) {
+	for x := 0; x < 256; x++ {
+		for y := 0; y < 256; y++ {
+			a := LanesToInt([8]byte{byte(x), byte(y), 0xFF, byte(x), 0, byte(y), byte(x), 0x80})
+			b := LanesToInt([8]byte{byte(y), byte(x), byte(y), 0xFF, byte(x), 0, byte(y), byte(x)})
+			la, lb := IntToLanes(a), IntToLanes(b)
+			var want [8]byte
+			for i := range want {
+				want[i] = byte((int(la[i])*int(lb[i]) + 127) / 255)
+			}
+			if got := MultiplyBytesNormalized(a, b); got != LanesToInt(want) {
+				t.Errorf("MultiplyBytesNormalized(0x%016x, 0x%016x) = 0x%016x; want 0x%016x", a, b, got, LanesToInt(want))
+			}
+		}
+	}
+}
+
+// TestMaskedArithmetic checks the predicated operations byte by byte: selected bytes must
+// match the unmasked operation and the rest must be a, even when mask has low bits set.
+func TestMaskedArithmetic(t *testing.T) {
+	run := func(a, b, mask uint64) {
+		add, sub, avg := AddBytesWithWrapping(a, b), SubtractBytesWithWrapping(a, b), AverageBytes(a, b)
+		for i := range 8 {
+			byteMask := uint64(0xFF) << (8 * i)
+			if mask>>(8*i+7)&1 == 0 {
+				add, sub, avg = add&^byteMask|a&byteMask, sub&^byteMask|a&byteMask, avg&^byteMask|a&byteMask
+			}
+		}
+		if got := MaskedAdd(a, b, mask); got != add {
+			t.Errorf("MaskedAdd(0x%016x, 0x%016x, 0x%016x) = 0x%016x; want 0x%016x", a, b, mask, got, add)
+		}
+		if got := MaskedSubtract(a, b, mask); got != sub {
+			t.Errorf("MaskedSubtract(0x%016x, 0x%016x, 0x%016x) = 0x%016x; want 0x%016x", a, b, mask, got, sub)
+		}
+		if got := MaskedAverage(a, b, mask); got != avg {
+			t.Errorf("MaskedAverage(0x%016x, 0x%016x, 0x%016x) = 0x%016x; want 0x%016x", a, b, mask, got, avg)
+		}
+	}
+
+	run(0xFF00_FF00_FF00_FF00, LowBits, HighBits)
+	run(0xFF00_FF00_FF00_FF00, LowBits, 0x7F7F_7F7F_7F7F_7F7F)
+	for n := uint64(0); n < 0xFF_FF_FF_FF_FF; n = (n*12 + 13) / 11 {
+		run(n, n*0x9E37_79B9_7F4A_7C15, ^n*0xBF58_476D_1CE4_E5B9)
+	}
+}
+
+// TestNegateBytes checks every byte value in every lane against int8 negation, and that
+// the conditional form only touches bytes selected by the mask.
+func TestNegateBytes(t *testing.T) {
+	for x := range 256 {
+		for lane := range 8 {
+			shift := uint(8 * lane)
+			v := Dupe(0x7F)&^(0xFF<<shift) | uint64(x)<<shift
+			want := Dupe(0x81)&^(0xFF<<shift) | uint64(byte(-int8(x)))<<shift
+			if got := NegateBytes(v); got != want {
+				t.Errorf("NegateBytes(0x%016x) = 0x%016x; want 0x%016x", v, got, want)
+			}
+			mask := uint64(0x80) << shift
+			wantWhere := v&^(0xFF<<shift) | want&(0xFF<<shift)
+			if got := NegateBytesWhere(v, mask); got != wantWhere {
+				t.Errorf("NegateBytesWhere(0x%016x, 0x%016x) = 0x%016x; want 0x%016x", v, mask, got, wantWhere)
+			}
+		}
+	}
+}
+
+// TestCarryBorrowOut checks the reported carries and borrows against integer arithmetic
+// for every pair of byte values, then chains them into 16-bit adds over byte pairs.
+func TestCarryBorrowOut(t *testing.T) {
+	for x := range 256 {
+		for y := range 256 {
+			a, b := Dupe(byte(x)), LanesToInt([8]byte{byte(y), 0, 0xFF, byte(y), byte(x), byte(y), 1, byte(255 - y)})
+			la, lb := IntToLanes(a), IntToLanes(b)
+			var sum, diff [8]byte
+			var carries, borrows uint64
+			for i := range 8 {
+				sum[i], diff[i] = la[i]+lb[i], la[i]-lb[i]
+				if int(la[i])+int(lb[i]) > 255 {
+					carries |= 0x80 << (8 * i)
+				}
+				if la[i] < lb[i] {
+					borrows |= 0x80 << (8 * i)
+				}
+			}
+			if gotSum, gotCarries := AddBytesWithCarryOut(a, b); gotSum != LanesToInt(sum) || gotCarries != carries {
+				t.Errorf("AddBytesWithCarryOut(0x%016x, 0x%016x) = 0x%016x, 0x%016x; want 0x%016x, 0x%016x", a, b, gotSum, gotCarries, LanesToInt(sum), carries)
+			}
+			if gotDiff, gotBorrows := SubtractBytesWithBorrowOut(a, b); gotDiff != LanesToInt(diff) || gotBorrows != borrows {
+				t.Errorf("SubtractBytesWithBorrowOut(0x%016x, 0x%016x) = 0x%016x, 0x%016x; want 0x%016x, 0x%016x", a, b, gotDiff, gotBorrows, LanesToInt(diff), borrows)
+			}
+		}
+	}
+
+	// 16-bit addition emulated over byte pairs: carries out of low bytes feed their high bytes
+	a, b := uint64(0x00FF_12F0_FFFF_0180), uint64(0x0001_0020_0001_0080)
+	sum, carries := AddBytesWithCarryOut(a, b)
+	sum = AddBytesWithWrapping(sum, (carries&mEven)<<1)
+	var want uint64
+	for slot := range 4 {
+		want |= (a>>(16*slot) + b>>(16*slot)) & 0xFFFF << (16 * slot)
+	}
+	if sum != want {
+		t.Errorf("16-bit add over byte pairs = 0x%016x; want 0x%016x", sum, want)
+	}
+}
+
+// TestAccumulateBytesInto16 adds all-0xFF lanes right up to MaxAccumulations16 between
+// flushes, the worst case for slot overflow, and compares per-position totals with a loop.
+func TestAccumulateBytesInto16(t *testing.T) {
+	var acc0, acc1 uint64
+	var got [8]uint32
+	var want [8]uint32
+	for i := range 3 * MaxAccumulations16 {
+		v := uint64(0xFFFF_FFFF_FFFF_FFFF)
+		if i%3 == 1 {
+			v = uint64(i) * 0x9E37_79B9_7F4A_7C15
+		}
+		for k, c := range IntToLanes(v) {
+			want[k] += uint32(c)
+		}
+		AccumulateBytesInto16(&acc0, &acc1, v)
+		if (i+1)%MaxAccumulations16 == 0 {
+			FlushAccumulators16(&acc0, &acc1, &got)
+		}
+	}
+	if acc0 != 0 || acc1 != 0 {
+		t.Errorf("FlushAccumulators16 left 0x%016x, 0x%016x; want 0, 0", acc0, acc1)
+	}
+	if got != want {
+		t.Errorf("accumulated totals = %v; want %v", got, want)
+	}
+}
@@ -0,0 +1,100 @@
+package swar
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+// TestSumAbsoluteDifferences compares with a scalar loop for every length, including
+// maximal differences over more than sadFlushLanes lanes to prove the 16-bit slots never overflow.
+func TestSumAbsoluteDifferences(t *testing.T) {
+	run := func(a, b []byte) {
+		want := uint64(0)
+		for i := 0; i < min(len(a), len(b)); i++ {
+			want += uint64(max(a[i], b[i]) - min(a[i], b[i]))
+		}
+		if got := SumAbsoluteDifferences(a, b); got != want {
+			t.Errorf("SumAbsoluteDifferences(len %d, len %d) = %d; want %d", len(a), len(b), got, want)
+		}
+	}
+
+	a := make([]byte, 100)
+	b := make([]byte, 100)
+	for i := range a {
+		a[i], b[i] = byte(i*37+0xF0), byte(i*101)
+	}
+	for n := 0; n <= len(a); n++ {
+		run(a[:n], b)
+		run(a, b[:n])
+	}
+	zeros, ones := make([]byte, 8*sadFlushLanes*3+5), make([]byte, 8*sadFlushLanes*3+5)
+	for i := range ones {
+		ones[i] = 0xFF
+	}
+	run(zeros, ones)
+	run(ones, zeros)
+}
+
+// TestMeanSquaredError compares with a scalar loop and checks PSNR at its edge cases:
+// identical buffers are infinite and maximal error is exactly 0 dB.
+func TestMeanSquaredError(t *testing.T) {
+	run := func(a, b []byte) {
+		n := min(len(a), len(b))
+		want := 0.0
+		for i := 0; i < n; i++ {
+			d := float64(a[i]) - float64(b[i])
+			want += d * d
+		}
+		if n > 0 {
+			want /= float64(n)
+		}
+		if got := MeanSquaredError(a, b); got != want {
+			t.Errorf("MeanSquaredError(len %d, len %d) = %v; want %v", len(a), len(b), got, want)
+		}
+	}
+
+	a := make([]byte, 100)
+	b := make([]byte, 100)
+	for i := range a {
+		a[i], b[i] = byte(i*37+0xF0), byte(i*101)
+	}
+	for n := 0; n <= len(a); n++ {
+		run(a[:n], b)
+	}
+	zeros, ones := make([]byte, 8*mseFlushLanes*2+3), bytes.Repeat([]byte{0xFF}, 8*mseFlushLanes*2+3)
+	run(zeros, ones)
+
+	if got := PSNR(a, a); !math.IsInf(got, 1) {
+		t.Errorf("PSNR(a, a) = %v; want +Inf", got)
+	}
+	if got := PSNR(zeros, ones); got != 0 {
+		t.Errorf("PSNR(zeros, ones) = %v; want 0", got)
+	}
+}
+
+// TestDotProductBytes compares with a scalar loop for mismatched lengths and for
+// all-0xFF vectors long enough to cross several accumulator flushes.
+func TestDotProductBytes(t *testing.T) {
+	run := func(a, b []byte) {
+		want := uint64(0)
+		for i := 0; i < min(len(a), len(b)); i++ {
+			want += uint64(a[i]) * uint64(b[i])
+		}
+		if got := DotProductBytes(a, b); got != want {
+			t.Errorf("DotProductBytes(len %d, len %d) = %d; want %d", len(a), len(b), got, want)
+		}
+	}
+
+	a := make([]byte, 100)
+	b := make([]byte, 100)
+	for i := range a {
+		a[i], b[i] = byte(i*37+0xF0), byte(i*101)
+	}
+	for n := 0; n <= len(a); n++ {
+		run(a[:n], b)
+		run(a, b[:n])
+	}
+	ones := bytes.Repeat([]byte{0xFF}, 8*mseFlushLanes*3+7)
+	run(ones, ones)
+}
@@ -0,0 +1,196 @@
+package swar
+
+import "math/bits"
+
+// ParseEightDigits converts 8 ASCII digits, most significant first in memory, to a number
+// Pairs of digits are merged with multiply-accumulate steps; reports false on non-digits
+func ParseEightDigits(lane uint64) (uint32, bool) {
+	if digitMask(lane) != HighBits {
+		return 0, false
+	}
+	v := lane - Dupe('0')
+	v = (v*10 + v>>8) & 0x00FF_00FF_00FF_00FF
+	v = (v*100 + v>>16) & 0x0000_FFFF_0000_FFFF
+	v = (v*10000 + v>>32) & 0xFFFF_FFFF
+	return uint32(v), true
+}
+
+// ParseUint parses b as an unsigned decimal number eight digits at a time
+// Reports false if b is empty, contains a non-digit, or overflows a uint64
+func ParseUint(b []byte) (uint64, bool) {
+	if len(b) == 0 {
+		return 0, false
+	}
+	head := len(b) % 8
+	if head == 0 {
+		head = 8
+	}
+	first, ok := parseShortDigits(b[:head])
+	if !ok {
+		return 0, false
+	}
+	n := uint64(first)
+	for i := head; i < len(b); i += 8 {
+		chunk, ok := ParseEightDigits(loadLane(b, i))
+		if !ok {
+			return 0, false
+		}
+		hi, lo := bits.Mul64(n, 100_000_000)
+		var carry uint64
+		n, carry = bits.Add64(lo, uint64(chunk), 0)
+		if hi != 0 || carry != 0 {
+			return 0, false
+		}
+	}
+	return n, true
+}
+
+// parseShortDigits parses up to 8 digits by padding them with leading zeros
+func parseShortDigits(b []byte) (uint32, bool) {
+	lane := IntToLanes(Dupe('0'))
+	copy(lane[8-len(b):], b)
+	return ParseEightDigits(LanesToInt(lane))
+}
+
+// digitMask sets the high bit of every ASCII digit 0-9
+func digitMask(v uint64) uint64 {
+	return HighBitWhereGreater(v, Dupe('0'-1)) & HighBitWhereLess(v, Dupe('9'+1))
+}
+
+// ParseIPv4 parses a dotted-quad IPv4 address such as "192.168.0.1"
+// Dots and digits are classified a lane at a time; octets reject leading zeros like net/netip
+func ParseIPv4(b []byte) ([4]byte, bool) {
+	var ip [4]byte
+	if len(b) < len("0.0.0.0") || len(b) > len("255.255.255.255") {
+		return ip, false
+	}
+	lo, hi := loadLane(b, 0), uint64(0)
+	if len(b) > 8 {
+		hi = loadLane(b, 8)
+	}
+	valid := uint16(1)<<len(b) - 1
+	dots := uint16(ExtractLowBits(HighBitWhereEqual(lo, Dupe('.'))>>7)) |
+		uint16(ExtractLowBits(HighBitWhereEqual(hi, Dupe('.'))>>7))<<8
+	digits := uint16(ExtractLowBits(digitMask(lo)>>7)) | uint16(ExtractLowBits(digitMask(hi)>>7))<<8
+	dots, digits = dots&valid, digits&valid
+	if dots|digits != valid || bits.OnesCount16(dots) != 3 {
+		return ip, false
+	}
+	start := 0
+	for i := range ip {
+		end := len(b)
+		if i < 3 {
+			end = bits.TrailingZeros16(dots)
+			dots &= dots - 1
+		}
+		if n := end - start; n < 1 || n > 3 || (n > 1 && b[start] == '0') {
+			return ip, false
+		}
+		octet, _ := parseShortDigits(b[start:end])
+		if octet > 255 {
+			return ip, false
+		}
+		ip[i] = byte(octet)
+		start = end + 1
+	}
+	return ip, true
+}
+
+const (
+	// dateSeparators marks the '-' positions of "YYYY-MM-" in the first lane of a date
+	dateSeparators uint64 = 0x8000_0080_0000_0000
+	// timeSeparators marks the ':' positions of "HH:MM:SS"
+	timeSeparators uint64 = 0x0000_8000_0080_0000
+)
+
+// ParseDateYYYYMMDD parses a "YYYY-MM-DD" calendar date such as the prefix of RFC 3339
+// Digits and separators are checked in one mask pass and converted as eight digits
+func ParseDateYYYYMMDD(b []byte) (year, month, day int, ok bool) {
+	if len(b) != len("2006-01-02") {
+		return 0, 0, 0, false
+	}
+	lo, hi := loadLane(b, 0), loadLane(b, 8)
+	layout := digitMask(lo)&^dateSeparators | HighBitWhereEqual(lo, Dupe('-'))&dateSeparators
+	if layout != HighBits || digitMask(hi)&0x8080 != 0x8080 {
+		return 0, 0, 0, false
+	}
+	n, _ := ParseEightDigits(lo&0xFFFF_FFFF | (lo>>40&0xFFFF)<<32 | (hi&0xFFFF)<<48)
+	year, month, day = int(n/10000), int(n/100%100), int(n%100)
+	if month < 1 || month > 12 || day < 1 || day > daysInMonth(year, month) {
+		return 0, 0, 0, false
+	}
+	return year, month, day, true
+}
+
+// ParseTimeHHMMSS parses a 24-hour "HH:MM:SS" wall clock time, accepting second 60 for leap seconds
+// Digits and separators are checked in one mask pass and converted as eight digits
+func ParseTimeHHMMSS(b []byte) (hour, minute, second int, ok bool) {
+	if len(b) != len("15:04:05") {
+		return 0, 0, 0, false
+	}
+	v := loadLane(b, 0)
+	layout := digitMask(v)&^timeSeparators | HighBitWhereEqual(v, Dupe(':'))&timeSeparators
+	if layout != HighBits {
+		return 0, 0, 0, false
+	}
+	n, _ := ParseEightDigits(Dupe('0')&0xFFFF | (v&0xFFFF)<<16 | (v>>24&0xFFFF)<<32 | (v>>48)<<48)
+	hour, minute, second = int(n/10000), int(n/100%100), int(n%100)
+	if hour > 23 || minute > 59 || second > 60 {
+		return 0, 0, 0, false
+	}
+	return hour, minute, second, true
+}
+
+// daysInMonth returns the number of days in month of the proleptic Gregorian year
+func daysInMonth(year, month int) int {
+	switch month {
+	case 2:
+		if year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+			return 29
+		}
+		return 28
+	case 4, 6, 9, 11:
+		return 30
+	}
+	return 31
+}
+
+// uuidHyphens marks the '-' positions of the 8-4-4-4-12 layout in each lane of a UUID
+var uuidHyphens = [5]uint64{0, 0x0000_8000_0000_0080, 0x8000_0000_0080_0000, 0, 0}
+
+// ValidateUUID reports whether b is a textual UUID in the 8-4-4-4-12 hex layout
+// Either letter case is accepted, matching ParseUUID
+func ValidateUUID(b []byte) bool {
+	_, ok := ParseUUID(b)
+	return ok
+}
+
+// ParseUUID decodes a textual UUID such as "123e4567-e89b-12d3-a456-426614174000"
+// Hyphens are checked with one mask per lane and the digits decoded 8 at a time
+func ParseUUID(b []byte) ([16]byte, bool) {
+	var out [16]byte
+	if len(b) != 36 {
+		return out, false
+	}
+	var lanes [5]uint64
+	for k := range lanes {
+		lanes[k] = loadLane(b, 8*k)
+		if HighBitWhereEqual(lanes[k], Dupe('-')) != uuidHyphens[k] {
+			return out, false
+		}
+	}
+	digits := [4]uint64{
+		lanes[0],
+		lanes[1]>>8&0xFFFF_FFFF | lanes[1]>>48<<32 | lanes[2]&0xFFFF<<48,
+		lanes[2]>>24&0xFFFF_FFFF | lanes[3]&0xFFFF_FFFF<<32,
+		lanes[3]>>32 | lanes[4]<<32,
+	}
+	for k, lane := range digits {
+		v, ok := decodeHexLane(lane)
+		if !ok {
+			return [16]byte{}, false
+		}
+		storeLane(out[:], 4*k, uint64(v))
+	}
+	return out, true
+}
@@ -0,0 +1,182 @@
+package swar
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestParseEightDigits verifies the multiply-accumulate decimal conversion on edge values
+// and that any non-digit byte, including the neighbours of '0' and '9', is rejected.
+func TestParseEightDigits(t *testing.T) {
+	run := func(s string, want uint32, wantOK bool) {
+		var lane [8]byte
+		copy(lane[:], s)
+		if got, ok := ParseEightDigits(LanesToInt(lane)); got != want || ok != wantOK {
+			t.Errorf("ParseEightDigits(%q) = %d, %v; want %d, %v", s, got, ok, want, wantOK)
+		}
+	}
+
+	run("00000000", 0, true)
+	run("12345678", 12345678, true)
+	run("99999999", 99999999, true)
+	run("00000001", 1, true)
+	run("10000000", 10000000, true)
+	run("1234567/", 0, false)
+	run(":2345678", 0, false)
+	run("1234 678", 0, false)
+}
+
+// TestParseUint verifies that chained eight digit chunks reproduce strconv.ParseUint,
+// including the short leading chunk, the exact overflow boundary and invalid input.
+func TestParseUint(t *testing.T) {
+	run := func(s string) {
+		want, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			want = 0
+		}
+		if got, ok := ParseUint([]byte(s)); got != want || ok != (err == nil) {
+			t.Errorf("ParseUint(%q) = %d, %v; want %d, %v", s, got, ok, want, err == nil)
+		}
+	}
+
+	run("")
+	run("0")
+	run("7")
+	run("1234567")
+	run("12345678")
+	run("123456789")
+	run("20240131235959")
+	run("00000000000000000000000042")
+	run("18446744073709551615")
+	run("18446744073709551616")
+	run("99999999999999999999")
+	run("123456789012345678901")
+	run("12345678x")
+	run("-1")
+	run("+1")
+}
+
+// TestParseIPv4 verifies dotted-quad parsing against net/netip for every octet layout
+// from 7 to 15 bytes, as well as malformed separators, leading zeros and range errors.
+func TestParseIPv4(t *testing.T) {
+	run := func(s string) {
+		want, err := netip.ParseAddr(s)
+		wantOK := err == nil && want.Is4()
+		got, ok := ParseIPv4([]byte(s))
+		if ok != wantOK || (ok && got != want.As4()) {
+			t.Errorf("ParseIPv4(%q) = %v, %v; want %v, %v", s, got, ok, want, wantOK)
+		}
+	}
+
+	for _, s := range []string{
+		"0.0.0.0", "1.2.3.4", "10.0.0.1", "127.0.0.1", "192.168.100.200", "255.255.255.255",
+		"1.22.33.255", "100.2.30.4", "256.1.1.1", "1.1.1.300", "01.2.3.4", "1.2.3.04", "1.2.3",
+		"1.2.3.4.", ".1.2.3.4", "1..2.3", "1.2.3.4.5", "1.2.3.a", "1234.1.1.1", "1.2.3.4 ",
+		"", "1.2.3.4/24", "255.255.255.2555",
+	} {
+		run(s)
+	}
+	for a := 0; a < 256; a += 17 {
+		for b := 0; b < 256; b += 51 {
+			run(fmt.Sprintf("%d.%d.%d.%d", a, b, 255-a, b/3))
+		}
+	}
+}
+
+// TestParseDateYYYYMMDD verifies date parsing against time.Parse, covering separator
+// positions, month lengths and leap years so that invalid calendar dates are rejected.
+func TestParseDateYYYYMMDD(t *testing.T) {
+	run := func(s string) {
+		want, err := time.Parse(time.DateOnly, s)
+		y, m, d, ok := ParseDateYYYYMMDD([]byte(s))
+		if ok != (err == nil) || (ok && (y != want.Year() || m != int(want.Month()) || d != want.Day())) {
+			t.Errorf("ParseDateYYYYMMDD(%q) = %d-%d-%d, %v; want %v, %v", s, y, m, d, ok, want, err)
+		}
+	}
+
+	for _, s := range []string{
+		"2024-01-31", "1999-12-31", "0001-01-01", "9999-12-31", "2024-02-29", "2023-02-29",
+		"2000-02-29", "1900-02-29", "2024-04-31", "2024-13-01", "2024-00-10", "2024-01-00",
+		"2024/01/31", "2024-1-31", "20240131", "2024-01-3x", "2024-01-311", "",
+	} {
+		run(s)
+	}
+}
+
+// TestParseTimeHHMMSS verifies wall clock parsing against time.Parse, including the upper
+// bound of every field and misplaced separators. Only the fixed-width layout is accepted,
+// and a leap second 60 is accepted where time.Parse would reject it.
+func TestParseTimeHHMMSS(t *testing.T) {
+	run := func(s string) {
+		leap := len(s) == len(time.TimeOnly) && s[6:] == "60"
+		if leap {
+			s = s[:6] + "59"
+		}
+		want, err := time.Parse(time.TimeOnly, s)
+		wantOK := err == nil && len(s) == len(time.TimeOnly) // time.Parse allows "1:23:45"
+		wantSec := want.Second()
+		if leap {
+			s, wantSec = s[:6]+"60", 60
+		}
+		h, m, sec, ok := ParseTimeHHMMSS([]byte(s))
+		if ok != wantOK || (ok && (h != want.Hour() || m != want.Minute() || sec != wantSec)) {
+			t.Errorf("ParseTimeHHMMSS(%q) = %d:%d:%d, %v; want %v, %v", s, h, m, sec, ok, want, err)
+		}
+	}
+
+	for _, s := range []string{
+		"00:00:00", "23:59:59", "12:34:56", "24:00:00", "23:60:00", "23:59:60", "23:59:61", "24:00:60",
+		"1:23:45", "12-34-56", "12:34:5x", "12:34:567", "",
+	} {
+		run(s)
+	}
+}
+
+// TestParseUUID verifies UUID parsing against a scalar decode of the same text. Every
+// byte value is substituted at every position, so misplaced or missing hyphens and
+// non-hex characters in any group are all exercised.
+func TestParseUUID(t *testing.T) {
+	reference := func(s string) ([16]byte, bool) {
+		var out [16]byte
+		if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return out, false
+		}
+		digits := s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:36]
+		if strings.Contains(digits, "-") || strings.Contains(digits, "+") {
+			return out, false
+		}
+		_, err := hex.Decode(out[:], []byte(digits))
+		return out, err == nil
+	}
+	run := func(s string) {
+		want, wantOK := reference(s)
+		if !wantOK {
+			want = [16]byte{}
+		}
+		got, ok := ParseUUID([]byte(s))
+		if ok != wantOK || got != want {
+			t.Errorf("ParseUUID(%q) = %x, %v; want %x, %v", s, got, ok, want, wantOK)
+		}
+		if valid := ValidateUUID([]byte(s)); valid != wantOK {
+			t.Errorf("ValidateUUID(%q) = %v; want %v", s, valid, wantOK)
+		}
+	}
+
+	run("123e4567-e89b-12d3-a456-426614174000")
+	run("00000000-0000-0000-0000-000000000000")
+	run("FFFFFFFF-ffff-FFFF-ffff-FFFFFFFFFFFF")
+	run("123e4567e89b12d3a456426614174000")
+	run("123e4567-e89b-12d3-a456-42661417400")
+	run("{123e4567-e89b-12d3-a456-426614174000}")
+	base := "123e4567-e89b-12d3-a456-426614174000"
+	for i := range base {
+		for c := 0; c < 256; c++ {
+			run(base[:i] + string([]byte{byte(c)}) + base[i+1:])
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package swar
+
+import "math/bits"
+
+// Rng generates pseudo-random lanes with xoshiro256**
+// Not cryptographically secure; cheap enough to dither or fuzz every lane of a buffer
+type Rng struct {
+	s [4]uint64
+}
+
+// NewRng creates a generator whose state is expanded from seed with SplitMix64
+// The same seed always gives the same sequence, so randomized tests reproduce
+func NewRng(seed uint64) *Rng {
+	r := &Rng{}
+	for i := range r.s {
+		seed += 0x9E37_79B9_7F4A_7C15
+		z := seed
+		z = (z ^ z>>30) * 0xBF58_476D_1CE4_E5B9
+		z = (z ^ z>>27) * 0x94D0_49BB_1331_11EB
+		r.s[i] = z ^ z>>31
+	}
+	return r
+}
+
+// Uint64 returns a uniformly random lane and advances the generator
+// Every byte is independently uniform, so the result doubles as eight random bytes
+func (r *Rng) Uint64() uint64 {
+	s := &r.s
+	out := bits.RotateLeft64(s[1]*5, 7) * 9
+	t := s[1] << 17
+	s[2] ^= s[0]
+	s[3] ^= s[1]
+	s[1] ^= s[2]
+	s[0] ^= s[3]
+	s[2] ^= t
+	s[3] = bits.RotateLeft64(s[3], 45)
+	return out
+}
+
+// RandomBytesInRange returns random bytes in the inclusive range [lo, hi], given in either order
+// Multiply-high scaling needs no division; each value gets floor or ceil of 256/(hi-lo+1) in 256
+func (r *Rng) RandomBytesInRange(lo, hi byte) uint64 {
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	v := r.Uint64()
+	span := uint64(hi-lo) + 1
+	even := (v & mEven) * span >> 8 & mEven
+	odd := (v >> 8 & mEven) * span & (mEven << 8)
+	return (even | odd) + Dupe(lo)
+}
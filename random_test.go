@@ -0,0 +1,60 @@
+package swar
+
+import "testing"
+
+// TestRngUint64 pins the generator to published outputs: SplitMix64's first value for seed 0
+// and xoshiro256**'s first values from state {1, 2, 3, 4}, so a seed gives the same
+// sequence on every platform and release.
+func TestRngUint64(t *testing.T) {
+	if got, want := NewRng(0).s[0], uint64(0xE220_A839_7B1D_CDAF); got != want {
+		t.Errorf("NewRng(0) state[0] = 0x%016x; want 0x%016x", got, want)
+	}
+	r := &Rng{s: [4]uint64{1, 2, 3, 4}}
+	for i, want := range []uint64{11520, 0, 1509978240, 1215971899390074240} {
+		if got := r.Uint64(); got != want {
+			t.Errorf("Uint64() call %d = %d; want %d", i, got, want)
+		}
+	}
+	a, b := NewRng(42), NewRng(42)
+	for range 100 {
+		if x, y := a.Uint64(), b.Uint64(); x != y {
+			t.Fatalf("NewRng(42) sequences differ: 0x%016x and 0x%016x", x, y)
+		}
+	}
+}
+
+// TestRandomBytesInRange draws many lanes for several ranges and checks that every byte is
+// in range and that each value occurs about as often as its share of the 256 random bytes.
+func TestRandomBytesInRange(t *testing.T) {
+	run := func(lo, hi byte) {
+		r := NewRng(uint64(lo)<<8 | uint64(hi))
+		var counts [256]int
+		const lanes = 100000
+		for range lanes {
+			for _, c := range IntToLanes(r.RandomBytesInRange(lo, hi)) {
+				counts[c]++
+			}
+		}
+		first, last := min(lo, hi), max(lo, hi)
+		// Each value owns floor or ceil of 256/span of the 256 possible random bytes
+		span := int(last-first) + 1
+		fewest, most := 8*lanes*(256/span)/256, 8*lanes*((256+span-1)/span)/256
+		for c, n := range counts {
+			inside := byte(c) >= first && byte(c) <= last
+			if !inside && n != 0 {
+				t.Errorf("RandomBytesInRange(%d, %d) produced %d %d times", lo, hi, c, n)
+			}
+			if inside && (n < fewest*9/10 || n > most*11/10) {
+				t.Errorf("RandomBytesInRange(%d, %d) produced %d %d times; want %d to %d", lo, hi, c, n, fewest, most)
+			}
+		}
+	}
+
+	run(0, 255)
+	run(0, 0)
+	run(7, 7)
+	run('0', '9')
+	run(100, 200)
+	run(250, 255)
+	run(200, 100)
+}
@@ -0,0 +1,49 @@
+package ref
+
+// MixAudio8 writes a[i]+b[i]-128 clamped to 0-255 over the common length of a and b and returns it
+func MixAudio8(dst, a, b []byte) int {
+	out := make([]byte, min(len(a), len(b)))
+	for i := range out {
+		out[i] = clamp255(int(a[i]) + int(b[i]) - 128)
+	}
+	return copy(dst, out)
+}
+
+// ScaleAudio8 writes 128 ± round(|c-128|*volume/255) for each sample c of src
+func ScaleAudio8(dst, src []byte, volume uint8) {
+	mapBytes(dst, src, func(c byte) byte {
+		if c < 128 {
+			return 128 - divide255((128-int(c))*int(volume))
+		}
+		return 128 + divide255((int(c)-128)*int(volume))
+	})
+}
+
+// MuLawToLinear8 decodes each byte with the G.711 reference decoder and keeps the top 8 bits, offset by 128
+func MuLawToLinear8(dst, src []byte) {
+	mapBytes(dst, src, func(c byte) byte {
+		u := ^c
+		mag := (int(u&0x0F)<<3+0x84)<<(u>>4&7) - 0x84
+		if u&0x80 != 0 {
+			return byte(128 - mag>>8)
+		}
+		return byte(128 + mag>>8)
+	})
+}
+
+// LinearToMuLaw8 encodes (c-128)<<8 for each byte c of src with the G.711 reference encoder
+func LinearToMuLaw8(dst, src []byte) {
+	mapBytes(dst, src, func(c byte) byte {
+		const bias, clip = 0x84, 32635
+		pcm, sign := (int(c)-128)<<8, 0
+		if pcm < 0 {
+			pcm, sign = -pcm, 0x80
+		}
+		pcm = min(pcm, clip) + bias
+		exp := 7
+		for pcm&(0x80<<exp) == 0 && exp > 0 {
+			exp--
+		}
+		return ^byte(sign | exp<<4 | pcm>>(exp+3)&0x0F)
+	})
+}
@@ -0,0 +1,36 @@
+package ref
+
+// ValidateBase64 reports whether b holds only standard base64 characters and a decodable length
+// With padding the length must be a multiple of 4, and up to two trailing '=' are allowed
+func ValidateBase64(b []byte, padding bool) bool {
+	if padding {
+		if len(b)%4 != 0 {
+			return false
+		}
+		for i := 0; i < 2 && len(b) > 0 && b[len(b)-1] == '='; i++ {
+			b = b[:len(b)-1]
+		}
+	}
+	for _, c := range b {
+		if !('A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '+' || c == '/') {
+			return false
+		}
+	}
+	return len(b)%4 != 1
+}
+
+// StripBase64Whitespace copies src to dst without space, '\t', '\n', '\v', '\f' and '\r'
+func StripBase64Whitespace(dst, src []byte) int {
+	out := make([]byte, 0, len(src))
+	for _, c := range src {
+		if !isSpace(c) {
+			out = append(out, c)
+		}
+	}
+	return copy(dst, out)
+}
+
+// isSpace reports whether c is ASCII whitespace
+func isSpace(c byte) bool {
+	return c == ' ' || '\t' <= c && c <= '\r'
+}
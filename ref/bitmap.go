@@ -0,0 +1,106 @@
+package ref
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// MatchBitmap sets bit i%64 of word i/64 where pred sets 0x80 in the lane holding only b[i] in byte 0
+// pred must treat bytes independently, as the HighBitWhere* functions do
+func MatchBitmap(b []byte, pred func(uint64) uint64) []uint64 {
+	bitmap := make([]uint64, (len(b)+63)/64)
+	for i, c := range b {
+		if matches(pred, c) {
+			bitmap[i/64] |= 1 << (i % 64)
+		}
+	}
+	return bitmap
+}
+
+// matches reports whether pred sets 0x80 in byte 0 for the lane holding only c
+func matches(pred func(uint64) uint64, c byte) bool {
+	return pred(uint64(c))&0x80 != 0
+}
+
+// bitSet reports whether bit i%64 of bitmap[i/64] is set
+func bitSet(bitmap []uint64, i int) bool {
+	return bitmap[i/64]>>(i%64)&1 == 1
+}
+
+// Rank counts the set bits of bitmap at positions below i
+func Rank(bitmap []uint64, i int) int {
+	n := 0
+	for p := range i {
+		if bitSet(bitmap, p) {
+			n++
+		}
+	}
+	return n
+}
+
+// Select returns the position of the set bit preceded by n others, or -1
+func Select(bitmap []uint64, n int) int {
+	for p := range 64 * len(bitmap) {
+		if bitSet(bitmap, p) {
+			if n == 0 {
+				return p
+			}
+			n--
+		}
+	}
+	return -1
+}
+
+// SetBits yields the positions of the set bits of bitmap in ascending order
+func SetBits(bitmap []uint64) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for p := range 64 * len(bitmap) {
+			if bitSet(bitmap, p) && !yield(p) {
+				return
+			}
+		}
+	}
+}
+
+// AndBitmaps writes a[i]&b[i] to dst[i] over the common length of all three and returns it
+func AndBitmaps(dst, a, b []uint64) int {
+	return combineBitmaps(dst, a, b, func(x, y uint64) uint64 { return x & y })
+}
+
+// OrBitmaps writes a[i]|b[i] to dst[i] over the common length of all three and returns it
+func OrBitmaps(dst, a, b []uint64) int {
+	return combineBitmaps(dst, a, b, func(x, y uint64) uint64 { return x | y })
+}
+
+// AndNotBitmaps writes a[i]&^b[i] to dst[i] over the common length of all three and returns it
+func AndNotBitmaps(dst, a, b []uint64) int {
+	return combineBitmaps(dst, a, b, func(x, y uint64) uint64 { return x &^ y })
+}
+
+// combineBitmaps writes f(a[i], b[i]) to dst[i] over the common length of all three
+func combineBitmaps(dst, a, b []uint64, f func(x, y uint64) uint64) int {
+	out := make([]uint64, min(len(dst), len(a), len(b)))
+	for i := range out {
+		out[i] = f(a[i], b[i])
+	}
+	return copy(dst, out)
+}
+
+// PopcountBitmap counts the set bits of bitmap
+func PopcountBitmap(bitmap []uint64) int {
+	n := 0
+	for _, word := range bitmap {
+		n += bits.OnesCount64(word)
+	}
+	return n
+}
+
+// BitIndices yields the positions of the set bits of mask in ascending order
+func BitIndices(mask byte) iter.Seq[int] {
+	return BitIndices64(uint64(mask))
+}
+
+// BitIndices64 yields the positions of the set bits of mask in ascending order
+func BitIndices64(mask uint64) iter.Seq[int] {
+	return SetBits([]uint64{mask})
+}
@@ -0,0 +1,133 @@
+package ref
+
+// ReplaceByte copies src to dst with every occurrence of old replaced by new and returns the count
+func ReplaceByte(dst, src []byte, old, new byte) int {
+	count := 0
+	for i, c := range src {
+		if c == old {
+			c = new
+			count++
+		}
+		dst[i] = c
+	}
+	return count
+}
+
+// FillBytes sets every byte of b to c
+func FillBytes(b []byte, c byte) {
+	for i := range b {
+		b[i] = c
+	}
+}
+
+// FillPattern sets b[i] to pattern[i%len(pattern)], leaving b unchanged for an empty pattern
+func FillPattern(b, pattern []byte) {
+	if len(pattern) == 0 {
+		return
+	}
+	for i := range b {
+		b[i] = pattern[i%len(pattern)]
+	}
+}
+
+// NthIndexByte returns the index of the zero-based n-th occurrence of c in b, or -1
+func NthIndexByte(b []byte, c byte, n int) int {
+	for i, x := range b {
+		if x == c {
+			if n == 0 {
+				return i
+			}
+			n--
+		}
+	}
+	return -1
+}
+
+// CountAnyBytes counts the bytes of b that equal any byte of set
+func CountAnyBytes(b []byte, set ...byte) int {
+	var members [256]bool
+	for _, c := range set {
+		members[c] = true
+	}
+	return countBytes(b, func(c byte) bool { return members[c] })
+}
+
+// CountMatches counts the bytes c of b for which classifier(uint64(c)) has bit 0x80 set
+func CountMatches(b []byte, classifier func(uint64) uint64) int {
+	return countBytes(b, func(c byte) bool { return classifier(uint64(c))&0x80 != 0 })
+}
+
+// RemoveByte copies the bytes of src other than c to the front of dst and returns how many
+func RemoveByte(dst, src []byte, c byte) int {
+	return Compress(dst, src, func(v uint64) uint64 { return uint64(flag(byte(v) == c)) })
+}
+
+// Compress copies the bytes c of src for which mask(uint64(c)) has bit 0x80 clear to the front of dst
+func Compress(dst, src []byte, mask func(uint64) uint64) int {
+	out := make([]byte, 0, len(src))
+	for _, c := range src {
+		if mask(uint64(c))&0x80 == 0 {
+			out = append(out, c)
+		}
+	}
+	return copy(dst, out)
+}
+
+// Expand copies successive bytes of src to the bytes of dst selected by bit i%64 of mask[i/64]
+// The other bytes, and selected bytes once src runs out, are zeroed; returns the src bytes used
+func Expand(dst, src []byte, mask []uint64) int {
+	out := make([]byte, len(dst))
+	n := 0
+	for i := range out {
+		if mask[i/64]>>(i%64)&1 != 0 && n < len(src) {
+			out[i] = src[n]
+			n++
+		}
+	}
+	copy(dst, out)
+	return n
+}
+
+// AddSaturating sets dst[i] to min(dst[i]+src[i], 255) over the common length
+func AddSaturating(dst, src []byte) {
+	combine(dst, src, func(x, y byte) byte { return byte(min(int(x)+int(y), 255)) })
+}
+
+// SubtractSaturating sets dst[i] to max(dst[i]-src[i], 0) over the common length
+func SubtractSaturating(dst, src []byte) {
+	combine(dst, src, func(x, y byte) byte { return byte(max(int(x)-int(y), 0)) })
+}
+
+// MaxInto sets dst[i] to max(dst[i], src[i]) over the common length
+func MaxInto(dst, src []byte) {
+	combine(dst, src, func(x, y byte) byte { return max(x, y) })
+}
+
+// MinInto sets dst[i] to min(dst[i], src[i]) over the common length
+func MinInto(dst, src []byte) {
+	combine(dst, src, func(x, y byte) byte { return min(x, y) })
+}
+
+// combine sets dst[i] to f(dst[i], src[i]) over the common length
+func combine(dst, src []byte, f func(x, y byte) byte) {
+	for i := range min(len(dst), len(src)) {
+		dst[i] = f(dst[i], src[i])
+	}
+}
+
+// GatherBytes sets dst[i] = src[idx[i]] for each index
+func GatherBytes(dst, src []byte, idx []int32) {
+	out := make([]byte, len(idx))
+	for i, x := range idx {
+		out[i] = src[x]
+	}
+	copy(dst[:len(idx)], out)
+}
+
+// ScatterBytes sets dst[idx[i]] = src[i] for each index in order, so later bytes win
+func ScatterBytes(dst, src []byte, idx []int32) {
+	in := append([]byte(nil), src[:len(idx)]...)
+	for i, x := range idx {
+		dst[x] = in[i]
+	}
+}
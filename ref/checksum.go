@@ -0,0 +1,84 @@
+package ref
+
+import "math/bits"
+
+// InternetChecksum returns the complement of the ones' complement sum of b as big-endian words
+func InternetChecksum(b []byte) uint16 {
+	return ^OnesComplementSum(0, b, 0)
+}
+
+// OnesComplementSum adds the bytes of b to sum with end-around carry, as big-endian 16-bit words
+// Byte i sits at message position offset+i, which picks its half of the word
+func OnesComplementSum(sum uint16, b []byte, offset int) uint16 {
+	acc := uint32(sum)
+	for i, c := range b {
+		if (offset+i)%2 == 0 {
+			acc += uint32(c) << 8
+		} else {
+			acc += uint32(c)
+		}
+		acc = acc&0xFFFF + acc>>16
+	}
+	return uint16(acc)
+}
+
+// Adler32 returns the Adler-32 checksum of b from the byte recurrence of RFC 1950
+func Adler32(b []byte) uint32 {
+	s1, s2 := uint32(1), uint32(0)
+	for _, c := range b {
+		s1 = (s1 + uint32(c)) % 65521
+		s2 = (s2 + s1) % 65521
+	}
+	return s2<<16 | s1
+}
+
+// Fletcher16 returns the Fletcher-16 checksum of b, the second sum in the high byte
+func Fletcher16(b []byte) uint16 {
+	s1, s2 := uint16(0), uint16(0)
+	for _, c := range b {
+		s1 = (s1 + uint16(c)) % 255
+		s2 = (s2 + s1) % 255
+	}
+	return s2<<8 | s1
+}
+
+// Fletcher32 returns the Fletcher-32 checksum of b as zero-padded little-endian words
+func Fletcher32(b []byte) uint32 {
+	s1, s2 := uint32(0), uint32(0)
+	for i := 0; i < len(b); i += 2 {
+		word := uint32(b[i])
+		if i+1 < len(b) {
+			word |= uint32(b[i+1]) << 8
+		}
+		s1 = (s1 + word) % 65535
+		s2 = (s2 + s1) % 65535
+	}
+	return s2<<16 | s1
+}
+
+// XorChecksum returns the XOR of every byte of b
+func XorChecksum(b []byte) byte {
+	var x byte
+	for _, c := range b {
+		x ^= c
+	}
+	return x
+}
+
+// LRC returns the negated byte sum of b modulo 256
+func LRC(b []byte) byte {
+	var sum byte
+	for _, c := range b {
+		sum += c
+	}
+	return -sum
+}
+
+// Parity reports whether b holds an odd number of set bits
+func Parity(b []byte) bool {
+	n := 0
+	for _, c := range b {
+		n += bits.OnesCount8(c)
+	}
+	return n%2 == 1
+}
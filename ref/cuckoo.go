@@ -0,0 +1,14 @@
+package ref
+
+import "bytes"
+
+// BucketMatch sets 0x80 in each slot of bucket equal to fp
+func BucketMatch(bucket uint64, fp byte) uint64 {
+	return HighBitWhereEqual(bucket, Dupe(fp))
+}
+
+// FindEmptySlot returns the index of the first zero slot of bucket, or -1
+func FindEmptySlot(bucket uint64) int {
+	b := Bytes(bucket)
+	return bytes.IndexByte(b[:], 0)
+}
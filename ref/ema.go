@@ -0,0 +1,55 @@
+package ref
+
+// EMA8 tracks eight exponential moving averages in 8.7 fixed point, one per byte of a lane
+type EMA8 struct {
+	avg   [8]int
+	shift uint
+}
+
+// NewEMA8 returns averages starting at the bytes of initial that step by 1/2^min(shift, 7)
+func NewEMA8(shift uint, initial uint64) *EMA8 {
+	e := &EMA8{shift: min(shift, 7)}
+	e.Reset(initial)
+	return e
+}
+
+// Reset sets each average to the corresponding byte of v
+func (e *EMA8) Reset(v uint64) {
+	e.ResetWhere(v, Dupe(0x80))
+}
+
+// ResetWhere sets the averages of the bytes where mask has 0x80 set to the bytes of sample
+func (e *EMA8) ResetWhere(sample, mask uint64) {
+	s, m := Bytes(sample), Bytes(mask)
+	for i := range e.avg {
+		if m[i]&0x80 != 0 {
+			e.avg[i] = int(s[i]) << 7
+		}
+	}
+}
+
+// Update moves each average towards its byte of sample by the difference >> shift, truncated, and returns Value
+func (e *EMA8) Update(sample uint64) uint64 {
+	for i, x := range Bytes(sample) {
+		if d := int(x)<<7 - e.avg[i]; d >= 0 {
+			e.avg[i] += d >> e.shift
+		} else {
+			e.avg[i] -= -d >> e.shift
+		}
+	}
+	return e.Value()
+}
+
+// Value returns each average rounded to the nearest byte, halves rounding up
+func (e *EMA8) Value() uint64 {
+	var b [8]byte
+	for i, a := range e.avg {
+		b[i] = byte((a + 64) >> 7)
+	}
+	return Lane(b)
+}
+
+// Deviation returns |sample - Value()| per byte
+func (e *EMA8) Deviation(sample uint64) uint64 {
+	return AbsoluteDifferenceBetweenBytes(sample, e.Value())
+}
@@ -0,0 +1,60 @@
+package ref
+
+import "bytes"
+
+// unreservedChars matches the RFC 3986 unreserved bytes
+var unreservedChars = NewRangeMatcher(
+	[2]byte{'-', '.'}, [2]byte{'0', '9'}, [2]byte{'A', 'Z'}, [2]byte{'_', '_'}, [2]byte{'a', 'z'}, [2]byte{'~', '~'},
+)
+
+// HighBitWhereNotUnreserved sets 0x80 in each byte of v outside the RFC 3986 unreserved set
+func HighBitWhereNotUnreserved(v uint64) uint64 {
+	return unreservedChars.Mask(v) ^ Dupe(0x80)
+}
+
+// NeedsPercentEscape reports whether b holds a byte outside the unreserved set and the index of the first
+func NeedsPercentEscape(b []byte) (bool, int) {
+	for i, c := range b {
+		if HighBitWhereNotUnreserved(uint64(c))&0x80 != 0 {
+			return true, i
+		}
+	}
+	return false, -1
+}
+
+// IndexHTMLEscapable returns the index of the first of & < > " ' in b, or -1
+func IndexHTMLEscapable(b []byte) int {
+	return bytes.IndexAny(b, `&<>"'`)
+}
+
+// ControlAllowance selects the control bytes IndexControlByte accepts
+type ControlAllowance uint8
+
+const (
+	// AllowTab permits horizontal tab (0x09)
+	AllowTab ControlAllowance = 1 << iota
+	// AllowLF permits line feed (0x0A)
+	AllowLF
+	// AllowCR permits carriage return (0x0D)
+	AllowCR
+)
+
+// ContainsControlBytes reports whether IndexControlByte finds a byte
+func ContainsControlBytes(b []byte, allow ControlAllowance) bool {
+	return IndexControlByte(b, allow) >= 0
+}
+
+// IndexControlByte returns the index of the first byte below 0x20 that allow does not accept, or -1
+func IndexControlByte(b []byte, allow ControlAllowance) int {
+	for i, c := range b {
+		switch {
+		case c >= 0x20:
+		case c == '\t' && allow&AllowTab != 0:
+		case c == '\n' && allow&AllowLF != 0:
+		case c == '\r' && allow&AllowCR != 0:
+		default:
+			return i
+		}
+	}
+	return -1
+}
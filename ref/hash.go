@@ -0,0 +1,10 @@
+package ref
+
+import "hash/fnv"
+
+// HashFNV1a returns the 64-bit FNV-1a hash of b
+func HashFNV1a(b []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(b)
+	return h.Sum64()
+}
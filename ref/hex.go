@@ -0,0 +1,114 @@
+package ref
+
+import "encoding/hex"
+
+// hexDigits holds the lower case hexadecimal digits in value order
+const hexDigits = "0123456789abcdef"
+
+// EncodeHex writes two lower case hex digits per byte of src to dst, high nibble first
+func EncodeHex(dst, src []byte) int {
+	out := make([]byte, 0, 2*len(src))
+	for _, c := range src {
+		out = append(out, hexDigits[c>>4], hexDigits[c&0x0F])
+	}
+	return copy(dst, out)
+}
+
+// DecodeHex decodes digit pairs from src into dst until the first invalid byte
+// Errors match encoding/hex.Decode: InvalidByteError, or ErrLength for a trailing odd digit
+func DecodeHex(dst, src []byte) (int, error) {
+	out := make([]byte, 0, len(src)/2)
+	for i := 0; i+1 < len(src); i += 2 {
+		hi, ok := hexValue(src[i])
+		if !ok {
+			return copy(dst, out), hex.InvalidByteError(src[i])
+		}
+		lo, ok := hexValue(src[i+1])
+		if !ok {
+			return copy(dst, out), hex.InvalidByteError(src[i+1])
+		}
+		out = append(out, hi<<4|lo)
+	}
+	n := copy(dst, out)
+	if len(src)%2 == 1 {
+		if _, ok := hexValue(src[len(src)-1]); !ok {
+			return n, hex.InvalidByteError(src[len(src)-1])
+		}
+		return n, hex.ErrLength
+	}
+	return n, nil
+}
+
+// hexValue returns the value of the hex digit c in either case
+func hexValue(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// ValidateHex reports whether b has even length and only hex digits in either case
+func ValidateHex(b []byte) bool {
+	for _, c := range b {
+		if _, ok := hexValue(c); !ok {
+			return false
+		}
+	}
+	return len(b)%2 == 0
+}
+
+// NormalizeHexLower replaces each byte in A-F with its lower case letter
+func NormalizeHexLower(b []byte) {
+	for i, c := range b {
+		if 'A' <= c && c <= 'F' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+}
+
+// HexCaseMasks sets 0x80 in upper for bytes in A-F and in lower for bytes in a-f
+func HexCaseMasks(v uint64) (upper, lower uint64) {
+	upper = map1(v, func(x byte) byte { return flag('A' <= x && x <= 'F') })
+	lower = map1(v, func(x byte) byte { return flag('a' <= x && x <= 'f') })
+	return upper, lower
+}
+
+// IsMixedCaseHex reports whether b holds a byte in A-F and a byte in a-f
+func IsMixedCaseHex(b []byte) bool {
+	var upper, lower bool
+	for _, c := range b {
+		upper = upper || 'A' <= c && c <= 'F'
+		lower = lower || 'a' <= c && c <= 'f'
+	}
+	return upper && lower
+}
+
+// ParseHex16 decodes 16 hex digits as a big-endian uint64
+func ParseHex16(b []byte) (uint64, bool) {
+	if len(b) != 16 {
+		return 0, false
+	}
+	var v uint64
+	for _, c := range b {
+		d, ok := hexValue(c)
+		if !ok {
+			return 0, false
+		}
+		v = v<<4 | uint64(d)
+	}
+	return v, true
+}
+
+// FormatHex16 returns the 16 lower case hex digits of v, most significant first
+func FormatHex16(v uint64) [16]byte {
+	var out [16]byte
+	for i := range out {
+		out[i] = hexDigits[v>>(60-4*i)&0x0F]
+	}
+	return out
+}
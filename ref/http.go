@@ -0,0 +1,35 @@
+package ref
+
+import "bytes"
+
+// tokenChars matches the RFC 9110 tchar bytes
+var tokenChars = NewRangeMatcher(
+	[2]byte{'!', '!'}, [2]byte{'#', '\''}, [2]byte{'*', '+'}, [2]byte{'-', '.'},
+	[2]byte{'0', '9'}, [2]byte{'A', 'Z'}, [2]byte{'^', '`'}, [2]byte{'a', 'z'},
+	[2]byte{'|', '|'}, [2]byte{'~', '~'},
+)
+
+// IndexHeaderEnd returns the index of the first "\r\n\r\n" in b, or -1
+func IndexHeaderEnd(b []byte) int {
+	return bytes.Index(b, []byte("\r\n\r\n"))
+}
+
+// IndexCRLF returns the index of the first "\r\n" in b, or -1
+func IndexCRLF(b []byte) int {
+	return bytes.Index(b, []byte("\r\n"))
+}
+
+// IndexColon returns the index of the first ':' in b, or -1
+func IndexColon(b []byte) int {
+	return bytes.IndexByte(b, ':')
+}
+
+// HighBitWhereTokenChar sets 0x80 in each byte of v that is an RFC 9110 tchar
+func HighBitWhereTokenChar(v uint64) uint64 {
+	return tokenChars.Mask(v)
+}
+
+// IsToken reports whether b is a non-empty run of tchar bytes
+func IsToken(b []byte) bool {
+	return len(b) > 0 && CountMatches(b, tokenChars.Mask) == len(b)
+}
@@ -0,0 +1,178 @@
+package ref
+
+// pixelAlpha returns the alpha byte of the RGBA pixel holding b[i], or 0 if the pixel is cut short
+func pixelAlpha(b []byte, i int) int {
+	if a := i&^3 + 3; a < len(b) {
+		return int(b[a])
+	}
+	return 0
+}
+
+// BlendRGBA sets each byte of dst to min(255, s + round(d*(255-alpha)/255)) for src pixels s with alpha
+// Covers the common length of dst and src; a partial final pixel has alpha 0
+func BlendRGBA(dst, src []byte) {
+	src = src[:min(len(dst), len(src))]
+	out := make([]byte, len(src))
+	for i, s := range src {
+		out[i] = clamp255(int(s) + int(divide255(int(dst[i])*(255-pixelAlpha(src, i)))))
+	}
+	copy(dst, out)
+}
+
+// Blend writes round((a*weightA + b*(255-weightA))/255) to dst over the common length of a and b
+func Blend(dst, a, b []byte, weightA uint8) {
+	out := make([]byte, min(len(a), len(b)))
+	for i := range out {
+		out[i] = divide255(int(a[i])*int(weightA) + int(b[i])*(255-int(weightA)))
+	}
+	copy(dst, out)
+}
+
+// PremultiplyAlpha writes round(c*alpha/255) for the colour bytes of each RGBA pixel and keeps alpha
+// A partial final pixel has alpha 0
+func PremultiplyAlpha(dst, src []byte) {
+	out := make([]byte, len(src))
+	for i, c := range src {
+		out[i] = divide255(int(c) * pixelAlpha(src, i))
+		if i%4 == 3 {
+			out[i] = c
+		}
+	}
+	copy(dst, out)
+}
+
+// RGBAToGray writes (77r + 150g + 29b + 128) >> 8 for each complete RGBA pixel of src
+func RGBAToGray(dst, src []byte) {
+	out := make([]byte, len(src)/4)
+	for i := range out {
+		p := src[4*i:]
+		out[i] = byte((77*int(p[0]) + 150*int(p[1]) + 29*int(p[2]) + 128) >> 8)
+	}
+	copy(dst, out)
+}
+
+// Threshold writes 0xFF where src[i] > t and 0 elsewhere
+func Threshold(dst, src []byte, t byte) {
+	mapBytes(dst, src, func(c byte) byte {
+		if c > t {
+			return 0xFF
+		}
+		return 0
+	})
+}
+
+// ThresholdToBitmap sets bit i%64 of dst[i/64] where src[i] > t and clears it elsewhere
+func ThresholdToBitmap(dst []uint64, src []byte, t byte) {
+	clear(dst[:(len(src)+63)/64])
+	for i, c := range src {
+		if c > t {
+			dst[i/64] |= 1 << (i % 64)
+		}
+	}
+}
+
+// AdjustBrightness writes src[i]+delta clamped to 0-255
+func AdjustBrightness(dst, src []byte, delta int8) {
+	mapBytes(dst, src, func(c byte) byte { return clamp255(int(c) + int(delta)) })
+}
+
+// AddDither writes src[i] + pattern[8*(y%8)+x%8] clamped to 255, where x, y = i%width, i/width
+// Panics if width is not positive
+func AddDither(dst, src []byte, width int, pattern *[64]byte) {
+	if width <= 0 {
+		panic("swar: AddDither with non-positive width")
+	}
+	out := make([]byte, len(src))
+	for i, c := range src {
+		x, y := i%width, i/width
+		out[i] = clamp255(int(c) + int(pattern[8*(y%8)+x%8]))
+	}
+	copy(dst, out)
+}
+
+// QuantizeBytes writes src[i]*levels/256 to dst; panics if levels is outside 1-256
+func QuantizeBytes(dst, src []byte, levels int) {
+	if levels < 1 || levels > 256 {
+		panic("swar: QuantizeBytes with levels outside 1 to 256")
+	}
+	mapBytes(dst, src, func(c byte) byte { return byte(int(c) * levels / 256) })
+}
+
+// AdjustContrast writes 128 ± floor((|c-128|*num + den/2)/den), the offset capped at 255, clamped to 0-255
+// Panics if den is 0
+func AdjustContrast(dst, src []byte, num, den uint8) {
+	if den == 0 {
+		panic("swar: AdjustContrast with zero denominator")
+	}
+	mapBytes(dst, src, func(c byte) byte {
+		d := int(c) - 128
+		scaled := min(255, (max(d, -d)*int(num)+int(den/2))/int(den))
+		if d < 0 {
+			return clamp255(128 - scaled)
+		}
+		return clamp255(128 + scaled)
+	})
+}
+
+// InvertBytes writes 255-src[i]
+func InvertBytes(dst, src []byte) {
+	mapBytes(dst, src, func(c byte) byte { return 255 - c })
+}
+
+// SwizzleRGBA writes channel order[i]%4 of each complete RGBA pixel of src to channel i of dst
+func SwizzleRGBA(dst, src []byte, order [4]byte) {
+	out := make([]byte, len(src)&^3)
+	for p := 0; p < len(out); p += 4 {
+		for i, from := range order {
+			out[p+i] = src[p+int(from%4)]
+		}
+	}
+	copy(dst, out)
+}
+
+// ExpandRGB565 widens each little-endian RGB565 pixel to 3 bytes by replicating the top bits
+func ExpandRGB565(dst, src []byte) {
+	out := make([]byte, 0, len(src)/2*3)
+	for i := 0; i+1 < len(src); i += 2 {
+		p := int(src[i]) | int(src[i+1])<<8
+		r, g, b := p>>11, p>>5&0x3F, p&0x1F
+		out = append(out, byte(r<<3|r>>2), byte(g<<2|g>>4), byte(b<<3|b>>2))
+	}
+	copy(dst, out)
+}
+
+// PackRGB888To565 keeps the top 5, 6 and 5 bits of each RGB888 pixel as a little-endian uint16
+func PackRGB888To565(dst, src []byte) {
+	out := make([]byte, 0, len(src)/3*2)
+	for i := 0; i+2 < len(src); i += 3 {
+		p := int(src[i])>>3<<11 | int(src[i+1])>>2<<5 | int(src[i+2])>>3
+		out = append(out, byte(p), byte(p>>8))
+	}
+	copy(dst, out)
+}
+
+// Interleave4Planes writes r[i], g[i], b[i], a[i] to dst[4i:4i+4] for the length of the shortest plane
+func Interleave4Planes(dst, r, g, b, a []byte) {
+	out := make([]byte, 0, 4*len(r))
+	for i := range min(len(r), len(g), len(b), len(a)) {
+		out = append(out, r[i], g[i], b[i], a[i])
+	}
+	copy(dst, out)
+}
+
+// Deinterleave4 writes byte 4i, 4i+1, 4i+2 and 4i+3 of src to r[i], g[i], b[i] and a[i]
+func Deinterleave4(r, g, b, a, src []byte) {
+	planes := [4][]byte{}
+	for i, c := range src[:len(src)&^3] {
+		planes[i%4] = append(planes[i%4], c)
+	}
+	copy(r, planes[0])
+	copy(g, planes[1])
+	copy(b, planes[2])
+	copy(a, planes[3])
+}
+
+// clamp255 limits x to 0-255
+func clamp255(x int) byte {
+	return byte(min(max(x, 0), 255))
+}
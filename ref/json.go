@@ -0,0 +1,69 @@
+package ref
+
+// JSONIndex holds bitmaps with one bit per input byte: bit i%64 of word i/64 is b[i]
+type JSONIndex struct {
+	// Structural marks { } [ ] , : outside strings and every unescaped quote
+	Structural []uint64
+	// Backslash marks every '\' byte, escaped or not
+	Backslash []uint64
+	// Quoted marks bytes inside strings, including the opening but not the closing quote
+	Quoted []uint64
+}
+
+// ScanJSONStructure walks b once, tracking whether each byte is escaped and whether it is inside a string
+func ScanJSONStructure(b []byte) JSONIndex {
+	words := (len(b) + 63) / 64
+	idx := JSONIndex{make([]uint64, words), make([]uint64, words), make([]uint64, words)}
+	var escaped, inString bool
+	for i, c := range b {
+		bit := uint64(1) << (i % 64)
+		quote := c == '"' && !escaped
+		escaped = !escaped && c == '\\'
+		if quote {
+			inString = !inString
+		}
+		if c == '\\' {
+			idx.Backslash[i/64] |= bit
+		}
+		if inString {
+			idx.Quoted[i/64] |= bit
+		}
+		switch c {
+		case '{', '}', '[', ']', ',', ':':
+			if !inString {
+				idx.Structural[i/64] |= bit
+			}
+		case '"':
+			if quote {
+				idx.Structural[i/64] |= bit
+			}
+		}
+	}
+	return idx
+}
+
+// EscapedCharMask sets 0x80 in each byte of chunk that follows an unescaped backslash
+// *carryIn escapes byte 0 and is set to whether the byte after the chunk is escaped
+func EscapedCharMask(chunk uint64, carryIn *bool) uint64 {
+	var m [8]byte
+	escaped := *carryIn
+	for i, c := range Bytes(chunk) {
+		m[i] = flag(escaped)
+		escaped = !escaped && c == '\\'
+	}
+	*carryIn = escaped
+	return Lane(m)
+}
+
+// QuoteRegionMask sets 0x80 in each byte from an opening quote up to but not including its closing quote
+// quotes has 0x80 in each quote byte; *inQuote opens a region at byte 0 and is set to the state after the chunk
+func QuoteRegionMask(quotes uint64, inQuote *bool) uint64 {
+	var m [8]byte
+	for i, q := range Bytes(quotes) {
+		if q&0x80 != 0 {
+			*inQuote = !*inQuote
+		}
+		m[i] = flag(*inQuote)
+	}
+	return Lane(m)
+}
@@ -0,0 +1,52 @@
+package ref
+
+import (
+	"bytes"
+	"iter"
+)
+
+// CountLines counts the '\n' bytes of b, plus one if b ends without a newline
+func CountLines(b []byte) int {
+	n := bytes.Count(b, []byte("\n"))
+	if len(b) > 0 && b[len(b)-1] != '\n' {
+		n++
+	}
+	return n
+}
+
+// Lines yields each line of b without its '\n'; a trailing newline adds no empty line
+func Lines(b []byte) iter.Seq[[]byte] {
+	return lines(b, false)
+}
+
+// LinesCRLF yields each line of b without its '\n' or the '\r' before it
+func LinesCRLF(b []byte) iter.Seq[[]byte] {
+	return lines(b, true)
+}
+
+// lines yields the lines of b, trimming a '\r' before each '\n' if trimCR is set
+func lines(b []byte, trimCR bool) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		for len(b) > 0 {
+			line, rest, found := bytes.Cut(b, []byte("\n"))
+			if trimCR && found {
+				line = bytes.TrimSuffix(line, []byte("\r"))
+			}
+			if !yield(line) {
+				return
+			}
+			b = rest
+		}
+	}
+}
+
+// NormalizeNewlines copies src to dst replacing each "\r\n" with "\n"
+func NormalizeNewlines(dst, src []byte) int {
+	return copy(dst, bytes.ReplaceAll(src, []byte("\r\n"), []byte("\n")))
+}
+
+// NormalizeAllNewlines copies src to dst replacing each "\r\n" and each other '\r' with "\n"
+func NormalizeAllNewlines(dst, src []byte) int {
+	out := bytes.ReplaceAll(src, []byte("\r\n"), []byte("\n"))
+	return copy(dst, bytes.ReplaceAll(out, []byte("\r"), []byte("\n")))
+}
@@ -0,0 +1,22 @@
+package ref
+
+// RangeMatcher flags bytes that fall inside any of a set of inclusive ranges
+type RangeMatcher struct {
+	members *[256]bool
+}
+
+// NewRangeMatcher creates a matcher for the given inclusive [lo, hi] ranges; lo > hi is empty
+func NewRangeMatcher(ranges ...[2]byte) RangeMatcher {
+	m := RangeMatcher{new([256]bool)}
+	for _, r := range ranges {
+		for c := int(r[0]); c <= int(r[1]); c++ {
+			m.members[c] = true
+		}
+	}
+	return m
+}
+
+// Mask sets 0x80 in each byte of v inside any of the ranges
+func (m RangeMatcher) Mask(v uint64) uint64 {
+	return map1(v, func(x byte) byte { return flag(m.members[x]) })
+}
@@ -0,0 +1,33 @@
+package ref
+
+// TableCompiler looks bytes up in a 256-entry table
+type TableCompiler struct {
+	table *[256]byte
+}
+
+// NewTableCompiler wraps table, which must not be modified while the compiler is in use
+func NewTableCompiler(table *[256]byte) *TableCompiler {
+	return &TableCompiler{table}
+}
+
+// Decomposable reports whether table[c] == table[c&0x0F] ^ table[c&0xF0] ^ table[0] for every c
+func (t *TableCompiler) Decomposable() bool {
+	for c := range 256 {
+		if t.table[c] != t.table[c&0x0F]^t.table[c&0xF0]^t.table[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// Lookup replaces each byte c of v with table[c]
+func (t *TableCompiler) Lookup(v uint64) uint64 {
+	return LookupBytes(v, t.table)
+}
+
+// TranslateBytes sets dst[i] to table[src[i]] for each byte of src
+func TranslateBytes(dst, src []byte, table *[256]byte) {
+	for i, c := range src {
+		dst[i] = table[c]
+	}
+}
@@ -0,0 +1,44 @@
+package ref
+
+import "math"
+
+// SumAbsoluteDifferences returns the sum of |a[i]-b[i]| over the common length of a and b
+func SumAbsoluteDifferences(a, b []byte) uint64 {
+	var total uint64
+	for i := range min(len(a), len(b)) {
+		total += uint64(max(a[i], b[i]) - min(a[i], b[i]))
+	}
+	return total
+}
+
+// MeanSquaredError returns the mean of (a[i]-b[i])^2 over the common length of a and b, or 0 if it is empty
+func MeanSquaredError(a, b []byte) float64 {
+	n := min(len(a), len(b))
+	if n == 0 {
+		return 0
+	}
+	var total uint64
+	for i := range n {
+		d := uint64(max(a[i], b[i]) - min(a[i], b[i]))
+		total += d * d
+	}
+	return float64(total) / float64(n)
+}
+
+// PSNR returns 10*log10(255²/MeanSquaredError(a, b)), or +Inf when the error is 0
+func PSNR(a, b []byte) float64 {
+	mse := MeanSquaredError(a, b)
+	if mse == 0 {
+		return math.Inf(1)
+	}
+	return 10 * math.Log10(255*255/mse)
+}
+
+// DotProductBytes returns the sum of a[i]*b[i] over the common length of a and b
+func DotProductBytes(a, b []byte) uint64 {
+	var total uint64
+	for i := range min(len(a), len(b)) {
+		total += uint64(a[i]) * uint64(b[i])
+	}
+	return total
+}
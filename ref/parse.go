@@ -0,0 +1,117 @@
+package ref
+
+import "bytes"
+
+// ParseEightDigits returns the decimal value of 8 digit bytes, byte 0 most significant
+func ParseEightDigits(lane uint64) (uint32, bool) {
+	var n uint32
+	for _, c := range Bytes(lane) {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = 10*n + uint32(c-'0')
+	}
+	return n, true
+}
+
+// ParseUint returns the decimal value of b, or false if b is empty, holds a non-digit or overflows
+func ParseUint(b []byte) (uint64, bool) {
+	if len(b) == 0 {
+		return 0, false
+	}
+	var n uint64
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		d := uint64(c - '0')
+		if n > (1<<64-1-d)/10 {
+			return 0, false
+		}
+		n = 10*n + d
+	}
+	return n, true
+}
+
+// ParseIPv4 splits b at its three dots into octets of 1-3 digits, 0-255 without leading zeros
+func ParseIPv4(b []byte) ([4]byte, bool) {
+	var ip [4]byte
+	fields := bytes.Split(b, []byte("."))
+	if len(fields) != len(ip) {
+		return ip, false
+	}
+	for i, f := range fields {
+		n, ok := ParseUint(f)
+		if !ok || len(f) > 3 || n > 255 || len(f) > 1 && f[0] == '0' {
+			return [4]byte{}, false
+		}
+		ip[i] = byte(n)
+	}
+	return ip, true
+}
+
+// ParseDateYYYYMMDD parses "YYYY-MM-DD", checking the month and the day within that month
+func ParseDateYYYYMMDD(b []byte) (year, month, day int, ok bool) {
+	if len(b) != 10 || b[4] != '-' || b[7] != '-' {
+		return 0, 0, 0, false
+	}
+	y, ok1 := ParseUint(b[:4])
+	m, ok2 := ParseUint(b[5:7])
+	d, ok3 := ParseUint(b[8:])
+	year, month, day = int(y), int(m), int(d)
+	if !ok1 || !ok2 || !ok3 || month < 1 || month > 12 || day < 1 || day > daysInMonth(year, month) {
+		return 0, 0, 0, false
+	}
+	return year, month, day, true
+}
+
+// daysInMonth returns the length of month in the proleptic Gregorian year
+func daysInMonth(year, month int) int {
+	days := [12]int{31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}[month-1]
+	if month == 2 && year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+		days++
+	}
+	return days
+}
+
+// ParseTimeHHMMSS parses "HH:MM:SS" with hour 0-23, minute 0-59 and second 0-60
+func ParseTimeHHMMSS(b []byte) (hour, minute, second int, ok bool) {
+	if len(b) != 8 || b[2] != ':' || b[5] != ':' {
+		return 0, 0, 0, false
+	}
+	h, ok1 := ParseUint(b[:2])
+	m, ok2 := ParseUint(b[3:5])
+	s, ok3 := ParseUint(b[6:])
+	if !ok1 || !ok2 || !ok3 || h > 23 || m > 59 || s > 60 {
+		return 0, 0, 0, false
+	}
+	return int(h), int(m), int(s), true
+}
+
+// ValidateUUID reports whether ParseUUID accepts b
+func ValidateUUID(b []byte) bool {
+	_, ok := ParseUUID(b)
+	return ok
+}
+
+// ParseUUID decodes 36 bytes of hex digit pairs with hyphens at offsets 8, 13, 18 and 23
+func ParseUUID(b []byte) ([16]byte, bool) {
+	var out [16]byte
+	if len(b) != 36 {
+		return out, false
+	}
+	digits := make([]byte, 0, 32)
+	for i, c := range b {
+		if i == 8 || i == 13 || i == 18 || i == 23 {
+			if c != '-' {
+				return out, false
+			}
+			continue
+		}
+		digits = append(digits, c)
+	}
+	if n, err := DecodeHex(out[:], digits); n != len(out) || err != nil {
+		return [16]byte{}, false
+	}
+	return out, true
+}
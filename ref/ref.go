@@ -0,0 +1,529 @@
+// Package ref holds plain byte-at-a-time versions of the swar lane primitives
+//
+// Every function has the same name and signature as its swar counterpart and treats
+// byte i of a lane as the byte at offset i in memory, like a little-endian load. The
+// code favours obviousness over speed so it can serve as the oracle in differential
+// tests and fuzzers for kernels composed from the swar primitives. Inputs outside a
+// function's documented domain, such as BCD nibbles above 9, have no defined result.
+//
+// Mirrors that take a per-byte predicate, such as Compress and CountMatches, call it
+// on one byte at a time, so the predicate must treat the bytes of a lane independently.
+// The hashes, random generator, filters and chunkers whose output is defined only by
+// their implementation have no mirror, nor do the lane conversion and debug helpers.
+package ref
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"slices"
+)
+
+// Bytes splits a lane into its bytes in memory order
+func Bytes(v uint64) [8]byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	return b
+}
+
+// Lane joins bytes in memory order into a lane
+func Lane(b [8]byte) uint64 {
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+// map1 applies f to every byte of v
+func map1(v uint64, f func(x byte) byte) uint64 {
+	b := Bytes(v)
+	for i := range b {
+		b[i] = f(b[i])
+	}
+	return Lane(b)
+}
+
+// map2 applies f to every pair of corresponding bytes of a and b
+func map2(a, b uint64, f func(x, y byte) byte) uint64 {
+	ab, bb := Bytes(a), Bytes(b)
+	for i := range ab {
+		ab[i] = f(ab[i], bb[i])
+	}
+	return Lane(ab)
+}
+
+// flag returns 0x80 when ok is true and 0 otherwise
+func flag(ok bool) byte {
+	if ok {
+		return 0x80
+	}
+	return 0
+}
+
+// Dupe duplicates c into every byte
+func Dupe(c byte) uint64 {
+	return Lane([8]byte{c, c, c, c, c, c, c, c})
+}
+
+// Dupe2 repeats the two bytes of p, low byte first, across the lane
+func Dupe2(p uint16) uint64 {
+	return DupePattern([]byte{byte(p), byte(p >> 8)})
+}
+
+// Dupe4 repeats the four bytes of p, low byte first, across the lane
+func Dupe4(p uint32) uint64 {
+	return DupePattern(binary.LittleEndian.AppendUint32(nil, p))
+}
+
+// DupePattern sets byte i to p[i%len(p)], or returns 0 for an empty pattern
+func DupePattern(p []byte) uint64 {
+	return DupePatternAt(p, 0)
+}
+
+// DupePatternAt sets byte i to p[(offset+i)%len(p)], or returns 0 for an empty pattern
+func DupePatternAt(p []byte, offset int) uint64 {
+	var b [8]byte
+	for i := range b {
+		if len(p) > 0 {
+			b[i] = p[(offset+i)%len(p)]
+		}
+	}
+	return Lane(b)
+}
+
+// HighBitWhereLess sets 0x80 in each byte where v < cm and clears it elsewhere
+func HighBitWhereLess(v, cm uint64) uint64 {
+	return map2(v, cm, func(x, y byte) byte { return flag(x < y) })
+}
+
+// HighBitWhereGreater sets 0x80 in each byte where v > cm and clears it elsewhere
+func HighBitWhereGreater(v, cm uint64) uint64 {
+	return map2(v, cm, func(x, y byte) byte { return flag(x > y) })
+}
+
+// HighBitWhereEqual sets 0x80 in each byte where v == cm and clears it elsewhere
+func HighBitWhereEqual(v, cm uint64) uint64 {
+	return map2(v, cm, func(x, y byte) byte { return flag(x == y) })
+}
+
+// SubtractBytesWithWrapping returns a-b per byte modulo 256
+func SubtractBytesWithWrapping(a, b uint64) uint64 {
+	return map2(a, b, func(x, y byte) byte { return x - y })
+}
+
+// SubtractBytesWithMinimum returns a-b per byte, or 0 where b > a
+func SubtractBytesWithMinimum(a, b uint64) uint64 {
+	return map2(a, b, func(x, y byte) byte { return x - min(x, y) })
+}
+
+// SubtractBytesWithBorrowOut returns a-b per byte modulo 256 and 0x80 where b > a
+func SubtractBytesWithBorrowOut(a, b uint64) (diff, borrows uint64) {
+	return SubtractBytesWithWrapping(a, b), HighBitWhereLess(a, b)
+}
+
+// AddBytesWithWrapping returns a+b per byte modulo 256
+func AddBytesWithWrapping(a, b uint64) uint64 {
+	return map2(a, b, func(x, y byte) byte { return x + y })
+}
+
+// AddBytesWithMaximum returns a+b per byte, or 255 where the sum overflows
+func AddBytesWithMaximum(a, b uint64) uint64 {
+	return map2(a, b, func(x, y byte) byte { return byte(min(int(x)+int(y), 255)) })
+}
+
+// AddBytesWithCarryOut returns a+b per byte modulo 256 and 0x80 where the sum overflows
+func AddBytesWithCarryOut(a, b uint64) (sum, carries uint64) {
+	return AddBytesWithWrapping(a, b), map2(a, b, func(x, y byte) byte { return flag(int(x)+int(y) > 255) })
+}
+
+// AbsoluteDifferenceBetweenBytes returns |a-b| per byte
+func AbsoluteDifferenceBetweenBytes(a, b uint64) uint64 {
+	return map2(a, b, func(x, y byte) byte { return max(x, y) - min(x, y) })
+}
+
+// SelectSmallerBytes returns min(a, b) per byte
+func SelectSmallerBytes(a, b uint64) uint64 {
+	return map2(a, b, func(x, y byte) byte { return min(x, y) })
+}
+
+// SelectLargerBytes returns max(a, b) per byte
+func SelectLargerBytes(a, b uint64) uint64 {
+	return map2(a, b, func(x, y byte) byte { return max(x, y) })
+}
+
+// AverageBytes returns (a+b)/2 per byte, rounded down
+func AverageBytes(a, b uint64) uint64 {
+	return map2(a, b, func(x, y byte) byte { return byte((int(x) + int(y)) / 2) })
+}
+
+// SwapByteHalves swaps the two nibbles of each byte
+func SwapByteHalves(v uint64) uint64 {
+	return map1(v, func(x byte) byte { return x<<4 | x>>4 })
+}
+
+// ReverseEachByte reverses the bit order of each byte
+func ReverseEachByte(v uint64) uint64 {
+	return map1(v, bits.Reverse8)
+}
+
+// SelectByLowBit takes each byte from a where the mask byte is 1 and from b where it is 0
+func SelectByLowBit(a, b, mask uint64) uint64 {
+	return selectBytes(a, b, mask, 0x01)
+}
+
+// SelectByHighBit takes each byte from a where the mask byte has 0x80 set and from b elsewhere
+func SelectByHighBit(a, b, mask uint64) uint64 {
+	return selectBytes(a, b, mask, 0x80)
+}
+
+// selectBytes takes each byte from a where the mask byte has bit set and from b elsewhere
+func selectBytes(a, b, mask uint64, bit byte) uint64 {
+	ab, bb, mb := Bytes(a), Bytes(b), Bytes(mask)
+	for i := range ab {
+		if mb[i]&bit == 0 {
+			ab[i] = bb[i]
+		}
+	}
+	return Lane(ab)
+}
+
+// CountOnesPerByte replaces each byte with its population count
+func CountOnesPerByte(v uint64) uint64 {
+	return map1(v, func(x byte) byte { return byte(bits.OnesCount8(x)) })
+}
+
+// BinaryToBCDBytes converts each byte 0-99 to two packed decimal digits
+func BinaryToBCDBytes(v uint64) uint64 {
+	return map1(v, func(x byte) byte { return x/10<<4 | x%10 })
+}
+
+// BCDToBinaryBytes converts each byte of two packed decimal digits to 0-99
+func BCDToBinaryBytes(v uint64) uint64 {
+	return map1(v, func(x byte) byte { return x>>4*10 + x&0x0F })
+}
+
+// AddBCDBytes adds packed decimal bytes modulo 100 and returns 0x80 where a byte overflowed
+func AddBCDBytes(a, b uint64) (sum, carries uint64) {
+	ab, bb := Bytes(BCDToBinaryBytes(a)), Bytes(BCDToBinaryBytes(b))
+	var s, c [8]byte
+	for i := range ab {
+		total := int(ab[i]) + int(bb[i])
+		s[i], c[i] = byte(total%100), flag(total >= 100)
+	}
+	return BinaryToBCDBytes(Lane(s)), Lane(c)
+}
+
+// PrefixSumBytesWrapping replaces byte i with the sum of bytes 0 through i modulo 256
+func PrefixSumBytesWrapping(v uint64) uint64 {
+	b := Bytes(v)
+	for i := 1; i < len(b); i++ {
+		b[i] += b[i-1]
+	}
+	return Lane(b)
+}
+
+// PrefixSumBytesSaturating replaces byte i with the sum of bytes 0 through i capped at 255
+func PrefixSumBytesSaturating(v uint64) uint64 {
+	b := Bytes(v)
+	total := 0
+	for i, x := range b {
+		total += int(x)
+		b[i] = byte(min(total, 255))
+	}
+	return Lane(b)
+}
+
+// ZigZagEncodeBytes maps each byte as an int8 s to 2s for s >= 0 and -2s-1 otherwise
+func ZigZagEncodeBytes(v uint64) uint64 {
+	return map1(v, func(x byte) byte {
+		if s := int(int8(x)); s < 0 {
+			return byte(-2*s - 1)
+		}
+		return x << 1
+	})
+}
+
+// ZigZagDecodeBytes inverts ZigZagEncodeBytes in each byte
+func ZigZagDecodeBytes(v uint64) uint64 {
+	return map1(v, func(x byte) byte {
+		if x&1 == 1 {
+			return byte(-int(x>>1) - 1)
+		}
+		return x >> 1
+	})
+}
+
+// SumBytes returns the sum of the eight bytes
+func SumBytes(v uint64) uint64 {
+	var total uint64
+	for _, x := range Bytes(v) {
+		total += uint64(x)
+	}
+	return total
+}
+
+// MultiplyBytesWidening returns the 16-bit products of bytes 0,2,4,6 in even and 1,3,5,7 in odd
+func MultiplyBytesWidening(a, b uint64) (even, odd uint64) {
+	ab, bb := Bytes(a), Bytes(b)
+	for i := range 4 {
+		even |= uint64(ab[2*i]) * uint64(bb[2*i]) << (16 * i)
+		odd |= uint64(ab[2*i+1]) * uint64(bb[2*i+1]) << (16 * i)
+	}
+	return even, odd
+}
+
+// ScaleBytes returns round(x*scale/255) per byte
+func ScaleBytes(v uint64, scale uint8) uint64 {
+	return map1(v, func(x byte) byte { return divide255(int(x) * int(scale)) })
+}
+
+// QuantizeLane returns round(x*(levels-1)/255) per byte when round is set, and x*levels/256 otherwise
+func QuantizeLane(v uint64, levels int, round bool) uint64 {
+	return map1(v, func(x byte) byte {
+		if round {
+			return divide255(int(x) * (levels - 1))
+		}
+		return byte(int(x) * levels / 256)
+	})
+}
+
+// MultiplyBytesNormalized returns round(a*b/255) per byte
+func MultiplyBytesNormalized(a, b uint64) uint64 {
+	return map2(a, b, func(x, y byte) byte { return divide255(int(x) * int(y)) })
+}
+
+// divide255 returns p/255 rounded to nearest; 255 is odd so there are no ties
+func divide255(p int) byte {
+	return byte((2*p + 255) / 510)
+}
+
+// MaskedAdd returns a+b modulo 256 in bytes where mask has 0x80 set, and a elsewhere
+func MaskedAdd(a, b, mask uint64) uint64 {
+	return SelectByHighBit(AddBytesWithWrapping(a, b), a, mask)
+}
+
+// MaskedSubtract returns a-b modulo 256 in bytes where mask has 0x80 set, and a elsewhere
+func MaskedSubtract(a, b, mask uint64) uint64 {
+	return SelectByHighBit(SubtractBytesWithWrapping(a, b), a, mask)
+}
+
+// MaskedAverage returns (a+b)/2 in bytes where mask has 0x80 set, and a elsewhere
+func MaskedAverage(a, b, mask uint64) uint64 {
+	return SelectByHighBit(AverageBytes(a, b), a, mask)
+}
+
+// NegateBytes returns -x modulo 256 per byte
+func NegateBytes(v uint64) uint64 {
+	return map1(v, func(x byte) byte { return -x })
+}
+
+// NegateBytesWhere returns -x modulo 256 in bytes where mask has 0x80 set, and x elsewhere
+func NegateBytesWhere(v, mask uint64) uint64 {
+	return SelectByHighBit(NegateBytes(v), v, mask)
+}
+
+// MaxAccumulations16 is how many lanes AccumulateBytesInto16 can add before a flush
+const MaxAccumulations16 = 257
+
+// AccumulateBytesInto16 adds bytes 0,2,4,6 of v to the 16-bit slots of acc0 and bytes 1,3,5,7 to acc1
+func AccumulateBytesInto16(acc0, acc1 *uint64, v uint64) {
+	b := Bytes(v)
+	for i := range 4 {
+		*acc0 = setSlot16(*acc0, i, slot16(*acc0, i)+uint16(b[2*i]))
+		*acc1 = setSlot16(*acc1, i, slot16(*acc1, i)+uint16(b[2*i+1]))
+	}
+}
+
+// FlushAccumulators16 adds slot i of acc0 to totals[2i] and of acc1 to totals[2i+1], then clears both
+func FlushAccumulators16(acc0, acc1 *uint64, totals *[8]uint32) {
+	for i := range 4 {
+		totals[2*i] += uint32(slot16(*acc0, i))
+		totals[2*i+1] += uint32(slot16(*acc1, i))
+	}
+	*acc0, *acc1 = 0, 0
+}
+
+// slot16 returns 16-bit slot i of v
+func slot16(v uint64, i int) uint16 {
+	return uint16(v >> (16 * i))
+}
+
+// setSlot16 returns v with 16-bit slot i replaced by x
+func setSlot16(v uint64, i int, x uint16) uint64 {
+	return v&^(0xFFFF<<(16*i)) | uint64(x)<<(16*i)
+}
+
+// SortBytesInLane returns the bytes of v in ascending order from byte 0 to byte 7
+func SortBytesInLane(v uint64) uint64 {
+	b := Bytes(v)
+	slices.Sort(b[:])
+	return Lane(b)
+}
+
+// MedianOfThreeBytes returns the middle of a, b and c per byte
+func MedianOfThreeBytes(a, b, c uint64) uint64 {
+	ab, bb, cb := Bytes(a), Bytes(b), Bytes(c)
+	for i := range ab {
+		three := []byte{ab[i], bb[i], cb[i]}
+		slices.Sort(three)
+		ab[i] = three[1]
+	}
+	return Lane(ab)
+}
+
+// SelectSecondLargestBytes returns the second largest of a, b and c per byte
+func SelectSecondLargestBytes(a, b, c uint64) uint64 {
+	return MedianOfThreeBytes(a, b, c)
+}
+
+// ExtractLowBits sets bit i of the result to the low bit of byte i; other bits of v must be clear
+func ExtractLowBits(v uint64) byte {
+	return ExtractLowBitsLSB0(v)
+}
+
+// ExtractLowBitsLSB0 sets bit i of the result to the low bit of byte i
+func ExtractLowBitsLSB0(v uint64) byte {
+	var m byte
+	for i, x := range Bytes(v) {
+		m |= x & 1 << i
+	}
+	return m
+}
+
+// ExtractLowBitsMSB0 sets bit 7-i of the result to the low bit of byte i
+func ExtractLowBitsMSB0(v uint64) byte {
+	return bits.Reverse8(ExtractLowBitsLSB0(v))
+}
+
+// ExtractHighBits sets bit i of the result to the high bit of byte i
+func ExtractHighBits(v uint64) byte {
+	return ExtractHighBitsLSB0(v)
+}
+
+// ExtractHighBitsLSB0 sets bit i of the result to the high bit of byte i
+func ExtractHighBitsLSB0(v uint64) byte {
+	var m byte
+	for i, x := range Bytes(v) {
+		m |= x >> 7 << i
+	}
+	return m
+}
+
+// ExtractHighBitsMSB0 sets bit 7-i of the result to the high bit of byte i
+func ExtractHighBitsMSB0(v uint64) byte {
+	return bits.Reverse8(ExtractHighBitsLSB0(v))
+}
+
+// ExtractLowBits4 sets bit 8k+i of the result to the low bit of byte i of v[k]
+func ExtractLowBits4(v [4]uint64) uint32 {
+	var m uint32
+	for k, lane := range v {
+		m |= uint32(ExtractLowBitsLSB0(lane)) << (8 * k)
+	}
+	return m
+}
+
+// ExtractLowBits8 sets bit 8k+i of the result to the low bit of byte i of v[k]
+func ExtractLowBits8(v [8]uint64) uint64 {
+	var m uint64
+	for k, lane := range v {
+		m |= uint64(ExtractLowBitsLSB0(lane)) << (8 * k)
+	}
+	return m
+}
+
+// AppendOnesPositions appends base plus the position of each set bit of mask to dst, lowest first
+func AppendOnesPositions(dst []int, mask byte, base int) []int {
+	for i := range 8 {
+		if mask>>i&1 != 0 {
+			dst = append(dst, base+i)
+		}
+	}
+	return dst
+}
+
+// SpreadBitsToLanes sets byte i to bit i of m
+func SpreadBitsToLanes(m byte) uint64 {
+	var b [8]byte
+	for i := range b {
+		b[i] = m >> i & 1
+	}
+	return Lane(b)
+}
+
+// SpreadBitsToHighBits sets byte i to 0x80 where bit i of m is set
+func SpreadBitsToHighBits(m byte) uint64 {
+	var b [8]byte
+	for i := range b {
+		b[i] = flag(m>>i&1 == 1)
+	}
+	return Lane(b)
+}
+
+// PrefixXorBits sets bit i to the XOR of bits 0 through i of v
+func PrefixXorBits(v uint64) uint64 {
+	var out, acc uint64
+	for i := range 64 {
+		acc ^= v >> i & 1
+		out |= acc << i
+	}
+	return out
+}
+
+// RotateLane returns the lane whose byte i is byte (i+n) mod 8 of v
+func RotateLane(v uint64, n int) uint64 {
+	b := Bytes(v)
+	var out [8]byte
+	for i := range out {
+		out[i] = b[((i+n)%8+8)%8]
+	}
+	return Lane(out)
+}
+
+// InterleaveBytes zips a and b as a0 b0 a1 b1 ..., bytes 0-3 of each into lo and 4-7 into hi
+func InterleaveBytes(a, b uint64) (lo, hi uint64) {
+	ab, bb := Bytes(a), Bytes(b)
+	var zipped [16]byte
+	for i := range ab {
+		zipped[2*i], zipped[2*i+1] = ab[i], bb[i]
+	}
+	return Lane([8]byte(zipped[:8])), Lane([8]byte(zipped[8:]))
+}
+
+// DeinterleaveBytes splits lo then hi into their even-offset bytes a and odd-offset bytes b
+func DeinterleaveBytes(lo, hi uint64) (a, b uint64) {
+	lb, hb := Bytes(lo), Bytes(hi)
+	zipped := append(lb[:], hb[:]...)
+	var ab, bb [8]byte
+	for i := range ab {
+		ab[i], bb[i] = zipped[2*i], zipped[2*i+1]
+	}
+	return Lane(ab), Lane(bb)
+}
+
+// LookupBytes replaces each byte c of v with table[c]
+func LookupBytes(v uint64, table *[256]byte) uint64 {
+	return map1(v, func(x byte) byte { return table[x] })
+}
+
+// TransposeBytes8x8 swaps byte c of rows[r] with byte r of rows[c]
+func TransposeBytes8x8(rows *[8]uint64) {
+	var m [8][8]byte
+	for r, v := range rows {
+		m[r] = Bytes(v)
+	}
+	for r := range rows {
+		var col [8]byte
+		for c := range col {
+			col[c] = m[c][r]
+		}
+		rows[r] = Lane(col)
+	}
+}
+
+// MixBytes applies MixBytes's per-byte xor-shift-multiply rounds to each byte of v
+func MixBytes(v uint64) uint64 {
+	return map1(v, func(x byte) byte {
+		x ^= x >> 4
+		x *= 0x9D
+		x ^= x >> 3
+		x *= 0x5B
+		return x ^ x>>4
+	})
+}
@@ -0,0 +1,68 @@
+package ref
+
+import "testing"
+
+// TestBytes pins the memory-order convention every reference relies on: byte 0 of a lane
+// is the least significant byte, as a little-endian load of a []byte produces.
+func TestBytes(t *testing.T) {
+	v := uint64(0x0807_0605_0403_0201)
+	want := [8]byte{1, 2, 3, 4, 5, 6, 7, 8}
+	if got := Bytes(v); got != want {
+		t.Errorf("Bytes(0x%016x) = %v; want %v", v, got, want)
+	}
+	if got := Lane(want); got != v {
+		t.Errorf("Lane(%v) = 0x%016x; want 0x%016x", want, got, v)
+	}
+}
+
+// TestReferences spot-checks the references on hand-worked lanes, so a mistake in the
+// oracle cannot hide behind an identical mistake in the SWAR code it is compared with.
+func TestReferences(t *testing.T) {
+	run := func(name string, got, want uint64) {
+		if got != want {
+			t.Errorf("%s = 0x%016x; want 0x%016x", name, got, want)
+		}
+	}
+
+	a := Lane([8]byte{0, 1, 100, 200, 255, 128, 7, 99})
+	b := Lane([8]byte{0, 2, 50, 100, 1, 128, 9, 1})
+	run("HighBitWhereLess", HighBitWhereLess(a, b), Lane([8]byte{0, 0x80, 0, 0, 0, 0, 0x80, 0}))
+	run("AddBytesWithMaximum", AddBytesWithMaximum(a, b), Lane([8]byte{0, 3, 150, 255, 255, 255, 16, 100}))
+	run("SubtractBytesWithWrapping", SubtractBytesWithWrapping(a, b), Lane([8]byte{0, 255, 50, 100, 254, 0, 254, 98}))
+	run("AverageBytes", AverageBytes(a, b), Lane([8]byte{0, 1, 75, 150, 128, 128, 8, 50}))
+	run("MultiplyBytesNormalized", MultiplyBytesNormalized(a, Dupe(128)), Lane([8]byte{0, 1, 50, 100, 128, 64, 4, 50}))
+	run("ZigZagEncodeBytes", ZigZagEncodeBytes(Lane([8]byte{0, 0xFF, 1, 0xFE, 2, 0x80, 0x7F})), Lane([8]byte{0, 1, 2, 3, 4, 255, 254}))
+	run("PrefixSumBytesSaturating", PrefixSumBytesSaturating(Dupe(40)), Lane([8]byte{40, 80, 120, 160, 200, 240, 255, 255}))
+	run("SortBytesInLane", SortBytesInLane(a), Lane([8]byte{0, 1, 7, 99, 100, 128, 200, 255}))
+	run("RotateLane", RotateLane(a, 1), Lane([8]byte{1, 100, 200, 255, 128, 7, 99, 0}))
+	run("ExtractHighBits", uint64(ExtractHighBits(a)), 0b0011_1000)
+	run("ExtractHighBitsMSB0", uint64(ExtractHighBitsMSB0(a)), 0b0001_1100)
+	escaped, inQuote := true, false
+	lane := Lane([8]byte{'"', '\\', '\\', '\\', '"', 'x', '"', '\\'})
+	run("EscapedCharMask", EscapedCharMask(lane, &escaped), Lane([8]byte{0x80, 0, 0x80, 0, 0x80}))
+	run("QuoteRegionMask", QuoteRegionMask(Lane([8]byte{0x80, 0, 0, 0x80, 0, 0x80}), &inQuote), Lane([8]byte{0x80, 0x80, 0x80, 0, 0, 0x80, 0x80, 0x80}))
+	if !escaped || !inQuote {
+		t.Errorf("carries = %v, %v; want true, true", escaped, inQuote)
+	}
+	if n, ok := ParseEightDigits(Lane([8]byte{'1', '2', '3', '4', '5', '6', '7', '8'})); n != 12345678 || !ok {
+		t.Errorf("ParseEightDigits(\"12345678\") = %d, %v; want 12345678, true", n, ok)
+	}
+	lo, hi := InterleaveBytes(a, b)
+	run("InterleaveBytes lo", lo, Lane([8]byte{0, 0, 1, 2, 100, 50, 200, 100}))
+	run("InterleaveBytes hi", hi, Lane([8]byte{255, 1, 128, 128, 7, 9, 99, 1}))
+}
+
+// TestTransposeBytes8x8 numbers each cell by its position so every swapped byte is visible.
+func TestTransposeBytes8x8(t *testing.T) {
+	var rows, want [8]uint64
+	for r := range rows {
+		var row, col [8]byte
+		for c := range row {
+			row[c], col[c] = byte(8*r+c), byte(8*c+r)
+		}
+		rows[r], want[r] = Lane(row), Lane(col)
+	}
+	if TransposeBytes8x8(&rows); rows != want {
+		t.Errorf("TransposeBytes8x8 = %x; want %x", rows, want)
+	}
+}
@@ -0,0 +1,65 @@
+package ref
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrInvalidRLE reports RLE input with an odd length or a zero run count
+var ErrInvalidRLE = errors.New("swar: invalid RLE data")
+
+// MaxRLEEncodedLen returns 2*n, the size when every byte is its own run
+func MaxRLEEncodedLen(n int) int {
+	return 2 * n
+}
+
+// RLEEncode writes a (count, byte) pair per run of src, splitting runs longer than 255
+// Stops with io.ErrShortBuffer before the first pair that does not fit in dst
+func RLEEncode(dst, src []byte) (int, error) {
+	var out []byte
+	for start, length := range Runs(src) {
+		for ; length > 0; length -= 255 {
+			out = append(out, byte(min(length, 255)), src[start])
+		}
+	}
+	if len(out) > len(dst) {
+		return copy(dst, out[:len(dst)&^1]), io.ErrShortBuffer
+	}
+	return copy(dst, out), nil
+}
+
+// RLEDecode writes count copies of byte for each (count, byte) pair of src to dst
+// Fails with ErrInvalidRLE on odd length or a zero count, and io.ErrShortBuffer before a pair that does not fit
+func RLEDecode(dst, src []byte) (int, error) {
+	if len(src)%2 != 0 {
+		return 0, ErrInvalidRLE
+	}
+	var out []byte
+	for i := 0; i < len(src); i += 2 {
+		if src[i] == 0 {
+			return copy(dst, out), ErrInvalidRLE
+		}
+		if len(out)+int(src[i]) > len(dst) {
+			return copy(dst, out), io.ErrShortBuffer
+		}
+		for range src[i] {
+			out = append(out, src[i+1])
+		}
+	}
+	return copy(dst, out), nil
+}
+
+// RLEDecodedLen returns the sum of the counts of src, or ErrInvalidRLE
+func RLEDecodedLen(src []byte) (int, error) {
+	if len(src)%2 != 0 {
+		return 0, ErrInvalidRLE
+	}
+	n := 0
+	for i := 0; i < len(src); i += 2 {
+		if src[i] == 0 {
+			return 0, ErrInvalidRLE
+		}
+		n += int(src[i])
+	}
+	return n, nil
+}
@@ -0,0 +1,48 @@
+package ref
+
+import "iter"
+
+// Runs yields the start and length of each maximal run of equal bytes in b
+func Runs(b []byte) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		for start := 0; start < len(b); {
+			end := start + 1
+			for end < len(b) && b[end] == b[start] {
+				end++
+			}
+			if !yield(start, end-start) {
+				return
+			}
+			start = end
+		}
+	}
+}
+
+// MatchLen returns the length of the common prefix of a and b, at most max and at least 0
+func MatchLen(a, b []byte, max int) int {
+	n := 0
+	for n < len(a) && n < len(b) && n < max && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// LongestRun returns the start and length of the first run of equal bytes that no other run exceeds
+func LongestRun(b []byte) (start, length int) {
+	for s, n := range Runs(b) {
+		if n > length {
+			start, length = s, n
+		}
+	}
+	return start, length
+}
+
+// IsNonDecreasing reports whether b[i-1] <= b[i] for every i
+func IsNonDecreasing(b []byte) bool {
+	for i := 1; i < len(b); i++ {
+		if b[i-1] > b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package ref
+
+import "iter"
+
+// ScanState carries the escape, quote and previous-byte state of a lane-by-lane scan
+type ScanState struct {
+	// Escaped reports that the next chunk starts with a byte escaped by a backslash
+	Escaped bool
+	// InQuote reports that the next chunk starts inside a quoted region
+	InQuote bool
+	// last is the final byte of the most recent chunk passed to Advance
+	last byte
+}
+
+// Reset returns s to the start-of-input state
+func (s *ScanState) Reset() {
+	*s = ScanState{}
+}
+
+// Chunks yields each zero-padded lane of b with its offset and calls Advance after each
+func (s *ScanState) Chunks(b []byte) iter.Seq2[int, uint64] {
+	return func(yield func(int, uint64) bool) {
+		for i := 0; i < len(b); i += 8 {
+			var lane [8]byte
+			copy(lane[:], b[i:])
+			ok := yield(i, Lane(lane))
+			s.Advance(Lane(lane))
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// Advance records the last byte of v for Previous
+func (s *ScanState) Advance(v uint64) {
+	s.last = Bytes(v)[7]
+}
+
+// Previous returns the lane whose byte i is byte i-1 of v, and byte 0 the last byte before v
+func (s *ScanState) Previous(v uint64) uint64 {
+	b := Bytes(v)
+	return Lane([8]byte{s.last, b[0], b[1], b[2], b[3], b[4], b[5], b[6]})
+}
+
+// PairMask sets 0x80 in each byte of v equal to second whose preceding byte equals first
+func (s *ScanState) PairMask(v uint64, first, second byte) uint64 {
+	return map2(s.Previous(v), v, func(x, y byte) byte { return flag(x == first && y == second) })
+}
+
+// EscapedMask returns EscapedCharMask(v, &s.Escaped)
+func (s *ScanState) EscapedMask(v uint64) uint64 {
+	return EscapedCharMask(v, &s.Escaped)
+}
+
+// QuoteMask returns QuoteRegionMask(quotes, &s.InQuote)
+func (s *ScanState) QuoteMask(quotes uint64) uint64 {
+	return QuoteRegionMask(quotes, &s.InQuote)
+}
@@ -0,0 +1,36 @@
+package ref
+
+// MovingAverage writes (sum(src[i:i+window]) + window/2) / window for each full window and returns the count
+// Returns 0 if window is not positive or exceeds len(src)
+func MovingAverage(dst, src []byte, window int) int {
+	if window <= 0 || window > len(src) {
+		return 0
+	}
+	out := make([]byte, len(src)-window+1)
+	for i := range out {
+		sum := 0
+		for _, c := range src[i : i+window] {
+			sum += int(c)
+		}
+		out[i] = byte((sum + window/2) / window)
+	}
+	return copy(dst, out)
+}
+
+// DownsampleByTwo writes (src[2i]+src[2i+1])/2 rounded down to dst[i]
+func DownsampleByTwo(dst, src []byte) {
+	out := make([]byte, len(src)/2)
+	for i := range out {
+		out[i] = byte((int(src[2*i]) + int(src[2*i+1])) / 2)
+	}
+	copy(dst, out)
+}
+
+// UpsampleByTwo writes src[i] to dst[2i] and dst[2i+1]
+func UpsampleByTwo(dst, src []byte) {
+	out := make([]byte, 0, 2*len(src))
+	for _, c := range src {
+		out = append(out, c, c)
+	}
+	copy(dst, out)
+}
@@ -0,0 +1,28 @@
+package ref
+
+import "slices"
+
+// MedianFilter3 writes the median of src[i-1], src[i] and src[i+1] to dst[i], copying the end bytes
+func MedianFilter3(dst, src []byte) {
+	out := slices.Clone(src)
+	for i := 1; i+1 < len(src); i++ {
+		three := []byte{src[i-1], src[i], src[i+1]}
+		slices.Sort(three)
+		out[i] = three[1]
+	}
+	copy(dst, out)
+}
+
+// Top2Bytes returns the largest and second largest byte at each position across rows and two zero rows
+func Top2Bytes(rows []uint64) (largest, second uint64) {
+	var top, next [8]byte
+	for i := range top {
+		column := []byte{0, 0}
+		for _, row := range rows {
+			column = append(column, Bytes(row)[i])
+		}
+		slices.Sort(column)
+		top[i], next[i] = column[len(column)-1], column[len(column)-2]
+	}
+	return Lane(top), Lane(next)
+}
@@ -0,0 +1,28 @@
+package ref
+
+import (
+	"bytes"
+	"iter"
+)
+
+// SplitIter yields the subslices of b between each delim, including empty ones
+func SplitIter(b []byte, delim byte) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		for _, field := range bytes.Split(b, []byte{delim}) {
+			if !yield(field) {
+				return
+			}
+		}
+	}
+}
+
+// Fields yields the non-empty runs of b between ASCII whitespace bytes
+func Fields(b []byte) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		for _, field := range bytes.FieldsFunc(b, func(r rune) bool { return r < 0x80 && isSpace(byte(r)) }) {
+			if !yield(field) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package ref
+
+// Histogram adds the number of occurrences of each byte value in b to counts
+func Histogram(b []byte, counts *[256]uint64) {
+	for _, c := range b {
+		counts[c]++
+	}
+}
+
+// BlockStat summarises one block for compressibility heuristics
+type BlockStat struct {
+	Distinct     int     // number of different byte values
+	MaxRun       int     // length of the longest run of one repeated byte
+	ZeroFraction float64 // fraction of bytes equal to zero
+}
+
+// BlockStats returns a BlockStat for each blockSize bytes of b, or nil if blockSize is not positive
+func BlockStats(b []byte, blockSize int) []BlockStat {
+	if blockSize <= 0 {
+		return nil
+	}
+	stats := []BlockStat{}
+	for start := 0; start < len(b); start += blockSize {
+		block := b[start:min(start+blockSize, len(b))]
+		var seen [256]bool
+		distinct, zeros := 0, 0
+		for _, c := range block {
+			if !seen[c] {
+				seen[c] = true
+				distinct++
+			}
+			if c == 0 {
+				zeros++
+			}
+		}
+		_, maxRun := LongestRun(block)
+		stats = append(stats, BlockStat{distinct, maxRun, float64(zeros) / float64(len(block))})
+	}
+	return stats
+}
+
+// CountGreater counts the bytes of b above t
+func CountGreater(b []byte, t byte) int {
+	return countBytes(b, func(c byte) bool { return c > t })
+}
+
+// CountLess counts the bytes of b below t
+func CountLess(b []byte, t byte) int {
+	return countBytes(b, func(c byte) bool { return c < t })
+}
+
+// CountBetween counts the bytes of b in lo through hi inclusive
+func CountBetween(b []byte, lo, hi byte) int {
+	return countBytes(b, func(c byte) bool { return lo <= c && c <= hi })
+}
+
+// countBytes counts the bytes of b for which f is true
+func countBytes(b []byte, f func(c byte) bool) int {
+	n := 0
+	for _, c := range b {
+		if f(c) {
+			n++
+		}
+	}
+	return n
+}
+
+// IndexOfMax returns the index of the first largest byte of b, or -1 if b is empty
+func IndexOfMax(b []byte) int {
+	at := -1
+	for i, c := range b {
+		if at < 0 || c > b[at] {
+			at = i
+		}
+	}
+	return at
+}
+
+// IndexOfMin returns the index of the first smallest byte of b, or -1 if b is empty
+func IndexOfMin(b []byte) int {
+	at := -1
+	for i, c := range b {
+		if at < 0 || c < b[at] {
+			at = i
+		}
+	}
+	return at
+}
+
+// ColumnStats tracks the minimum, maximum and sum of each column of fixed-width records
+type ColumnStats struct {
+	width    int
+	rows     int
+	min, max []byte
+	sums     []uint64
+}
+
+// NewColumnStats creates statistics for records of width bytes
+func NewColumnStats(width int) *ColumnStats {
+	return &ColumnStats{width: width, min: make([]byte, width), max: make([]byte, width), sums: make([]uint64, width)}
+}
+
+// Add folds each complete record of records into the statistics and returns the bytes consumed
+func (s *ColumnStats) Add(records []byte) int {
+	n := len(records) / s.width * s.width
+	for i, c := range records[:n] {
+		col := i % s.width
+		if s.rows == 0 || c < s.min[col] {
+			s.min[col] = c
+		}
+		s.max[col] = max(s.max[col], c)
+		s.sums[col] += uint64(c)
+		if col == s.width-1 {
+			s.rows++
+		}
+	}
+	return n
+}
+
+// Rows returns the number of records added
+func (s *ColumnStats) Rows() int {
+	return s.rows
+}
+
+// Min returns the smallest byte of column col, or 0 before any record is added
+func (s *ColumnStats) Min(col int) byte {
+	return s.min[col]
+}
+
+// Max returns the largest byte of column col, or 0 before any record is added
+func (s *ColumnStats) Max(col int) byte {
+	return s.max[col]
+}
+
+// Sum returns the total of column col
+func (s *ColumnStats) Sum(col int) uint64 {
+	return s.sums[col]
+}
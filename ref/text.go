@@ -0,0 +1,107 @@
+package ref
+
+// IsASCII reports whether every byte of b is below 0x80
+func IsASCII(b []byte) bool {
+	return IndexNonASCII(b) < 0
+}
+
+// IndexNonASCII returns the index of the first byte of b at or above 0x80, or -1
+func IndexNonASCII(b []byte) int {
+	for i, c := range b {
+		if c >= 0x80 {
+			return i
+		}
+	}
+	return -1
+}
+
+// CountRunes counts the bytes of b outside 0x80-0xBF, the UTF-8 continuation bytes
+func CountRunes(b []byte) int {
+	n := 0
+	for _, c := range b {
+		if c < 0x80 || c > 0xBF {
+			n++
+		}
+	}
+	return n
+}
+
+// ToUpperASCII writes src to dst with a-z replaced by A-Z
+func ToUpperASCII(dst, src []byte) {
+	mapBytes(dst, src, toUpper)
+}
+
+// ToLowerASCII writes src to dst with A-Z replaced by a-z
+func ToLowerASCII(dst, src []byte) {
+	mapBytes(dst, src, toLower)
+}
+
+// SwapCaseASCII writes src to dst with a-z and A-Z exchanged
+func SwapCaseASCII(dst, src []byte) {
+	mapBytes(dst, src, func(c byte) byte {
+		if isUpper(c) {
+			return toLower(c)
+		}
+		return toUpper(c)
+	})
+}
+
+// ToUpperASCIIInPlace replaces a-z in b with A-Z
+func ToUpperASCIIInPlace(b []byte) {
+	ToUpperASCII(b, b)
+}
+
+// ToLowerASCIIInPlace replaces A-Z in b with a-z
+func ToLowerASCIIInPlace(b []byte) {
+	ToLowerASCII(b, b)
+}
+
+// TitleCaseASCII writes src to dst with each a-z that starts a word upper cased
+// Words are runs of ASCII letters, digits, apostrophes and bytes at or above 0x80
+func TitleCaseASCII(dst, src []byte) {
+	out := make([]byte, len(src))
+	inWord := false
+	for i, c := range src {
+		out[i] = c
+		if !inWord {
+			out[i] = toUpper(c)
+		}
+		inWord = isUpper(c) || isLower(c) || '0' <= c && c <= '9' || c == '\'' || c >= 0x80
+	}
+	copy(dst, out)
+}
+
+// mapBytes writes f(c) to dst for each byte c of src
+func mapBytes(dst, src []byte, f func(c byte) byte) {
+	out := make([]byte, len(src))
+	for i, c := range src {
+		out[i] = f(c)
+	}
+	copy(dst, out)
+}
+
+// isUpper reports whether c is in A-Z
+func isUpper(c byte) bool {
+	return 'A' <= c && c <= 'Z'
+}
+
+// isLower reports whether c is in a-z
+func isLower(c byte) bool {
+	return 'a' <= c && c <= 'z'
+}
+
+// toUpper returns c with a-z mapped to A-Z
+func toUpper(c byte) byte {
+	if isLower(c) {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+// toLower returns c with A-Z mapped to a-z
+func toLower(c byte) byte {
+	if isUpper(c) {
+		return c - 'A' + 'a'
+	}
+	return c
+}
@@ -0,0 +1,36 @@
+package ref
+
+// Rot13 writes src to dst with each ASCII letter moved 13 places through its alphabet
+func Rot13(dst, src []byte) {
+	mapBytes(dst, src, func(c byte) byte {
+		switch {
+		case isUpper(c):
+			return 'A' + (c-'A'+13)%26
+		case isLower(c):
+			return 'a' + (c-'a'+13)%26
+		}
+		return c
+	})
+}
+
+// XORMask writes src[i]^key[i%8] to dst for each byte of src
+func XORMask(dst, src []byte, key [8]byte) {
+	xorKey(dst, src, key[:], 0)
+}
+
+// XORMask4 writes src[i]^key[i%4] to dst for each byte of src
+func XORMask4(dst, src []byte, key [4]byte) {
+	xorKey(dst, src, key[:], 0)
+}
+
+// UnmaskWebSocket XORs payload[i] with key[(offset+i)%4] in place
+func UnmaskWebSocket(payload []byte, key [4]byte, offset int) {
+	xorKey(payload, payload, key[:], offset)
+}
+
+// xorKey writes src[i] XOR key[(offset+i) mod len(key)] to dst
+func xorKey(dst, src, key []byte, offset int) {
+	for i, c := range src {
+		dst[i] = c ^ key[((offset+i)%len(key)+len(key))%len(key)]
+	}
+}
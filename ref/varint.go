@@ -0,0 +1,17 @@
+package ref
+
+import "encoding/binary"
+
+// DecodeUvarint64s decodes varints from src with binary.Uvarint until dst is full, src ends or one is malformed
+func DecodeUvarint64s(dst []uint64, src []byte) (n, read int) {
+	for n < len(dst) && read < len(src) {
+		x, size := binary.Uvarint(src[read:])
+		if size <= 0 {
+			break
+		}
+		dst[n] = x
+		n++
+		read += size
+	}
+	return n, read
+}
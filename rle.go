@@ -0,0 +1,71 @@
+package swar
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrInvalidRLE reports RLE input with an odd length or a zero run count
+var ErrInvalidRLE = errors.New("swar: invalid RLE data")
+
+// MaxRLEEncodedLen returns the largest RLEEncode output for n input bytes
+// Every byte may start its own run, so each costs a count and a value
+func MaxRLEEncodedLen(n int) int {
+	return 2 * n
+}
+
+// RLEEncode writes src to dst as (count, byte) pairs with counts from 1 to 255
+// Returns bytes written, or io.ErrShortBuffer when dst cannot hold the output
+func RLEEncode(dst, src []byte) (int, error) {
+	src = unaliased(dst, src, false)
+	n := 0
+	for start, length := range Runs(src) {
+		c := src[start]
+		for ; length > 0; length -= 255 {
+			if n+2 > len(dst) {
+				return n, io.ErrShortBuffer
+			}
+			dst[n], dst[n+1] = byte(min(length, 255)), c
+			n += 2
+		}
+	}
+	return n, nil
+}
+
+// RLEDecode expands (count, byte) pairs from src into dst with lane-wide fills
+// Returns bytes written plus ErrInvalidRLE or io.ErrShortBuffer
+func RLEDecode(dst, src []byte) (int, error) {
+	if len(src)%2 != 0 {
+		return 0, ErrInvalidRLE
+	}
+	src = unaliased(dst, src, false)
+	n := 0
+	for i := 0; i < len(src); i += 2 {
+		count := int(src[i])
+		if count == 0 {
+			return n, ErrInvalidRLE
+		}
+		if n+count > len(dst) {
+			return n, io.ErrShortBuffer
+		}
+		FillBytes(dst[n:n+count], src[i+1])
+		n += count
+	}
+	return n, nil
+}
+
+// RLEDecodedLen returns the length RLEDecode produces for src, or ErrInvalidRLE
+// Sizes the destination before decoding untrusted input
+func RLEDecodedLen(src []byte) (int, error) {
+	if len(src)%2 != 0 {
+		return 0, ErrInvalidRLE
+	}
+	n := 0
+	for i := 0; i < len(src); i += 2 {
+		if src[i] == 0 {
+			return 0, ErrInvalidRLE
+		}
+		n += int(src[i])
+	}
+	return n, nil
+}
@@ -0,0 +1,66 @@
+package swar
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// TestRLERoundTrip encodes and decodes inputs with short, long and over-255 runs. The
+// encoded form must match a scalar encoder exactly so the format stays stable.
+func TestRLERoundTrip(t *testing.T) {
+	run := func(src []byte) {
+		var want []byte
+		for i := 0; i < len(src); {
+			j := i + 1
+			for j < len(src) && src[j] == src[i] && j-i < 255 {
+				j++
+			}
+			want = append(want, byte(j-i), src[i])
+			i = j
+		}
+		enc := make([]byte, MaxRLEEncodedLen(len(src)))
+		n, err := RLEEncode(enc, src)
+		if err != nil || !bytes.Equal(enc[:n], want) {
+			t.Errorf("RLEEncode(%q) = %v, %v; want %v, nil", src, enc[:n], err, want)
+			return
+		}
+		if size, err := RLEDecodedLen(enc[:n]); size != len(src) || err != nil {
+			t.Errorf("RLEDecodedLen(%v) = %d, %v; want %d, nil", enc[:n], size, err, len(src))
+		}
+		dec := make([]byte, len(src))
+		m, err := RLEDecode(dec, enc[:n])
+		if err != nil || !bytes.Equal(dec[:m], src) {
+			t.Errorf("RLEDecode(%v) = %q, %v; want %q, nil", enc[:n], dec[:m], err, src)
+		}
+	}
+
+	run(nil)
+	run([]byte("a"))
+	run([]byte("aaabccccccccccccccccd"))
+	run([]byte("abcdefghijklmnop"))
+	run(bytes.Repeat([]byte{0}, 1000))
+	run(append(bytes.Repeat([]byte{'x'}, 255), bytes.Repeat([]byte{'y'}, 256)...))
+}
+
+// TestRLEErrors checks that malformed input and short buffers are reported rather than
+// silently truncated, matching the io.ErrShortBuffer convention of the standard library.
+func TestRLEErrors(t *testing.T) {
+	run := func(name string, err, want error) {
+		if !errors.Is(err, want) {
+			t.Errorf("%s error = %v; want %v", name, err, want)
+		}
+	}
+
+	_, err := RLEEncode(make([]byte, 3), []byte("abc"))
+	run("RLEEncode short dst", err, io.ErrShortBuffer)
+	_, err = RLEDecode(make([]byte, 2), []byte{3, 'a'})
+	run("RLEDecode short dst", err, io.ErrShortBuffer)
+	_, err = RLEDecode(make([]byte, 8), []byte{3, 'a', 1})
+	run("RLEDecode odd length", err, ErrInvalidRLE)
+	_, err = RLEDecode(make([]byte, 8), []byte{0, 'a'})
+	run("RLEDecode zero count", err, ErrInvalidRLE)
+	_, err = RLEDecodedLen([]byte{2, 'a', 0, 'b'})
+	run("RLEDecodedLen zero count", err, ErrInvalidRLE)
+}
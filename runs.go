@@ -0,0 +1,81 @@
+package swar
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// Runs yields the start and length of each maximal run of equal bytes in b
+// Boundaries are found 8 at a time by comparing each lane with itself shifted by one byte
+func Runs(b []byte) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		if len(b) == 0 {
+			return
+		}
+		start := 0
+		prev := uint64(b[0])
+		for i := 0; i < len(b); i += 8 {
+			v := loadLane(b, i)
+			m := ^HighBitWhereEqual(v, v<<8|prev) & HighBits
+			if i+8 > len(b) {
+				m &= uint64(1)<<(8*(len(b)-i)) - 1
+			}
+			for ; m != 0; m &= m - 1 {
+				pos := i + bits.TrailingZeros64(m)>>3
+				if pos > start && !yield(start, pos-start) {
+					return
+				}
+				start = pos
+			}
+			prev = v >> 56
+		}
+		yield(start, len(b)-start)
+	}
+}
+
+// MatchLen returns how many leading bytes a and b share, capped at max
+// The first mismatch is the lowest set byte of the XOR of two lanes, as LZ match finders need
+func MatchLen(a, b []byte, max int) int {
+	limit := min(len(a), len(b), max)
+	for i := 0; i < limit; i += 8 {
+		if x := loadLane(a, i) ^ loadLane(b, i); x != 0 {
+			return min(i+bits.TrailingZeros64(x)>>3, limit)
+		}
+	}
+	if limit < 0 {
+		return 0
+	}
+	return limit
+}
+
+// LongestRun returns the start and length of the first longest run of equal bytes
+// A long run relative to len(b) suggests RLE will pay off for this block
+func LongestRun(b []byte) (start, length int) {
+	for s, n := range Runs(b) {
+		if n > length {
+			start, length = s, n
+		}
+	}
+	return start, length
+}
+
+// IsNonDecreasing reports whether every byte of b is at least the byte before it
+// Sorted data is a good fit for delta encoding; each lane is compared with itself shifted
+func IsNonDecreasing(b []byte) bool {
+	if len(b) == 0 {
+		return true
+	}
+	prev := uint64(b[0])
+	for i := 0; i < len(b); i += 8 {
+		v := loadLane(b, i)
+		m := HighBitWhereLess(v, v<<8|prev)
+		if i+8 > len(b) {
+			m &= uint64(1)<<(8*(len(b)-i)) - 1
+		}
+		if m != 0 {
+			return false
+		}
+		prev = v >> 56
+	}
+	return true
+}
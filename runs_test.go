@@ -0,0 +1,126 @@
+package swar
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+// runsReference splits b into maximal runs of equal bytes one byte at a time.
+func runsReference(b []byte) (runs [][2]int) {
+	for i := 0; i < len(b); {
+		j := i + 1
+		for j < len(b) && b[j] == b[i] {
+			j++
+		}
+		runs = append(runs, [2]int{i, j - i})
+		i = j
+	}
+	return runs
+}
+
+// TestRuns compares run boundaries with a scalar scan, including runs that straddle lanes
+// and a leading zero byte that must not be mistaken for a continuation of the carry-in.
+func TestRuns(t *testing.T) {
+	run := func(src string) {
+		var got [][2]int
+		for start, length := range Runs([]byte(src)) {
+			got = append(got, [2]int{start, length})
+		}
+		want := runsReference([]byte(src))
+		if !slices.Equal(got, want) {
+			t.Errorf("Runs(%q) = %v; want %v", src, got, want)
+		}
+	}
+
+	run("")
+	run("a")
+	run("aaaaaaaaaaaaaaaaaaaa")
+	run("\x00\x00abc\x00")
+	run("hello    world    with   spaces")
+	run("abcdefghijklmnopqrstuvwxyz")
+	run("aaaaaaabbbbbbbbbcccccccccccccccccd")
+	for n := 0; n < 200; n++ {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = byte(i * i % 7 / 3)
+		}
+		run(string(b))
+	}
+}
+
+// TestMatchLen checks the common prefix length against a byte loop for every mismatch
+// position and cap, so lane boundaries and limits shorter than a lane are both covered.
+func TestMatchLen(t *testing.T) {
+	run := func(a, b []byte, max int) {
+		want := 0
+		for want < len(a) && want < len(b) && want < max && a[want] == b[want] {
+			want++
+		}
+		if got := MatchLen(a, b, max); got != want {
+			t.Errorf("MatchLen(%q, %q, %d) = %d; want %d", a, b, max, got, want)
+		}
+	}
+
+	run(nil, nil, 10)
+	run([]byte("abc"), []byte("abc"), -1)
+	base := []byte("the quick brown fox jumps over the lazy dog")
+	for i := 0; i <= len(base); i++ {
+		other := bytes.Clone(base)
+		if i < len(other) {
+			other[i] ^= 0x20
+		}
+		for _, max := range []int{0, 3, 8, 17, len(base), 1 << 20} {
+			run(base, other, max)
+			run(base[:i], base, max)
+		}
+	}
+}
+
+// TestLongestRun checks the first longest run against runsReference, so ties keep the
+// earliest run and a run crossing lane boundaries is measured in full.
+func TestLongestRun(t *testing.T) {
+	run := func(src string) {
+		var wantStart, wantLength int
+		for _, r := range runsReference([]byte(src)) {
+			if r[1] > wantLength {
+				wantStart, wantLength = r[0], r[1]
+			}
+		}
+		if start, length := LongestRun([]byte(src)); start != wantStart || length != wantLength {
+			t.Errorf("LongestRun(%q) = %d, %d; want %d, %d", src, start, length, wantStart, wantLength)
+		}
+	}
+
+	run("")
+	run("a")
+	run("aabb")
+	run("abcccccccccccccdddddeeeeeeeeeeeeeeeeeeeeef")
+	run("xxxxxyyyyyzzzzz")
+}
+
+// TestIsNonDecreasing compares with slices.IsSorted for sorted inputs with a single byte
+// lowered at every position, so each lane position and the cross-lane carry are exercised.
+func TestIsNonDecreasing(t *testing.T) {
+	run := func(b []byte) {
+		if got, want := IsNonDecreasing(b), slices.IsSorted(b); got != want {
+			t.Errorf("IsNonDecreasing(%v) = %v; want %v", b, got, want)
+		}
+	}
+
+	run(nil)
+	run([]byte{0})
+	run([]byte{5, 5, 5, 0})
+	sorted := make([]byte, 37)
+	for i := range sorted {
+		sorted[i] = byte(i * 3)
+	}
+	for n := 0; n <= len(sorted); n++ {
+		run(sorted[:n])
+		for i := 0; i < n; i++ {
+			b := bytes.Clone(sorted[:n])
+			b[i] -= 2
+			run(b)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package swar
+
+import "iter"
+
+// ScanState carries the state a lane-by-lane scan needs from one chunk to the next
+// The zero value is ready for the start of input; call each mask method once per chunk, in order
+type ScanState struct {
+	// Escaped reports that the next chunk starts with a byte escaped by a backslash
+	Escaped bool
+	// InQuote reports that the next chunk starts inside a quoted region
+	InQuote bool
+	// last is the most recent chunk passed to Advance, zero before the first
+	last uint64
+}
+
+// Reset returns s to the start-of-input state so it can scan another buffer
+func (s *ScanState) Reset() {
+	*s = ScanState{}
+}
+
+// Chunks yields each zero-padded lane of b with its offset and advances s past it afterwards
+// Threads the carry automatically; breaking out early leaves s positioned after the last chunk seen
+func (s *ScanState) Chunks(b []byte) iter.Seq2[int, uint64] {
+	return func(yield func(int, uint64) bool) {
+		for i := 0; i < len(b); i += 8 {
+			v := loadLane(b, i)
+			ok := yield(i, v)
+			s.Advance(v)
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// Advance records v as the chunk just scanned, so Previous and PairMask see its last byte
+// Chunks calls it for you; call it yourself when feeding lanes by hand
+func (s *ScanState) Advance(v uint64) {
+	s.last = v
+}
+
+// Previous returns the lane of bytes that precede each byte of v in the input
+// Byte 0 is the last byte of the previous chunk, or zero at the start of input
+func (s *ScanState) Previous(v uint64) uint64 {
+	return v<<8 | s.last>>56
+}
+
+// PairMask sets the high bit of each byte of v equal to second that directly follows first
+// Finds two-byte sequences such as "\r\n" even when the pair straddles two chunks
+func (s *ScanState) PairMask(v uint64, first, second byte) uint64 {
+	return HighBitWhereEqual(s.Previous(v), Dupe(first)) & HighBitWhereEqual(v, Dupe(second))
+}
+
+// EscapedMask sets the high bit of each byte of v escaped by a backslash and updates s.Escaped
+// A backslash run of odd length escapes the byte after it, wherever the run began
+func (s *ScanState) EscapedMask(v uint64) uint64 {
+	return EscapedCharMask(v, &s.Escaped)
+}
+
+// QuoteMask sets the high bit of each byte of v inside a quoted region and updates s.InQuote
+// quotes marks the unescaped quote bytes; the opening quote is inside, the closing one is not
+func (s *ScanState) QuoteMask(quotes uint64) uint64 {
+	return QuoteRegionMask(quotes, &s.InQuote)
+}
@@ -0,0 +1,66 @@
+package swar
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+// TestScanStatePairMask finds every "\r\n" with PairMask and compares the positions with
+// a byte loop, including pairs split across chunks and a chunk that starts with '\n'.
+func TestScanStatePairMask(t *testing.T) {
+	run := func(src string) {
+		var want []int
+		for i := 1; i < len(src); i++ {
+			if src[i-1] == '\r' && src[i] == '\n' {
+				want = append(want, i)
+			}
+		}
+		var got []int
+		var s ScanState
+		for i, v := range s.Chunks([]byte(src)) {
+			got = AppendOnesPositions(got, ExtractHighBits(s.PairMask(v, '\r', '\n')), i)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("PairMask(%q, '\\r', '\\n') positions = %v; want %v", src, got, want)
+		}
+	}
+
+	run("")
+	run("\n")
+	run("a\r\nb\r\nc")
+	run("1234567\r\n234567\r\n")
+	run("\r\r\n\n\r\n\r\n\r\n\r\n\r\n\r\n\r\n")
+}
+
+// TestScanStateQuotes threads escape and quote state through ScanState one lane at a time
+// and checks the string regions against ScanJSONStructure, which carries the same state
+// between 64-byte blocks.
+func TestScanStateQuotes(t *testing.T) {
+	run := func(src string) {
+		want := ScanJSONStructure([]byte(src)).Quoted
+		var s ScanState
+		for i, v := range s.Chunks([]byte(src)) {
+			quotes := HighBitWhereEqual(v, Dupe('"')) &^ s.EscapedMask(v)
+			inside := s.QuoteMask(quotes)
+			if i+8 > len(src) {
+				inside &= uint64(1)<<(8*(len(src)-i)) - 1
+			}
+			if got, want := ExtractHighBits(inside), byte(want[i/64]>>(i%64)); got != want {
+				t.Errorf("QuoteMask(%q) at %d = %08b; want %08b", src, i, got, want)
+			}
+		}
+	}
+
+	run(`{"a":1}`)
+	run(`{"key with \"escaped\" quotes":"and a \\ backslash","n":[1,2,3]}`)
+	run(`"\\\\\\\"still inside\\\\" outside "in`)
+	run(`{"long":"` + string(bytes.Repeat([]byte(`\\\"`), 30)) + `"}`)
+
+	var s ScanState
+	s.Escaped, s.InQuote = true, true
+	s.Advance(^uint64(0))
+	if s.Reset(); s != (ScanState{}) {
+		t.Errorf("Reset() left %+v; want the zero state", s)
+	}
+}
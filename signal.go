@@ -0,0 +1,75 @@
+package swar
+
+// maxLaneWindow is the largest MovingAverage window summed in 16-bit slots
+// 128 bytes of 255 stay below 2^15, which the reciprocal division requires
+const maxLaneWindow = 128
+
+// MovingAverage writes the rounded mean of each window-byte run of src to dst and returns the count
+// A valid convolution: output i averages src[i:i+window], giving len(src)-window+1 bytes
+func MovingAverage(dst, src []byte, window int) int {
+	if window <= 0 || window > len(src) {
+		return 0
+	}
+	n := len(src) - window + 1
+	dst = dst[:n]
+	src = unaliased(dst, src, true)
+	if window > maxLaneWindow {
+		// Wide windows use a running sum; the per-lane sums would overflow their slots
+		sum := 0
+		for _, c := range src[:window] {
+			sum += int(c)
+		}
+		for i := range dst {
+			mean := byte((sum + window/2) / window)
+			if i+window < len(src) {
+				sum += int(src[i+window]) - int(src[i])
+			}
+			dst[i] = mean // written after src[i] is read, so dst may equal src
+		}
+		return n
+	}
+	div := newDivider15(uint64(window))
+	half := uint64(window/2) * 0x0000_0001_0000_0001
+	for i := 0; i < n; i += 8 {
+		var even, odd uint64
+		for k := range window {
+			v := loadLane(src, i+k)
+			even += v & mEven
+			odd += v >> 8 & mEven
+		}
+		q := div.divide(even&0x0000_FFFF_0000_FFFF+half) |
+			div.divide(odd&0x0000_FFFF_0000_FFFF+half)<<8 |
+			div.divide(even>>16&0x0000_FFFF_0000_FFFF+half)<<16 |
+			div.divide(odd>>16&0x0000_FFFF_0000_FFFF+half)<<24
+		storeLane(dst, i, q)
+	}
+	return n
+}
+
+// DownsampleByTwo writes the average of each adjacent pair of src bytes to dst of len(src)/2 bytes
+// Pairs split with mEven and mOdd are averaged rounding down; a final unpaired byte is dropped
+func DownsampleByTwo(dst, src []byte) {
+	n := len(src) / 2
+	src, dst = src[:2*n], dst[:n]
+	src = unaliased(dst, src, true)
+	for i := 0; i < n; i += 8 {
+		lo, hi := loadLane(src, 2*i), loadLane(src, 2*i+8)
+		lo = AverageBytes(lo&mEven, lo&mOdd>>8)
+		hi = AverageBytes(hi&mEven, hi&mOdd>>8)
+		storeLane(dst, i, narrowBytes(lo)|narrowBytes(hi)<<32)
+	}
+}
+
+// UpsampleByTwo writes every byte of src to dst twice
+// Nearest-neighbour stretch for waveform and thumbnail display; dst must hold 2*len(src) bytes
+func UpsampleByTwo(dst, src []byte) {
+	dst = dst[:2*len(src)]
+	src = unaliased(dst, src, false)
+	for i := 0; i < len(src); i += 8 {
+		v := loadLane(src, i)
+		storeLane(dst, 2*i, widenBytes(v)*0x0101)
+		if 2*i+8 < len(dst) {
+			storeLane(dst, 2*i+8, widenBytes(v>>32)*0x0101)
+		}
+	}
+}
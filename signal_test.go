@@ -0,0 +1,73 @@
+package swar
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestMovingAverage compares each window mean with a scalar sum for windows on both sides
+// of maxLaneWindow, where the lane path hands over to the running sum.
+func TestMovingAverage(t *testing.T) {
+	src := make([]byte, 400)
+	for i := range src {
+		src[i] = byte(i*i*7 + i)
+	}
+	src[100], src[101], src[102] = 0xFF, 0xFF, 0xFF
+	for _, window := range []int{0, 1, 2, 3, 5, 8, 17, 127, 128, 129, 300, 400, 401} {
+		dst := make([]byte, len(src))
+		n := MovingAverage(dst, src, window)
+		want := max(len(src)-window+1, 0)
+		if window == 0 {
+			want = 0
+		}
+		if n != want {
+			t.Errorf("MovingAverage(window %d) = %d outputs; want %d", window, n, want)
+			continue
+		}
+		for i := range n {
+			sum := 0
+			for _, c := range src[i : i+window] {
+				sum += int(c)
+			}
+			if want := byte((sum + window/2) / window); dst[i] != want {
+				t.Errorf("MovingAverage(window %d)[%d] = %d; want %d", window, i, dst[i], want)
+			}
+		}
+	}
+
+	ones := bytes.Repeat([]byte{0xFF}, 200)
+	dst := make([]byte, len(ones))
+	if n := MovingAverage(dst, ones, maxLaneWindow); !bytes.Equal(dst[:n], ones[:n]) {
+		t.Errorf("MovingAverage(0xFF..., %d) = %v; want all 0xFF", maxLaneWindow, dst[:n])
+	}
+}
+
+// TestResampleByTwo checks pair averaging and byte doubling against scalar loops for odd
+// and even lengths, and that downsampling an upsampled buffer restores it exactly.
+func TestResampleByTwo(t *testing.T) {
+	src := make([]byte, 101)
+	for i := range src {
+		src[i] = byte(i*i*7 + i)
+	}
+	for n := 0; n <= len(src); n++ {
+		down := make([]byte, n/2)
+		DownsampleByTwo(down, src[:n])
+		for i := range down {
+			if want := byte((int(src[2*i]) + int(src[2*i+1])) / 2); down[i] != want {
+				t.Errorf("DownsampleByTwo(len %d)[%d] = %d; want %d", n, i, down[i], want)
+			}
+		}
+		up := make([]byte, 2*n)
+		UpsampleByTwo(up, src[:n])
+		for i := range up {
+			if up[i] != src[i/2] {
+				t.Errorf("UpsampleByTwo(len %d)[%d] = %d; want %d", n, i, up[i], src[i/2])
+			}
+		}
+		round := make([]byte, n)
+		DownsampleByTwo(round, up)
+		if !bytes.Equal(round, src[:n]) {
+			t.Errorf("DownsampleByTwo(UpsampleByTwo(len %d)) did not round trip", n)
+		}
+	}
+}
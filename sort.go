@@ -0,0 +1,83 @@
+package swar
+
+// sortStage is one compare-exchange step of the bitonic network over the bytes of a lane
+// Byte i is paired with byte i^d; keepMin marks the bytes that take the smaller value
+type sortStage struct {
+	shift   uint
+	low     uint64 // bytes whose partner sits above them
+	keepMin uint64
+}
+
+// sortStages is Batcher's bitonic network for 8 elements: 6 stages, 24 comparators
+// Built once from the usual (k, d) loops so the masks are not written out by hand
+var sortStages = func() (stages []sortStage) {
+	for k := 2; k <= 8; k <<= 1 {
+		for d := k / 2; d > 0; d >>= 1 {
+			s := sortStage{shift: uint(8 * d)}
+			for i := range 8 {
+				if i&d == 0 {
+					s.low |= 0xFF << (8 * i)
+				}
+				if (i&d == 0) == (i&k == 0) {
+					s.keepMin |= 0xFF << (8 * i)
+				}
+			}
+			stages = append(stages, s)
+		}
+	}
+	return stages
+}()
+
+// SortBytesInLane returns v with its eight bytes in ascending order from byte 0 to byte 7
+// A fixed bitonic network of SelectSmallerBytes/SelectLargerBytes steps, with no branches
+func SortBytesInLane(v uint64) uint64 {
+	for _, s := range sortStages {
+		partner := v>>s.shift&s.low | v<<s.shift&^s.low
+		lo, hi := SelectSmallerBytes(v, partner), SelectLargerBytes(v, partner)
+		v = lo&s.keepMin | hi&^s.keepMin
+	}
+	return v
+}
+
+// MedianOfThreeBytes returns the middle value of a, b and c for each byte
+// Four min/max steps; the building block of 3-tap median filters
+func MedianOfThreeBytes(a, b, c uint64) uint64 {
+	return SelectLargerBytes(SelectSmallerBytes(a, b), SelectSmallerBytes(SelectLargerBytes(a, b), c))
+}
+
+// MedianFilter3 replaces each byte of src with the median of itself and its two neighbours
+// Removes single-sample spikes; the end bytes are copied. dst must hold len(src) bytes and may equal src
+func MedianFilter3(dst, src []byte) {
+	n := len(src)
+	dst = dst[:n]
+	src = unaliased(dst, src, true)
+	if n < 3 {
+		copy(dst, src)
+		return
+	}
+	first, last := src[0], src[n-1]
+	var prev uint64 // byte before the current lane, taken before dst can overwrite it
+	for i := 0; i < n; i += 8 {
+		v := loadLane(src, i)
+		next := loadLane(src, i+1)
+		storeLane(dst, i, MedianOfThreeBytes(v<<8|prev, v, next))
+		prev = v >> 56
+	}
+	dst[0], dst[n-1] = first, last
+}
+
+// SelectSecondLargestBytes returns the second largest of a, b and c for each byte
+// With three inputs this is the median; ties count separately, so 5,5,1 gives 5
+func SelectSecondLargestBytes(a, b, c uint64) uint64 {
+	return MedianOfThreeBytes(a, b, c)
+}
+
+// Top2Bytes returns the largest and second largest value at each byte position across rows
+// A vertical reduction for scoring many rows of 8 channels; missing rows count as zero
+func Top2Bytes(rows []uint64) (largest, second uint64) {
+	for _, r := range rows {
+		second = SelectLargerBytes(second, SelectSmallerBytes(largest, r))
+		largest = SelectLargerBytes(largest, r)
+	}
+	return largest, second
+}
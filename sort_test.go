@@ -0,0 +1,121 @@
+package swar
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestSortBytesInLane compares with slices.Sort on a sweep of lanes plus every 0/1 lane,
+// which by the zero-one principle is enough to prove the network sorts all inputs.
+func TestSortBytesInLane(t *testing.T) {
+	run := func(v uint64) {
+		want := IntToLanes(v)
+		slices.Sort(want[:])
+		if got := SortBytesInLane(v); got != LanesToInt(want) {
+			t.Errorf("SortBytesInLane(0x%016x) = 0x%016x; want 0x%016x", v, got, LanesToInt(want))
+		}
+	}
+
+	for bitsSet := range 256 {
+		var lanes [8]byte
+		for i := range lanes {
+			lanes[i] = byte(bitsSet>>i&1) * 0xFF
+		}
+		run(LanesToInt(lanes))
+	}
+	run(0x0001_0203_0405_0607)
+	run(0xFF80_7F01_00FF_807F)
+	for n := uint64(0); n < 0xFF_FF_FF_FF_FF; n = (n*12 + 13) / 11 {
+		run(n)
+		run(n * 0x9E37_79B9_7F4A_7C15)
+	}
+}
+
+// TestMedianOfThreeBytes checks all orderings of three distinct values in every lane,
+// plus ties, against sorting the three bytes.
+func TestMedianOfThreeBytes(t *testing.T) {
+	run := func(a, b, c uint64) {
+		la, lb, lc := IntToLanes(a), IntToLanes(b), IntToLanes(c)
+		var want [8]byte
+		for i := range want {
+			three := []byte{la[i], lb[i], lc[i]}
+			slices.Sort(three)
+			want[i] = three[1]
+		}
+		if got := MedianOfThreeBytes(a, b, c); got != LanesToInt(want) {
+			t.Errorf("MedianOfThreeBytes(0x%016x, 0x%016x, 0x%016x) = 0x%016x; want 0x%016x", a, b, c, got, LanesToInt(want))
+		}
+	}
+
+	x, y, z := uint64(0x00FF_7F80_0110_2030), uint64(0x80FE_0081_1001_3020), uint64(0xFF00_8000_1010_2020)
+	run(x, y, z)
+	run(x, z, y)
+	run(y, x, z)
+	run(y, z, x)
+	run(z, x, y)
+	run(z, y, x)
+	run(x, x, y)
+	for n := uint64(0); n < 0xFF_FF_FF_FF_FF; n = (n*12 + 13) / 11 {
+		run(n, n*0x9E37_79B9_7F4A_7C15, ^n)
+	}
+}
+
+// TestMedianFilter3 compares with a scalar 3-tap median for every length, both into a
+// separate buffer and in place, which must not see already filtered neighbours.
+func TestMedianFilter3(t *testing.T) {
+	src := make([]byte, 70)
+	for i := range src {
+		src[i] = byte(i * 3)
+		if i%5 == 2 {
+			src[i] = 0xFF
+		}
+	}
+	for n := 0; n <= len(src); n++ {
+		want := slices.Clone(src[:n])
+		for i := 1; i+1 < n; i++ {
+			three := []byte{src[i-1], src[i], src[i+1]}
+			slices.Sort(three)
+			want[i] = three[1]
+		}
+		dst := make([]byte, n)
+		MedianFilter3(dst, src[:n])
+		if !slices.Equal(dst, want) {
+			t.Errorf("MedianFilter3(%v) = %v; want %v", src[:n], dst, want)
+		}
+		inPlace := slices.Clone(src[:n])
+		MedianFilter3(inPlace, inPlace)
+		if !slices.Equal(inPlace, want) {
+			t.Errorf("MedianFilter3(%v) in place = %v; want %v", src[:n], inPlace, want)
+		}
+	}
+}
+
+// TestTop2Bytes compares the vertical top-two reduction with sorting each byte column,
+// for row counts from zero upwards, and checks SelectSecondLargestBytes on three rows.
+func TestTop2Bytes(t *testing.T) {
+	rows := make([]uint64, 20)
+	for i := range rows {
+		rows[i] = uint64(i+1) * 0x9E37_79B9_7F4A_7C15
+	}
+	rows[7] = rows[3] // duplicate row so ties are exercised
+	for n := 0; n <= len(rows); n++ {
+		var wantLargest, wantSecond [8]byte
+		for col := range 8 {
+			column := []byte{0, 0}
+			for _, r := range rows[:n] {
+				column = append(column, byte(r>>(8*col)))
+			}
+			slices.Sort(column)
+			wantLargest[col], wantSecond[col] = column[len(column)-1], column[len(column)-2]
+		}
+		largest, second := Top2Bytes(rows[:n])
+		if largest != LanesToInt(wantLargest) || second != LanesToInt(wantSecond) {
+			t.Errorf("Top2Bytes(%d rows) = 0x%016x, 0x%016x; want 0x%016x, 0x%016x", n, largest, second, LanesToInt(wantLargest), LanesToInt(wantSecond))
+		}
+		if n == 3 {
+			if got := SelectSecondLargestBytes(rows[0], rows[1], rows[2]); got != second {
+				t.Errorf("SelectSecondLargestBytes(rows[:3]) = 0x%016x; want 0x%016x", got, second)
+			}
+		}
+	}
+}
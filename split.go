@@ -0,0 +1,61 @@
+package swar
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// SplitIter iterates over the subslices of b separated by delim without allocating
+// Like bytes.SplitSeq, empty fields are yielded and empty input yields one empty slice
+func SplitIter(b []byte, delim byte) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		pattern := Dupe(delim)
+		start := 0
+		for i := 0; i < len(b); i += 8 {
+			m := HighBitWhereEqual(loadLane(b, i), pattern)
+			if i+8 > len(b) {
+				m &= uint64(1)<<(8*(len(b)-i)) - 1 // padding may equal a zero delim
+			}
+			for ; m != 0; m &= m - 1 {
+				end := i + bits.TrailingZeros64(m)>>3
+				if !yield(b[start:end:end]) {
+					return
+				}
+				start = end + 1
+			}
+		}
+		yield(b[start:len(b):len(b)])
+	}
+}
+
+// Fields iterates over the runs of b separated by ASCII whitespace without allocating
+// Leading, trailing and repeated whitespace never produce empty fields
+func Fields(b []byte) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		start, inField := 0, false
+		for i := 0; i < len(b); i += 8 {
+			space := uint(ExtractLowBits(spaceMask(loadLane(b, i)) >> 7))
+			if i+8 > len(b) {
+				space |= 0xFF << (len(b) - i) & 0xFF // padding ends the last field
+			}
+			prev := uint(1)
+			if inField {
+				prev = 0
+			}
+			for edges := (space ^ (space<<1 | prev)) & 0xFF; edges != 0; edges &= edges - 1 {
+				p := bits.TrailingZeros(edges)
+				if space>>p&1 == 0 {
+					start = i + p
+					continue
+				}
+				if !yield(b[start : i+p : i+p]) {
+					return
+				}
+			}
+			inField = space&0x80 == 0
+		}
+		if inField {
+			yield(b[start:len(b):len(b)])
+		}
+	}
+}
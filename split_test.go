@@ -0,0 +1,67 @@
+package swar
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestSplitIter verifies that splitting matches bytes.SplitSeq for empty fields, leading
+// and trailing delimiters and delimiters on lane boundaries. A zero delimiter must not
+// match the padding of the final lane.
+func TestSplitIter(t *testing.T) {
+	run := func(b string, delim byte) {
+		var got, want []string
+		for field := range SplitIter([]byte(b), delim) {
+			got = append(got, string(field))
+		}
+		for field := range bytes.SplitSeq([]byte(b), []byte{delim}) {
+			want = append(want, string(field))
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("SplitIter(%q, %q) = %q; want %q", b, delim, got, want)
+		}
+	}
+
+	run("", ',')
+	run("a,b,c", ',')
+	run(",,a,,", ',')
+	run("1234567,1234567,", ',')
+	run("field one;field two;a much longer third field;", ';')
+	run("a\x00b\x00", 0)
+	run("abc", 0)
+
+	for field := range SplitIter([]byte("x,y,z"), ',') {
+		if string(field) != "x" {
+			t.Errorf("first field = %q; want %q", field, "x")
+		}
+		break
+	}
+}
+
+// TestFields verifies whitespace tokenizing against strings.Fields for ASCII input,
+// including fields that span lanes, whitespace runs across lane boundaries and a field
+// that ends exactly at the end of the input.
+func TestFields(t *testing.T) {
+	run := func(b string) {
+		var got []string
+		for field := range Fields([]byte(b)) {
+			got = append(got, string(field))
+		}
+		if want := strings.Fields(b); !slices.Equal(got, want) {
+			t.Errorf("Fields(%q) = %q; want %q", b, got, want)
+		}
+	}
+
+	run("")
+	run("   ")
+	run("one")
+	run("  one two  three ")
+	run("1234567 12345678\t\n\v\f\rx")
+	run("averyveryverylongfieldthatspanslanes short")
+	run("a b c d e f g h i j k l m n o p")
+	for i := 0; i < 18; i++ {
+		run(strings.Repeat(" ", i) + "tok" + strings.Repeat(" ", 17-i) + "end")
+	}
+}
@@ -0,0 +1,241 @@
+package swar
+
+import "math/bits"
+
+// histogramBlock bounds the bytes counted before the 32-bit tables are merged
+const histogramBlock = 1 << 30
+
+// Histogram adds the number of occurrences of each byte value in b to counts
+// Four interleaved tables keep repeated bytes from serialising on one counter
+func Histogram(b []byte, counts *[256]uint64) {
+	var tables [4][256]uint32
+	for len(b) > 0 {
+		block := b[:min(len(b), histogramBlock)]
+		b = b[len(block):]
+		chunks, unused := BytesToLanes(block)
+		for _, v := range chunks {
+			tables[0][byte(v)]++
+			tables[1][byte(v>>8)]++
+			tables[2][byte(v>>16)]++
+			tables[3][byte(v>>24)]++
+			tables[0][byte(v>>32)]++
+			tables[1][byte(v>>40)]++
+			tables[2][byte(v>>48)]++
+			tables[3][byte(v>>56)]++
+		}
+		for _, c := range block[unused:] {
+			tables[0][c]++
+		}
+		for i := range counts {
+			counts[i] += uint64(tables[0][i]) + uint64(tables[1][i]) + uint64(tables[2][i]) + uint64(tables[3][i])
+		}
+		tables = [4][256]uint32{}
+	}
+}
+
+// BlockStat summarises one block for compressibility heuristics
+// Few distinct bytes, long runs or many zeros all suggest the block will compress well
+type BlockStat struct {
+	Distinct     int     // number of different byte values
+	MaxRun       int     // length of the longest run of one repeated byte
+	ZeroFraction float64 // fraction of bytes equal to zero
+}
+
+// BlockStats returns a BlockStat for each blockSize bytes of b; the last block may be short
+// Zeros are counted with equality masks and runs come from LongestRun
+func BlockStats(b []byte, blockSize int) []BlockStat {
+	if blockSize <= 0 {
+		return nil
+	}
+	stats := make([]BlockStat, 0, (len(b)+blockSize-1)/blockSize)
+	for len(b) > 0 {
+		block := b[:min(len(b), blockSize)]
+		b = b[len(block):]
+		var seen [4]uint64
+		for _, c := range block {
+			seen[c>>6] |= 1 << (c & 63)
+		}
+		_, maxRun := LongestRun(block)
+		zeros := countMasked(block, func(v uint64) uint64 {
+			return HighBitWhereEqual(v, 0)
+		})
+		stats = append(stats, BlockStat{
+			Distinct:     PopcountBitmap(seen[:]),
+			MaxRun:       maxRun,
+			ZeroFraction: float64(zeros) / float64(len(block)),
+		})
+	}
+	return stats
+}
+
+// CountGreater returns how many bytes of b are greater than t
+// One comparison and one popcount per lane, for threshold checks on every sample batch
+func CountGreater(b []byte, t byte) int {
+	threshold := Dupe(t)
+	return countMasked(b, func(v uint64) uint64 {
+		return HighBitWhereGreater(v, threshold)
+	})
+}
+
+// CountLess returns how many bytes of b are less than t
+// The mirror of CountGreater; the two plus the count of t itself add up to len(b)
+func CountLess(b []byte, t byte) int {
+	threshold := Dupe(t)
+	return countMasked(b, func(v uint64) uint64 {
+		return HighBitWhereLess(v, threshold)
+	})
+}
+
+// CountBetween returns how many bytes of b lie in the inclusive range [lo, hi], or 0 if lo > hi
+// Subtracting lo first folds both bounds into a single comparison against hi-lo
+func CountBetween(b []byte, lo, hi byte) int {
+	if lo > hi {
+		return 0
+	}
+	from, span := Dupe(lo), Dupe(hi-lo)
+	return countMasked(b, func(v uint64) uint64 {
+		return HighBitWhereGreater(SubtractBytesWithWrapping(v, from), span) ^ HighBits
+	})
+}
+
+// IndexOfMax returns the index of the first largest byte of b, or -1 if b is empty
+// Lanes are only compared with the best so far; the winning lane is resolved to a position once
+func IndexOfMax(b []byte) int {
+	if len(b) == 0 {
+		return -1
+	}
+	best, at := Dupe(b[0]), 0
+	for i := 0; i < len(b); i += 8 {
+		if v := loadLane(b, i); HighBitWhereGreater(v, best) != 0 {
+			best, at = Dupe(laneMax(v)), i
+		}
+	}
+	return at + bits.TrailingZeros64(HighBitWhereEqual(loadLane(b, at), best))>>3
+}
+
+// IndexOfMin returns the index of the first smallest byte of b, or -1 if b is empty
+// The final lane is padded with 0xFF so its padding can never win
+func IndexOfMin(b []byte) int {
+	if len(b) == 0 {
+		return -1
+	}
+	best, at := Dupe(b[0]), 0
+	for i := 0; i < len(b); i += 8 {
+		v := loadLane(b, i)
+		if i+8 > len(b) {
+			v |= ^uint64(0) << (8 * (len(b) - i))
+		}
+		if HighBitWhereLess(v, best) != 0 {
+			best, at = Dupe(laneMin(v)), i
+		}
+	}
+	return at + bits.TrailingZeros64(HighBitWhereEqual(loadLane(b, at), best))>>3
+}
+
+// laneMax returns the largest byte of v by folding the lane in half three times
+func laneMax(v uint64) byte {
+	v = SelectLargerBytes(v, v>>32)
+	v = SelectLargerBytes(v, v>>16)
+	return byte(SelectLargerBytes(v, v>>8))
+}
+
+// laneMin returns the smallest byte of v by folding the lane in half three times
+func laneMin(v uint64) byte {
+	v = SelectSmallerBytes(v, v>>32)
+	v = SelectSmallerBytes(v, v>>16)
+	return byte(SelectSmallerBytes(v, v>>8))
+}
+
+// ColumnStats tracks the minimum, maximum and sum of each column of fixed-width records
+// One lane covers eight columns, so each record costs a few operations per 8 bytes
+type ColumnStats struct {
+	width    int
+	rows     int
+	min, max []uint64 // running extremes, one lane per 8 columns
+	even     []uint64 // pending sums of columns 0,2,4,6 of each lane in 16-bit slots
+	odd      []uint64 // pending sums of columns 1,3,5,7 of each lane in 16-bit slots
+	pending  int      // rows added to even and odd since the last flush
+	totals   []uint64 // flushed sum of each column
+}
+
+// NewColumnStats creates statistics for records of width bytes, which must be positive
+// Records are typically rows of a binary log or table, e.g. 64 bytes wide
+func NewColumnStats(width int) *ColumnStats {
+	lanes := (width + 7) / 8
+	s := &ColumnStats{
+		width:  width,
+		min:    make([]uint64, lanes),
+		max:    make([]uint64, lanes),
+		even:   make([]uint64, lanes),
+		odd:    make([]uint64, lanes),
+		totals: make([]uint64, width),
+	}
+	for i := range s.min {
+		s.min[i] = ^uint64(0)
+	}
+	return s
+}
+
+// Add folds every complete record in records into the statistics
+// Returns the number of bytes consumed, so a partial trailing record can be kept for the next call
+func (s *ColumnStats) Add(records []byte) int {
+	n := len(records) / s.width * s.width
+	for r := 0; r < n; r += s.width {
+		record := records[r : r+s.width]
+		for k := range s.min {
+			v := loadLane(record, 8*k)
+			s.min[k] = SelectSmallerBytes(s.min[k], v)
+			s.max[k] = SelectLargerBytes(s.max[k], v)
+			AccumulateBytesInto16(&s.even[k], &s.odd[k], v)
+		}
+		s.rows++
+		if s.pending++; s.pending == MaxAccumulations16 {
+			s.flush()
+		}
+	}
+	return n
+}
+
+// flush moves the pending 16-bit slot sums into the per-column totals
+// Unlike FlushAccumulators16 the totals are 64-bit, so arbitrarily long streams cannot overflow
+func (s *ColumnStats) flush() {
+	for col := range s.totals {
+		k, shift := col/8, 16*(col%8/2)
+		sums := s.even
+		if col%2 == 1 {
+			sums = s.odd
+		}
+		s.totals[col] += sums[k] >> shift & 0xFFFF
+	}
+	clear(s.even)
+	clear(s.odd)
+	s.pending = 0
+}
+
+// Rows returns the number of records added so far
+func (s *ColumnStats) Rows() int {
+	return s.rows
+}
+
+// Min returns the smallest byte seen in column col, or 0 before any record is added
+func (s *ColumnStats) Min(col int) byte {
+	if s.rows == 0 {
+		return 0
+	}
+	return byte(s.min[col/8] >> (8 * (col % 8)))
+}
+
+// Max returns the largest byte seen in column col, or 0 before any record is added
+func (s *ColumnStats) Max(col int) byte {
+	return byte(s.max[col/8] >> (8 * (col % 8)))
+}
+
+// Sum returns the total of column col over every record added
+// Divide by Rows for the column mean
+func (s *ColumnStats) Sum(col int) uint64 {
+	sums := s.even
+	if col%2 == 1 {
+		sums = s.odd
+	}
+	return s.totals[col] + sums[col/8]>>(16*(col%8/2))&0xFFFF
+}
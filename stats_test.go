@@ -0,0 +1,185 @@
+package swar
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestHistogram compares with a byte loop for every length and checks that counts
+// accumulate across calls, so a histogram can be built from a stream of buffers.
+func TestHistogram(t *testing.T) {
+	b := make([]byte, 100)
+	for i := range b {
+		b[i] = byte(i * i % 11)
+	}
+	for n := 0; n <= len(b); n++ {
+		var got, want [256]uint64
+		Histogram(b[:n], &got)
+		Histogram(b[:n], &got)
+		for _, c := range b[:n] {
+			want[c] += 2
+		}
+		if got != want {
+			t.Errorf("Histogram(%v) twice = %v; want %v", b[:n], got, want)
+		}
+	}
+}
+
+// TestBlockStats compares each block's summary with direct scalar computation, including a
+// short final block whose zero padding must not be counted as zero bytes.
+func TestBlockStats(t *testing.T) {
+	b := make([]byte, 0, 300)
+	b = append(b, bytes.Repeat([]byte{0}, 70)...)
+	for i := 0; i < 150; i++ {
+		b = append(b, byte(i*i%23))
+	}
+	b = append(b, bytes.Repeat([]byte{'x'}, 45)...)
+	b = append(b, "tail"...)
+
+	for _, size := range []int{1, 7, 16, 64, 100, len(b)} {
+		got := BlockStats(b, size)
+		if len(got) != (len(b)+size-1)/size {
+			t.Errorf("BlockStats(len %d, %d) returned %d blocks", len(b), size, len(got))
+			continue
+		}
+		for i, stat := range got {
+			block := b[i*size : min(len(b), (i+1)*size)]
+			distinct := map[byte]bool{}
+			zeros := 0
+			for _, c := range block {
+				distinct[c] = true
+				if c == 0 {
+					zeros++
+				}
+			}
+			_, maxRun := LongestRun(block)
+			want := BlockStat{len(distinct), maxRun, float64(zeros) / float64(len(block))}
+			if stat != want {
+				t.Errorf("BlockStats(len %d, %d)[%d] = %+v; want %+v", len(b), size, i, stat, want)
+			}
+		}
+	}
+	if got := BlockStats(b, 0); got != nil {
+		t.Errorf("BlockStats(b, 0) = %v; want nil", got)
+	}
+}
+
+// TestCountThresholds compares CountGreater, CountLess and CountBetween with a byte loop for
+// every prefix of a buffer, at thresholds that include 0, 255 and an empty range.
+func TestCountThresholds(t *testing.T) {
+	b := make([]byte, 70)
+	for i := range b {
+		b[i] = byte(i * 97)
+	}
+	for n := 0; n <= len(b); n++ {
+		for _, th := range []byte{0, 1, 100, 128, 200, 254, 255} {
+			greater, less := 0, 0
+			for _, c := range b[:n] {
+				if c > th {
+					greater++
+				}
+				if c < th {
+					less++
+				}
+			}
+			if got := CountGreater(b[:n], th); got != greater {
+				t.Errorf("CountGreater(%v, %d) = %d; want %d", b[:n], th, got, greater)
+			}
+			if got := CountLess(b[:n], th); got != less {
+				t.Errorf("CountLess(%v, %d) = %d; want %d", b[:n], th, got, less)
+			}
+		}
+		for _, r := range [][2]byte{{0, 255}, {0, 0}, {255, 255}, {50, 150}, {128, 200}, {200, 100}} {
+			want := 0
+			for _, c := range b[:n] {
+				if r[0] <= c && c <= r[1] {
+					want++
+				}
+			}
+			if got := CountBetween(b[:n], r[0], r[1]); got != want {
+				t.Errorf("CountBetween(%v, %d, %d) = %d; want %d", b[:n], r[0], r[1], got, want)
+			}
+		}
+	}
+}
+
+// TestIndexOfMax compares IndexOfMax and IndexOfMin with a byte loop for every prefix of
+// several buffers, including ties that must resolve to the first index and extremes of 0
+// and 255 that meet the final lane's padding.
+func TestIndexOfMax(t *testing.T) {
+	run := func(b []byte) {
+		for n := 0; n <= len(b); n++ {
+			wantMax, wantMin := -1, -1
+			for i, c := range b[:n] {
+				if wantMax < 0 || c > b[wantMax] {
+					wantMax = i
+				}
+				if wantMin < 0 || c < b[wantMin] {
+					wantMin = i
+				}
+			}
+			if got := IndexOfMax(b[:n]); got != wantMax {
+				t.Errorf("IndexOfMax(%v) = %d; want %d", b[:n], got, wantMax)
+			}
+			if got := IndexOfMin(b[:n]); got != wantMin {
+				t.Errorf("IndexOfMin(%v) = %d; want %d", b[:n], got, wantMin)
+			}
+		}
+	}
+
+	ramp := make([]byte, 40)
+	for i := range ramp {
+		ramp[i] = byte(i*53 + 7)
+	}
+	run(ramp)
+	run(bytes.Repeat([]byte{0}, 20))
+	run(bytes.Repeat([]byte{255}, 20))
+	run([]byte{3, 9, 1, 9, 1, 4, 4, 4, 9, 0, 200, 0, 200, 17})
+	run([]byte{5, 5, 5, 5, 5, 5, 5, 5, 6, 4, 6, 4})
+}
+
+// TestColumnStats compares per-column min, max and sum with a scalar pass over records of
+// widths that do and do not fill whole lanes, streaming the data in uneven pieces and with
+// enough rows of 255 to overflow the 16-bit partial sums if they were not flushed in time.
+func TestColumnStats(t *testing.T) {
+	for _, width := range []int{1, 5, 8, 13, 64} {
+		const rows = 700
+		data := make([]byte, width*rows)
+		for i := range data {
+			data[i] = byte(i * i * 7 / 5)
+			if i/width%3 == 0 {
+				data[i] = 255
+			}
+		}
+		s := NewColumnStats(width)
+		var carry []byte
+		for rest, piece := data, 1; len(rest) > 0; piece = piece*3 + 1 {
+			carry = append(carry, rest[:min(len(rest), piece)]...)
+			rest = rest[min(len(rest), piece):]
+			n := s.Add(carry)
+			if n%width != 0 || len(carry)-n >= width {
+				t.Fatalf("Add(%d bytes) with width %d consumed %d", len(carry), width, n)
+			}
+			carry = carry[n:]
+		}
+		if s.Rows() != rows {
+			t.Errorf("width %d: Rows() = %d; want %d", width, s.Rows(), rows)
+		}
+		for col := range width {
+			lo, hi, sum := byte(255), byte(0), uint64(0)
+			for r := range rows {
+				c := data[r*width+col]
+				lo, hi, sum = min(lo, c), max(hi, c), sum+uint64(c)
+			}
+			if got := s.Min(col); got != lo {
+				t.Errorf("width %d: Min(%d) = %d; want %d", width, col, got, lo)
+			}
+			if got := s.Max(col); got != hi {
+				t.Errorf("width %d: Max(%d) = %d; want %d", width, col, got, hi)
+			}
+			if got := s.Sum(col); got != sum {
+				t.Errorf("width %d: Sum(%d) = %d; want %d", width, col, got, sum)
+			}
+		}
+	}
+}
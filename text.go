@@ -0,0 +1,120 @@
+package swar
+
+// IsASCII reports whether every byte of b is below 0x80
+// ORs whole lanes together and tests the high bits once per chunk
+func IsASCII(b []byte) bool {
+	chunks, unused := BytesToLanes(b)
+	var acc uint64
+	for _, chunk := range chunks {
+		acc |= chunk
+	}
+	for _, c := range b[unused:] {
+		acc |= uint64(c)
+	}
+	return acc&HighBits == 0
+}
+
+// IndexNonASCII returns the index of the first byte of b that is 0x80 or above
+// Returns -1 if b is entirely ASCII
+func IndexNonASCII(b []byte) int {
+	return indexMasked(b, nonASCIIMask)
+}
+
+// nonASCIIMask keeps the high bit of every byte at or above 0x80
+func nonASCIIMask(v uint64) uint64 {
+	return v & HighBits
+}
+
+// CountRunes counts the UTF-8 code points in b without decoding them
+// Every byte that is not a continuation byte (0b10xxxxxx) starts a new rune
+func CountRunes(b []byte) int {
+	return len(b) - countMasked(b, continuationMask)
+}
+
+// continuationMask sets the high bit of every UTF-8 continuation byte
+func continuationMask(v uint64) uint64 {
+	return v & ^(v << 1) & HighBits
+}
+
+// ToUpperASCII writes src to dst with ASCII letters a-z converted to upper case
+// dst must be at least len(src) bytes and may be the same slice as src
+func ToUpperASCII(dst, src []byte) {
+	transformLanes(dst, src, upperASCIILane)
+}
+
+// ToLowerASCII writes src to dst with ASCII letters A-Z converted to lower case
+// dst must be at least len(src) bytes and may be the same slice as src
+func ToLowerASCII(dst, src []byte) {
+	transformLanes(dst, src, lowerASCIILane)
+}
+
+// SwapCaseASCII writes src to dst with the case of every ASCII letter inverted
+// dst must be at least len(src) bytes and may be the same slice as src
+func SwapCaseASCII(dst, src []byte) {
+	transformLanes(dst, src, swapCaseASCIILane)
+}
+
+// ToUpperASCIIInPlace converts ASCII letters in b to upper case
+// Bytes outside a-z, including UTF-8 sequences, are left untouched
+func ToUpperASCIIInPlace(b []byte) {
+	transformLanes(b, b, upperASCIILane)
+}
+
+// ToLowerASCIIInPlace converts ASCII letters in b to lower case
+// Bytes outside A-Z, including UTF-8 sequences, are left untouched
+func ToLowerASCIIInPlace(b []byte) {
+	transformLanes(b, b, lowerASCIILane)
+}
+
+// lowerLetterMask sets the high bit of every byte in a-z
+func lowerLetterMask(v uint64) uint64 {
+	return HighBitWhereGreater(v, Dupe('a'-1)) & HighBitWhereLess(v, Dupe('z'+1))
+}
+
+// upperLetterMask sets the high bit of every byte in A-Z
+func upperLetterMask(v uint64) uint64 {
+	return HighBitWhereGreater(v, Dupe('A'-1)) & HighBitWhereLess(v, Dupe('Z'+1))
+}
+
+// upperASCIILane clears the case bit (0x20) of lower case letters
+func upperASCIILane(v uint64) uint64 {
+	return v ^ lowerLetterMask(v)>>2
+}
+
+// lowerASCIILane sets the case bit (0x20) of upper case letters
+func lowerASCIILane(v uint64) uint64 {
+	return v ^ upperLetterMask(v)>>2
+}
+
+// swapCaseASCIILane flips the case bit (0x20) of every letter
+func swapCaseASCIILane(v uint64) uint64 {
+	return v ^ (lowerLetterMask(v)|upperLetterMask(v))>>2
+}
+
+// TitleCaseASCII writes src to dst with the first letter of every word upper cased
+// A word is a run of letters, digits, apostrophes and non-ASCII bytes, so "(foo) bar-baz" gives "(Foo) Bar-Baz"
+func TitleCaseASCII(dst, src []byte) {
+	dst = dst[:len(src)]
+	src = unaliased(dst, src, true)
+	boundary := uint64(0x80) // the start of src acts as a boundary
+	for i := 0; i < len(src); i += 8 {
+		v := loadLane(src, i)
+		outside := wordByteMask(v) ^ HighBits
+		starts := (outside<<8 | boundary) & lowerLetterMask(v)
+		storeLane(dst, i, v^starts>>2)
+		boundary = outside >> 56 // carry the last byte's boundary bit into the next lane
+	}
+}
+
+// wordByteMask sets the high bit of every byte that continues a word for TitleCaseASCII
+// Apostrophes keep "don't" whole and non-ASCII bytes keep UTF-8 letters inside their word
+func wordByteMask(v uint64) uint64 {
+	letters := lowerLetterMask(v | 0x2020_2020_2020_2020)
+	return letters | digitMask(v) | HighBitWhereEqual(v, Dupe('\'')) | v&HighBits
+}
+
+// spaceMask sets the high bit of every ASCII whitespace byte (\t \n \v \f \r and space)
+func spaceMask(v uint64) uint64 {
+	controls := HighBitWhereGreater(v, Dupe('\t'-1)) & HighBitWhereLess(v, Dupe('\r'+1))
+	return controls | HighBitWhereEqual(v, Dupe(' '))
+}
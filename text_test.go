@@ -0,0 +1,119 @@
+package swar
+
+import (
+	"bytes"
+	"testing"
+	"unicode/utf8"
+)
+
+// TestIsASCII verifies that ASCII detection inspects every byte, including the tail
+// that does not fill a whole lane. A missed high bit would let non-ASCII input take
+// fast paths that assume single-byte characters.
+func TestIsASCII(t *testing.T) {
+	run := func(b string, want bool) {
+		if got := IsASCII([]byte(b)); got != want {
+			t.Errorf("IsASCII(%q) = %v; want %v", b, got, want)
+		}
+	}
+
+	run("", true)
+	run("hello", true)
+	run("hello, world! 0123456789", true)
+	run("héllo", false)
+	run("0123456789abcde\xff", false)
+	run("\x80", false)
+}
+
+// TestIndexNonASCII verifies that the first non-ASCII byte is located in full lanes and
+// in the tail. Knowing the exact position lets callers copy the clean prefix before
+// falling back to UTF-8 aware processing.
+func TestIndexNonASCII(t *testing.T) {
+	run := func(b string, want int) {
+		if got := IndexNonASCII([]byte(b)); got != want {
+			t.Errorf("IndexNonASCII(%q) = %d; want %d", b, got, want)
+		}
+	}
+
+	run("", -1)
+	run("plain ascii text", -1)
+	run("\xc3\xa9", 0)
+	run("abcdefg\x80", 7)
+	run("abcdefgh\x80", 8)
+	run("abcdefghijklmnopq\xff", 17)
+	run("abc\xffdefghijk\xff", 3)
+}
+
+// TestCountRunes verifies that rune counting matches utf8.RuneCount for valid UTF-8.
+// Multi-byte sequences frequently straddle lane boundaries, so the inputs are chosen to
+// split characters across chunks and tails.
+func TestCountRunes(t *testing.T) {
+	run := func(b string) {
+		if got, want := CountRunes([]byte(b)), utf8.RuneCount([]byte(b)); got != want {
+			t.Errorf("CountRunes(%q) = %d; want %d", b, got, want)
+		}
+	}
+
+	run("")
+	run("ascii only")
+	run("héllo wörld")
+	run("日本語のテキスト")
+	run("emoji 🚀🔌🧩⚡ mix")
+	run("1234567€")
+}
+
+// TestCaseConversionASCII verifies the ASCII case conversions against the standard
+// library, including the tail bytes and in-place use. Only a-z and A-Z may change;
+// punctuation next to the letter ranges and UTF-8 bytes must survive untouched.
+func TestCaseConversionASCII(t *testing.T) {
+	run := func(name string, f func(dst, src []byte), src, want string) {
+		dst := make([]byte, len(src))
+		if f(dst, []byte(src)); string(dst) != want {
+			t.Errorf("%s(%q) = %q; want %q", name, src, dst, want)
+		}
+		inPlace := []byte(src)
+		if f(inPlace, inPlace); string(inPlace) != want {
+			t.Errorf("%s(%q) in place = %q; want %q", name, src, inPlace, want)
+		}
+	}
+
+	in := "Allo Zorld! I am NOT yelling @[`{ but I am using SWAR! é"
+	run("ToUpperASCII", ToUpperASCII, in, "ALLO ZORLD! I AM NOT YELLING @[`{ BUT I AM USING SWAR! é")
+	run("ToLowerASCII", ToLowerASCII, in, "allo zorld! i am not yelling @[`{ but i am using swar! é")
+	run("SwapCaseASCII", SwapCaseASCII, in, "aLLO zORLD! i AM not YELLING @[`{ BUT i AM USING swar! é")
+	run("ToUpperASCII", ToUpperASCII, "", "")
+	run("ToLowerASCII", ToLowerASCII, "XYZ", "xyz")
+
+	for c := 0; c < 256; c++ {
+		b := []byte{byte(c)}
+		ToUpperASCIIInPlace(b)
+		if want := bytes.ToUpper([]byte{byte(c)}); c < 0x80 && b[0] != want[0] {
+			t.Errorf("ToUpperASCIIInPlace(%#02x) = %#02x; want %#02x", c, b[0], want[0])
+		}
+		ToLowerASCIIInPlace(b)
+		if want := bytes.ToLower([]byte{byte(c)}); c < 0x80 && b[0] != want[0] {
+			t.Errorf("ToLowerASCIIInPlace(%#02x) = %#02x; want %#02x", c, b[0], want[0])
+		}
+	}
+}
+
+// TestTitleCaseASCII verifies that only letters directly after a word boundary are upper
+// cased, with the boundary state carried across lane boundaries. Words that begin
+// exactly at a lane boundary are the case most likely to be missed.
+func TestTitleCaseASCII(t *testing.T) {
+	run := func(src, want string) {
+		dst := make([]byte, len(src))
+		if TitleCaseASCII(dst, []byte(src)); string(dst) != want {
+			t.Errorf("TitleCaseASCII(%q) = %q; want %q", src, dst, want)
+		}
+	}
+
+	run("", "")
+	run("hello world", "Hello World")
+	run("1234567 abc", "1234567 Abc")
+	run("abcdefg hijklmn\topq\nrst", "Abcdefg Hijklmn\tOpq\nRst")
+	run("don't stop-me 2nd  (paren) ÉTÉ été", "Don't Stop-Me 2nd  (Paren) ÉTÉ été")
+	run(`hello-world (foo) "bar"`, `Hello-World (Foo) "Bar"`)
+	run("1234567-abc,def.ghi_jkl@mno", "1234567-Abc,Def.Ghi_Jkl@Mno")
+	run("[`{~a}]", "[`{~A}]")
+	run("MiXeD cAsE", "MiXeD CAsE")
+}
@@ -0,0 +1,40 @@
+package swar
+
+import "encoding/binary"
+
+// Rot13 writes src to dst with every ASCII letter rotated 13 places in the alphabet
+// dst must be at least len(src) bytes and may be the same slice as src
+func Rot13(dst, src []byte) {
+	transformLanes(dst, src, rot13Lane)
+}
+
+// rot13Lane moves a-m/A-M forward and n-z/N-Z back by 13 without crossing lanes
+func rot13Lane(v uint64) uint64 {
+	folded := v | 0x2020_2020_2020_2020
+	letters := HighBitWhereGreater(folded, Dupe('a'-1)) & HighBitWhereLess(folded, Dupe('z'+1))
+	firstHalf := HighBitWhereLess(folded, Dupe('n')) & letters
+	secondHalf := letters &^ firstHalf
+	return v + (firstHalf>>7)*13 - (secondHalf>>7)*13
+}
+
+// XORMask writes src to dst XORed with key repeated every 8 bytes
+// key[0] applies to src[0]; dst must be at least len(src) bytes and may equal src
+func XORMask(dst, src []byte, key [8]byte) {
+	k := LanesToInt(key)
+	transformLanes(dst, src, func(v uint64) uint64 {
+		return v ^ k
+	})
+}
+
+// XORMask4 writes src to dst XORed with key repeated every 4 bytes
+// key[0] applies to src[0]; the key is broadcast with Dupe4, and dst may equal src
+func XORMask4(dst, src []byte, key [4]byte) {
+	XORMask(dst, src, IntToLanes(Dupe4(binary.LittleEndian.Uint32(key[:]))))
+}
+
+// UnmaskWebSocket XORs a WebSocket payload in place with its 4-byte key; masking is the same operation
+// offset is the position of payload[0] within the frame, so fragments can be unmasked as they arrive
+func UnmaskWebSocket(payload []byte, key [4]byte, offset int) {
+	k := RotateLane(Dupe4(binary.LittleEndian.Uint32(key[:])), offset)
+	XORMask(payload, payload, IntToLanes(k))
+}
@@ -0,0 +1,83 @@
+package swar
+
+import (
+	"testing"
+)
+
+// TestRot13 verifies that letters rotate within their own case and everything else is
+// copied unchanged. Applying ROT13 twice must restore the original input, which also
+// exercises in-place use.
+func TestRot13(t *testing.T) {
+	run := func(src, want string) {
+		dst := make([]byte, len(src))
+		if Rot13(dst, []byte(src)); string(dst) != want {
+			t.Errorf("Rot13(%q) = %q; want %q", src, dst, want)
+		}
+		if Rot13(dst, dst); string(dst) != src {
+			t.Errorf("Rot13(Rot13(%q)) = %q; want %q", src, dst, src)
+		}
+	}
+
+	run("", "")
+	run("Hello, World!", "Uryyb, Jbeyq!")
+	run("abcdefghijklmnopqrstuvwxyz", "nopqrstuvwxyzabcdefghijklm")
+	run("ABCDEFGHIJKLMNOPQRSTUVWXYZ", "NOPQRSTUVWXYZABCDEFGHIJKLM")
+	run("@[`{ 09 é", "@[`{ 09 é")
+}
+
+// TestXORMask verifies that the repeating 8- and 4-byte keys line up with the source bytes
+// in full lanes and in the tail. Masking twice with the same key must be the identity.
+func TestXORMask(t *testing.T) {
+	key := [8]byte{1, 2, 3, 4, 5, 6, 7, 8}
+	key4 := [4]byte{0xA1, 0xB2, 0xC3, 0xD4}
+	for n := 0; n < 40; n++ {
+		src := make([]byte, n)
+		for i := range src {
+			src[i] = byte(i * 31)
+		}
+		dst := make([]byte, n)
+		XORMask(dst, src, key)
+		for i := range src {
+			if want := src[i] ^ key[i%8]; dst[i] != want {
+				t.Errorf("XORMask(len %d)[%d] = %#02x; want %#02x", n, i, dst[i], want)
+			}
+		}
+		if XORMask(dst, dst, key); string(dst) != string(src) {
+			t.Errorf("XORMask twice (len %d) = %v; want %v", n, dst, src)
+		}
+		XORMask4(dst, src, key4)
+		for i := range src {
+			if want := src[i] ^ key4[i%4]; dst[i] != want {
+				t.Errorf("XORMask4(len %d)[%d] = %#02x; want %#02x", n, i, dst[i], want)
+			}
+		}
+		if XORMask4(dst, dst, key4); string(dst) != string(src) {
+			t.Errorf("XORMask4 twice (len %d) = %v; want %v", n, dst, src)
+		}
+	}
+}
+
+// TestUnmaskWebSocket verifies unmasking against the RFC 6455 example frame and that a
+// payload unmasked in arbitrary fragments matches unmasking it in one call. Resuming at
+// an offset that is not a multiple of four is where hand-rolled versions go wrong.
+func TestUnmaskWebSocket(t *testing.T) {
+	key := [4]byte{0x37, 0xfa, 0x21, 0x3d}
+	payload := []byte{0x7f, 0x9f, 0x4d, 0x51, 0x58}
+	if UnmaskWebSocket(payload, key, 0); string(payload) != "Hello" {
+		t.Errorf("UnmaskWebSocket(RFC 6455 example) = %q; want %q", payload, "Hello")
+	}
+
+	src := []byte("a longer payload that spans several lanes and a ragged tail")
+	whole := append([]byte(nil), src...)
+	UnmaskWebSocket(whole, key, 0)
+	for _, split := range []int{1, 3, 5, 8, 13} {
+		parts := append([]byte(nil), src...)
+		for off := 0; off < len(parts); off += split {
+			end := min(off+split, len(parts))
+			UnmaskWebSocket(parts[off:end], key, off)
+		}
+		if string(parts) != string(whole) {
+			t.Errorf("UnmaskWebSocket in %d-byte fragments = %q; want %q", split, parts, whole)
+		}
+	}
+}
@@ -1,17 +1,28 @@
 package swar
 
-import "unsafe"
+import (
+	"math/bits"
+	"slices"
+	"unsafe"
+)
 
 const (
 	// LowBits has the lowest bit set in each byte for value duplication
 	LowBits uint64 = 0x0101_0101_0101_0101
+	// slots16 has the lowest bit set in each 16-bit slot for widened arithmetic
+	slots16 uint64 = 0x0001_0001_0001_0001
 	// packMask packs low bits from each byte into a single byte
 	packMask uint64 = 0x0102_0408_1020_4080
+	// packMaskMSB0 packs low bits from each byte into a single byte, first byte highest
+	packMaskMSB0 uint64 = 0x8040_2010_0804_0201
 )
 
 // BytesToLanes converts a []byte to []uint64 for SWAR processing
 // Returns uint64 lanes and index where unused bytes begin
 func BytesToLanes(b []byte) ([]uint64, int) {
+	if len(b) < 8 {
+		return nil, 0
+	}
 	countChunks := len(b) / 8
 	chunks := unsafe.Slice((*uint64)(unsafe.Pointer(&b[0])), countChunks)
 	return chunks, countChunks * 8
@@ -20,6 +31,9 @@ func BytesToLanes(b []byte) ([]uint64, int) {
 // LanesToBytes converts []uint64 back to []byte
 // Zero-copy conversion for optimal performance
 func LanesToBytes(lanes []uint64) []byte {
+	if len(lanes) == 0 {
+		return nil
+	}
 	countBytes := len(lanes) * 8
 	bytes := unsafe.Slice((*byte)(unsafe.Pointer(&lanes[0])), countBytes)
 	return bytes
@@ -31,12 +45,186 @@ func Dupe(c byte) uint64 {
 	return uint64(c) * LowBits
 }
 
+// Dupe2 tiles a 16-bit pattern across all four 16-bit slots of a uint64
+// The low byte of p lands first in memory order, e.g. Dupe2(0x0A0D) repeats "\r\n"
+func Dupe2(p uint16) uint64 {
+	return uint64(p) * slots16
+}
+
+// Dupe4 tiles a 32-bit pattern across both halves of a uint64
+// The low byte of p lands first in memory order, like a little-endian load of the pattern
+func Dupe4(p uint32) uint64 {
+	return uint64(p) * 0x0000_0001_0000_0001
+}
+
+// DupePattern tiles the bytes of p across a lane starting with p[0]
+// Patterns longer than 8 bytes are truncated; an empty pattern gives zero
+func DupePattern(p []byte) uint64 {
+	return DupePatternAt(p, 0)
+}
+
+// DupePatternAt tiles p across a lane starting at position offset of the repeating pattern
+// For lengths that do not divide 8, the next lane starts at offset+8; repeating-key XOR uses this
+func DupePatternAt(p []byte, offset int) uint64 {
+	if len(p) == 0 {
+		return 0
+	}
+	var lane [8]byte
+	for i := range lane {
+		lane[i] = p[(offset+i)%len(p)]
+	}
+	return LanesToInt(lane)
+}
+
+// RotateLane moves each byte of v n positions towards the start in memory order, wrapping around
+// Byte i of the result is byte (i+n)%8 of v; shifts the phase of a tiled 1, 2, 4 or 8 byte pattern
+func RotateLane(v uint64, n int) uint64 {
+	return bits.RotateLeft64(v, -8*(n&7))
+}
+
 // ExtractLowBits packs the low bit from each byte into a single byte
-// Compacts 8 comparison results into a single byte
+// Compacts 8 comparison results into a single byte; byte i maps to bit i as in ExtractLowBitsLSB0
 func ExtractLowBits(v uint64) byte {
 	return byte((v * packMask) >> 56)
 }
 
+// ExtractLowBitsLSB0 packs the low bit of byte i into bit i, where byte 0 is first in memory
+// Matches x86 movemask and bitmaps indexed with 1<<i; every other bit of v must be clear
+func ExtractLowBitsLSB0(v uint64) byte {
+	return ExtractLowBits(v)
+}
+
+// ExtractLowBitsMSB0 packs the low bit of byte i into bit 7-i, where byte 0 is first in memory
+// Matches bitmaps that read left to right as the most significant bit first, like PBM rows
+func ExtractLowBitsMSB0(v uint64) byte {
+	return byte((v * packMaskMSB0) >> 56)
+}
+
+// ExtractHighBits packs the high bit of byte i into bit i, the movemask of a HighBitWhere* result
+// Other bits of v are ignored; same ordering as ExtractHighBitsLSB0
+func ExtractHighBits(v uint64) byte {
+	return ExtractLowBits(v >> 7 & LowBits)
+}
+
+// ExtractHighBitsLSB0 packs the high bit of byte i into bit i, where byte 0 is first in memory
+// Other bits of v are ignored
+func ExtractHighBitsLSB0(v uint64) byte {
+	return ExtractHighBits(v)
+}
+
+// ExtractHighBitsMSB0 packs the high bit of byte i into bit 7-i, where byte 0 is first in memory
+// Other bits of v are ignored
+func ExtractHighBitsMSB0(v uint64) byte {
+	return ExtractLowBitsMSB0(v >> 7 & LowBits)
+}
+
+// SpreadBitsToLanes places bit i of m in the low bit of byte i, the reverse of ExtractLowBits
+// Turns an externally computed bitmap back into a lane mask; multiply by 0xFF for a byte mask
+func SpreadBitsToLanes(m byte) uint64 {
+	x := uint64(m)
+	x = (x | x<<28) & 0x0000_000F_0000_000F
+	x = (x | x<<14) & 0x0003_0003_0003_0003
+	return (x | x<<7) & LowBits
+}
+
+// SpreadBitsToHighBits places bit i of m in the high bit of byte i
+// Produces the masks SelectByHighBit and the HighBitWhere* family work with
+func SpreadBitsToHighBits(m byte) uint64 {
+	return SpreadBitsToLanes(m) << 7
+}
+
+// ExtractLowBits4 packs the low bit of each byte of four lanes into one uint32
+// Bit 8k+i is the low bit of byte i of v[k]; the lanes are merged first so one transpose does the work
+func ExtractLowBits4(v [4]uint64) uint32 {
+	x := v[0]&LowBits | v[1]&LowBits<<1 | v[2]&LowBits<<2 | v[3]&LowBits<<3
+	return uint32(transposeBits8x8(x))
+}
+
+// ExtractLowBits8 packs the low bit of each byte of eight lanes into one uint64
+// Bit 8k+i is the low bit of byte i of v[k], the layout MatchBitmap produces for 64 bytes
+func ExtractLowBits8(v [8]uint64) uint64 {
+	var x uint64
+	for k, lane := range v {
+		x |= lane & LowBits << k
+	}
+	return transposeBits8x8(x)
+}
+
+// transposeBits8x8 treats v as an 8x8 bit matrix with byte i as row i and transposes it
+// Three delta swaps from Hacker's Delight exchange 1x1, 2x2 and 4x4 blocks
+func transposeBits8x8(x uint64) uint64 {
+	t := (x ^ x>>7) & 0x00AA_00AA_00AA_00AA
+	x ^= t ^ t<<7
+	t = (x ^ x>>14) & 0x0000_CCCC_0000_CCCC
+	x ^= t ^ t<<14
+	t = (x ^ x>>28) & 0x0000_0000_F0F0_F0F0
+	return x ^ t ^ t<<28
+}
+
+// TransposeBytes8x8 transposes the 8x8 byte matrix whose row r is rows[r], in place
+// Byte c of row r swaps with byte r of row c, turning eight records into eight columns
+func TransposeBytes8x8(rows *[8]uint64) {
+	// Exchange 1-byte, then 2-byte, then 4-byte blocks between rows 1, 2 and 4 apart
+	for _, step := range [...]struct {
+		rows, shift int
+		mask        uint64
+	}{{1, 8, 0x00FF_00FF_00FF_00FF}, {2, 16, 0x0000_FFFF_0000_FFFF}, {4, 32, 0x0000_0000_FFFF_FFFF}} {
+		for r := range rows {
+			if r&step.rows != 0 {
+				continue
+			}
+			a, b := &rows[r], &rows[r+step.rows]
+			t := (*a>>step.shift ^ *b) & step.mask
+			*b ^= t
+			*a ^= t << step.shift
+		}
+	}
+}
+
+// PrefixXorBits sets bit i to the XOR of bits 0 through i of v
+// Equivalent to a carry-less multiply by all ones; turns quote bits into string regions
+func PrefixXorBits(v uint64) uint64 {
+	v ^= v << 1
+	v ^= v << 2
+	v ^= v << 4
+	v ^= v << 8
+	v ^= v << 16
+	v ^= v << 32
+	return v
+}
+
+// InterleaveBytes zips the bytes of a and b as a0 b0 a1 b1 ... across two lanes
+// lo holds the pairs from bytes 0-3 and hi from bytes 4-7; the SWAR form of an unpack
+func InterleaveBytes(a, b uint64) (lo, hi uint64) {
+	lo = widenBytes(a) | widenBytes(b)<<8
+	hi = widenBytes(a>>32) | widenBytes(b>>32)<<8
+	return lo, hi
+}
+
+// DeinterleaveBytes splits two lanes of zipped pairs back into their even and odd bytes
+// Inverse of InterleaveBytes; a gathers bytes 0, 2, 4, ... and b bytes 1, 3, 5, ...
+func DeinterleaveBytes(lo, hi uint64) (a, b uint64) {
+	a = narrowBytes(lo) | narrowBytes(hi)<<32
+	b = narrowBytes(lo>>8) | narrowBytes(hi>>8)<<32
+	return a, b
+}
+
+// widenBytes moves the low 4 bytes of v into the low byte of each 16-bit slot
+// Two shift-and-mask steps open a gap after every byte
+func widenBytes(v uint64) uint64 {
+	v &= 0xFFFF_FFFF
+	v = (v | v<<16) & 0x0000_FFFF_0000_FFFF
+	return (v | v<<8) & 0x00FF_00FF_00FF_00FF
+}
+
+// narrowBytes packs the low byte of each 16-bit slot of v into the low 4 bytes
+// Inverse of widenBytes; the high byte of each slot is ignored
+func narrowBytes(v uint64) uint64 {
+	v &= 0x00FF_00FF_00FF_00FF
+	v = (v | v>>8) & 0x0000_FFFF_0000_FFFF
+	return (v | v>>16) & 0xFFFF_FFFF
+}
+
 // IntToLanes converts a uint64 to an 8-byte array
 // Access individual bytes for mixed SWAR/byte-level operations
 func IntToLanes(i uint64) [8]byte {
@@ -49,18 +237,169 @@ func LanesToInt(lanes [8]byte) uint64 {
 	return *(*uint64)(unsafe.Pointer(&lanes))
 }
 
-// Lookup provides precomputed data for optimized operations
-// OnesPositions maps byte values to positions of their set bits
-var Lookup = struct {
-	OnesPositions [256][]int
-}{
-	func() (res [256][]int) {
-		for b := range res {
-			for i := 0; i < 8; i++ {
-				if b>>i&1 == 1 {
-					res[b] = append(res[b], i)
-				}
+// loadLane reads up to 8 bytes of b starting at i into a lane
+// Bytes past the end of b read as zero so tails and lookahead can reuse lane kernels
+func loadLane(b []byte, i int) uint64 {
+	if len(b)-i >= 8 {
+		return *(*uint64)(unsafe.Pointer(&b[i]))
+	}
+	if i >= len(b) {
+		return 0
+	}
+	var lane [8]byte
+	copy(lane[:], b[i:])
+	return LanesToInt(lane)
+}
+
+// storeLane writes the lane v to b starting at i
+// Bytes that would land past the end of b are dropped
+func storeLane(b []byte, i int, v uint64) {
+	if len(b)-i >= 8 {
+		*(*uint64)(unsafe.Pointer(&b[i])) = v
+		return
+	}
+	lane := IntToLanes(v)
+	copy(b[i:], lane[:])
+}
+
+// overlap returns how many elements after the start of src dst starts, when their memory overlaps
+// A negative offset means dst starts before src; ok is false when they share no memory
+func overlap[T byte | uint64](dst, src []T) (offset int, ok bool) {
+	if len(dst) == 0 || len(src) == 0 {
+		return 0, false
+	}
+	size := int(unsafe.Sizeof(src[0]))
+	d := int(uintptr(unsafe.Pointer(&dst[0])) - uintptr(unsafe.Pointer(&src[0])))
+	if d <= -len(dst)*size || d >= len(src)*size {
+		return 0, false
+	}
+	return d / size, true
+}
+
+// unaliased returns src, or a copy of it when writing dst could clobber bytes of src not yet read
+// forward means the caller never writes past what it has read, so dst at or before src is safe
+func unaliased[T byte | uint64](dst, src []T, forward bool) []T {
+	if d, ok := overlap(dst, src); !ok || forward && d <= 0 {
+		return src
+	}
+	return slices.Clone(src)
+}
+
+// indexMasked returns the index of the first byte whose high bit is set by mask
+// Returns -1 when no byte of b matches
+func indexMasked(b []byte, mask func(uint64) uint64) int {
+	for i := 0; i < len(b); i += 8 {
+		if m := mask(loadLane(b, i)); m != 0 {
+			if idx := i + bits.TrailingZeros64(m)>>3; idx < len(b) {
+				return idx
 			}
+			return -1
+		}
+	}
+	return -1
+}
+
+// transformLanes stores f applied to every lane of src, the tail zero padded, into dst of len(src)
+// Like memmove, lanes run backwards when dst starts inside src, so any overlap is safe
+func transformLanes(dst, src []byte, f func(uint64) uint64) {
+	dst = dst[:len(src)]
+	srcLanes, unused := BytesToLanes(src)
+	dstLanes, _ := BytesToLanes(dst)
+	tail := func() {
+		if unused < len(src) {
+			lane := IntToLanes(f(loadLane(src, unused)))
+			copy(dst[unused:], lane[:])
+		}
+	}
+	if d, ok := overlap(dst, src); ok && d > 0 {
+		tail()
+		for i := len(srcLanes) - 1; i >= 0; i-- {
+			dstLanes[i] = f(srcLanes[i])
 		}
 		return
-	}()}
+	}
+	for i, chunk := range srcLanes {
+		dstLanes[i] = f(chunk)
+	}
+	tail()
+}
+
+// transformLanes2 stores f applied to paired lanes of a and b into dst and returns their common length
+// Overlap is handled like memmove; if dst starts inside one input and before the other, it is copied
+func transformLanes2(dst, a, b []byte, f func(x, y uint64) uint64) int {
+	n := min(len(a), len(b))
+	dst, a, b = dst[:n], a[:n], b[:n]
+	da, aliasA := overlap(dst, a)
+	db, aliasB := overlap(dst, b)
+	aheadA, aheadB := aliasA && da > 0, aliasB && db > 0
+	if aheadA != aheadB && (aliasA && da < 0 || aliasB && db < 0) {
+		if aheadA {
+			a = slices.Clone(a)
+		} else {
+			b = slices.Clone(b)
+		}
+		aheadA, aheadB = false, false
+	}
+	aLanes, unused := BytesToLanes(a)
+	bLanes, _ := BytesToLanes(b)
+	dstLanes, _ := BytesToLanes(dst)
+	tail := func() {
+		if unused < n {
+			lane := IntToLanes(f(loadLane(a, unused), loadLane(b, unused)))
+			copy(dst[unused:], lane[:])
+		}
+	}
+	if aheadA || aheadB {
+		tail()
+		for i := len(aLanes) - 1; i >= 0; i-- {
+			dstLanes[i] = f(aLanes[i], bLanes[i])
+		}
+		return n
+	}
+	for i, chunk := range aLanes {
+		dstLanes[i] = f(chunk, bLanes[i])
+	}
+	tail()
+	return n
+}
+
+// countMasked counts the bytes of b whose high bit is set by mask
+// Bytes padding the final lane are never counted
+func countMasked(b []byte, mask func(uint64) uint64) int {
+	chunks, unused := BytesToLanes(b)
+	count := 0
+	for _, chunk := range chunks {
+		count += bits.OnesCount64(mask(chunk) & HighBits)
+	}
+	if unused < len(b) {
+		valid := uint64(1)<<(8*(len(b)-unused)) - 1
+		count += bits.OnesCount64(mask(loadLane(b, unused)) & HighBits & valid)
+	}
+	return count
+}
+
+// Lookup provides precomputed data; OnesPositions[m][:OnesCount[m]] lists the set bits of m in order
+// Fixed-size arrays keep the whole table in 2.3KB with no per-entry slice headers
+var Lookup = func() (res struct {
+	OnesPositions [256][8]uint8
+	OnesCount     [256]uint8
+}) {
+	for b := range 256 {
+		for i := range 8 {
+			if b>>i&1 == 1 {
+				res.OnesPositions[b][res.OnesCount[b]] = uint8(i)
+				res.OnesCount[b]++
+			}
+		}
+	}
+	return
+}()
+
+// AppendOnesPositions appends base plus the position of each set bit of mask to dst
+// Turns an ExtractLowBits result for the lane at byte offset base into slice indices
+func AppendOnesPositions(dst []int, mask byte, base int) []int {
+	for _, p := range Lookup.OnesPositions[mask][:Lookup.OnesCount[mask]] {
+		dst = append(dst, base+int(p))
+	}
+	return dst
+}
@@ -0,0 +1,282 @@
+package swar
+
+import (
+	"bytes"
+	"math/bits"
+	"slices"
+	"testing"
+
+	"github.com/dans-stuff/swar/ref"
+)
+
+// TestPrefixXorBits verifies the running XOR against a bit-by-bit loop. Each set bit must
+// toggle every bit above it, which is what turns quote positions into string regions.
+func TestPrefixXorBits(t *testing.T) {
+	run := func(v uint64) {
+		var want, acc uint64
+		for i := 0; i < 64; i++ {
+			acc ^= v >> i & 1
+			want |= acc << i
+		}
+		if got := PrefixXorBits(v); got != want {
+			t.Errorf("PrefixXorBits(0x%016x) = 0x%016x; want 0x%016x", v, got, want)
+		}
+	}
+
+	run(0)
+	run(1)
+	run(1 << 63)
+	run(0b1001_0000_0100_0010)
+	for n := uint64(0); n < 0xFF_FF_FF_FF_FF; n = (n*12 + 13) / 11 {
+		run(n)
+		run(n * 0x9E37_79B9_7F4A_7C15)
+	}
+}
+
+// TestInterleaveBytes checks zipping against a byte loop and that DeinterleaveBytes
+// restores both inputs, so planar and packed layouts convert losslessly.
+func TestInterleaveBytes(t *testing.T) {
+	run := func(a, b uint64) {
+		la, lb := IntToLanes(a), IntToLanes(b)
+		var zipped [16]byte
+		for i := range 8 {
+			zipped[2*i], zipped[2*i+1] = la[i], lb[i]
+		}
+		wantLo, wantHi := LanesToInt([8]byte(zipped[:8])), LanesToInt([8]byte(zipped[8:]))
+		lo, hi := InterleaveBytes(a, b)
+		if lo != wantLo || hi != wantHi {
+			t.Errorf("InterleaveBytes(0x%016x, 0x%016x) = 0x%016x, 0x%016x; want 0x%016x, 0x%016x", a, b, lo, hi, wantLo, wantHi)
+		}
+		if gotA, gotB := DeinterleaveBytes(lo, hi); gotA != a || gotB != b {
+			t.Errorf("DeinterleaveBytes(0x%016x, 0x%016x) = 0x%016x, 0x%016x; want 0x%016x, 0x%016x", lo, hi, gotA, gotB, a, b)
+		}
+	}
+
+	run(0, 0)
+	run(0x0706_0504_0302_0100, 0x1716_1514_1312_1110)
+	for n := uint64(0); n < 0xFF_FF_FF_FF_FF; n = (n*12 + 13) / 11 {
+		run(n, ^n)
+		run(n*0x9E37_79B9_7F4A_7C15, n)
+	}
+}
+
+// TestDupePatterns checks each tiling helper against indexing the pattern directly, at
+// every phase, so repeating-key XOR stays aligned across lanes for any key length.
+func TestDupePatterns(t *testing.T) {
+	if got, want := Dupe2(0x0A0D), LanesToInt([8]byte{'\r', '\n', '\r', '\n', '\r', '\n', '\r', '\n'}); got != want {
+		t.Errorf("Dupe2(0x0A0D) = 0x%016x; want 0x%016x", got, want)
+	}
+	if got, want := Dupe4(0x0403_0201), LanesToInt([8]byte{1, 2, 3, 4, 1, 2, 3, 4}); got != want {
+		t.Errorf("Dupe4(0x04030201) = 0x%016x; want 0x%016x", got, want)
+	}
+	if got := DupePattern(nil); got != 0 {
+		t.Errorf("DupePattern(nil) = 0x%016x; want 0", got)
+	}
+
+	p := []byte("0123456789")
+	for n := 1; n <= len(p); n++ {
+		for offset := 0; offset < 3*n; offset++ {
+			var want [8]byte
+			for i := range want {
+				want[i] = p[(offset+i)%n]
+			}
+			if got := DupePatternAt(p[:n], offset); got != LanesToInt(want) {
+				t.Errorf("DupePatternAt(%q, %d) = 0x%016x; want 0x%016x", p[:n], offset, got, LanesToInt(want))
+			}
+			if 8%n == 0 {
+				if got := RotateLane(DupePattern(p[:n]), offset); got != LanesToInt(want) {
+					t.Errorf("RotateLane(DupePattern(%q), %d) = 0x%016x; want 0x%016x", p[:n], offset, got, LanesToInt(want))
+				}
+			}
+		}
+	}
+}
+
+// TestAppendOnesPositions checks the table-driven positions for every mask against a
+// bit loop, so Compress, Expand and the newline kernels see bits in ascending order.
+func TestAppendOnesPositions(t *testing.T) {
+	for m := range 256 {
+		var want []int
+		for i := range 8 {
+			if m>>i&1 == 1 {
+				want = append(want, 100+i)
+			}
+		}
+		got := AppendOnesPositions([]int{-1}, byte(m), 100)
+		if !slices.Equal(got[1:], want) || got[0] != -1 {
+			t.Errorf("AppendOnesPositions([-1], 0x%02x, 100) = %v; want [-1 %v]", m, got, want)
+		}
+		if int(Lookup.OnesCount[m]) != len(want) {
+			t.Errorf("Lookup.OnesCount[0x%02x] = %d; want %d", m, Lookup.OnesCount[m], len(want))
+		}
+	}
+}
+
+// TestExtractLowBitsMulti checks that the merged forms agree with calling ExtractLowBits
+// on each lane, including lanes with bits other than the low bit set.
+func TestExtractLowBitsMulti(t *testing.T) {
+	var lanes [8]uint64
+	for n := uint64(0); n < 0xFF_FF_FF_FF_FF; n = (n*12 + 13) / 11 {
+		for k := range lanes {
+			lanes[k] = (n + uint64(k)) * 0x9E37_79B9_7F4A_7C15
+		}
+		var want uint64
+		for k, lane := range lanes {
+			want |= uint64(ExtractLowBits(lane&LowBits)) << (8 * k)
+		}
+		if got := ExtractLowBits8(lanes); got != want {
+			t.Errorf("ExtractLowBits8(%x) = 0x%016x; want 0x%016x", lanes, got, want)
+		}
+		if got := ExtractLowBits4([4]uint64(lanes[:4])); got != uint32(want) {
+			t.Errorf("ExtractLowBits4(%x) = 0x%08x; want 0x%08x", lanes[:4], got, uint32(want))
+		}
+	}
+}
+
+// TestSpreadBitsToLanes checks every byte against a per-bit loop and that ExtractLowBits
+// undoes the spread, so bitmaps can round-trip between both representations.
+func TestSpreadBitsToLanes(t *testing.T) {
+	for m := range 256 {
+		var want uint64
+		for i := range 8 {
+			want |= uint64(m>>i&1) << (8 * i)
+		}
+		if got := SpreadBitsToLanes(byte(m)); got != want {
+			t.Errorf("SpreadBitsToLanes(0x%02x) = 0x%016x; want 0x%016x", m, got, want)
+		}
+		if got := SpreadBitsToHighBits(byte(m)); got != want<<7 {
+			t.Errorf("SpreadBitsToHighBits(0x%02x) = 0x%016x; want 0x%016x", m, got, want<<7)
+		}
+		if got := ExtractLowBits(SpreadBitsToLanes(byte(m))); got != byte(m) {
+			t.Errorf("ExtractLowBits(SpreadBitsToLanes(0x%02x)) = 0x%02x; want 0x%02x", m, got, m)
+		}
+	}
+}
+
+// TestExtractBitOrder pins the documented byte-to-bit mapping of each ordering variant so
+// bitmaps exchanged with other tools keep their meaning.
+func TestExtractBitOrder(t *testing.T) {
+	for m := range 256 {
+		lanes := SpreadBitsToLanes(byte(m))
+		noise := lanes | 0x7E7E_7E7E_7E7E_7E7E
+		reversed := bits.Reverse8(byte(m))
+		if got := ExtractLowBitsLSB0(lanes); got != byte(m) {
+			t.Errorf("ExtractLowBitsLSB0(0x%016x) = 0x%02x; want 0x%02x", lanes, got, m)
+		}
+		if got := ExtractLowBitsMSB0(lanes); got != reversed {
+			t.Errorf("ExtractLowBitsMSB0(0x%016x) = 0x%02x; want 0x%02x", lanes, got, reversed)
+		}
+		if got := ExtractHighBits(noise << 7); got != byte(m) {
+			t.Errorf("ExtractHighBits(0x%016x) = 0x%02x; want 0x%02x", noise<<7, got, m)
+		}
+		if got := ExtractHighBitsLSB0(noise << 7); got != byte(m) {
+			t.Errorf("ExtractHighBitsLSB0(0x%016x) = 0x%02x; want 0x%02x", noise<<7, got, m)
+		}
+		if got := ExtractHighBitsMSB0(noise << 7); got != reversed {
+			t.Errorf("ExtractHighBitsMSB0(0x%016x) = 0x%02x; want 0x%02x", noise<<7, got, reversed)
+		}
+	}
+}
+
+// TestTransposeBytes8x8 compares with the scalar transpose on varied matrices and checks
+// that transposing twice restores the original, as row/column round trips rely on.
+func TestTransposeBytes8x8(t *testing.T) {
+	for n := uint64(0); n < 0xFF_FF_FF_FF_FF; n = (n*12 + 13) / 11 {
+		var rows [8]uint64
+		for r := range rows {
+			rows[r] = (n + uint64(r)) * 0x9E37_79B9_7F4A_7C15
+		}
+		got, want := rows, rows
+		TransposeBytes8x8(&got)
+		ref.TransposeBytes8x8(&want)
+		if got != want {
+			t.Errorf("TransposeBytes8x8(%x) = %x; want %x", rows, got, want)
+		}
+		if TransposeBytes8x8(&got); got != rows {
+			t.Errorf("TransposeBytes8x8 twice (%x) = %x; want the input", rows, got)
+		}
+	}
+}
+
+// TestOverlappingTransforms runs the dst/src APIs on slices of one buffer at every relative
+// shift up to two lanes, covering in-place calls and memmove-style partial overlap in both
+// directions, and compares each result with the same call on separate buffers.
+func TestOverlappingTransforms(t *testing.T) {
+	expandMask := []uint64{0x5A5A_5A5A_5A5A_5A5A, ^uint64(0), 0x00FF_F00F_0000_FFFF, 1}
+	var reverseTable [256]byte
+	for c := range reverseTable {
+		reverseTable[c] = bits.Reverse8(byte(c))
+	}
+	var ditherPattern [64]byte
+	for i := range ditherPattern {
+		ditherPattern[i] = byte(i * 5)
+	}
+	cases := []struct {
+		name   string
+		outLen func(n int) int
+		f      func(dst, src []byte) int
+	}{
+		{"ToUpperASCII", same, func(dst, src []byte) int { ToUpperASCII(dst, src); return len(src) }},
+		{"XORMask", same, func(dst, src []byte) int { XORMask(dst, src, [8]byte{1, 2, 3, 4, 5, 6, 7, 8}); return len(src) }},
+		{"ReplaceByte", same, func(dst, src []byte) int { ReplaceByte(dst, src, 'a', 'z'); return len(src) }},
+		{"TranslateBytes", same, func(dst, src []byte) int { TranslateBytes(dst, src, &reverseTable); return len(src) }},
+		{"AddDither", same, func(dst, src []byte) int { AddDither(dst, src, 13, &ditherPattern); return len(src) }},
+		{"TitleCaseASCII", same, func(dst, src []byte) int { TitleCaseASCII(dst, src); return len(src) }},
+		{"MedianFilter3", same, func(dst, src []byte) int { MedianFilter3(dst, src); return len(src) }},
+		{"RemoveByte", same, func(dst, src []byte) int { return RemoveByte(dst, src, ' ') }},
+		{"NormalizeAllNewlines", same, NormalizeAllNewlines},
+		{"Expand", same, func(dst, src []byte) int { return Expand(dst, src, expandMask) }},
+		{"MixAudio8", func(n int) int { return n - n/3 }, func(dst, src []byte) int { return MixAudio8(dst, src, src[len(src)/3:]) }},
+		{"Blend", func(n int) int { return n - n/3 }, func(dst, src []byte) int { Blend(dst, src, src[len(src)/3:], 90); return len(src) - len(src)/3 }},
+		{"EncodeHex", func(n int) int { return 2 * n }, EncodeHex},
+		{"DecodeHex", func(n int) int { return n / 2 }, func(dst, src []byte) int { n, _ := DecodeHex(dst, src); return n }},
+		{"RGBAToGray", func(n int) int { return n / 4 }, func(dst, src []byte) int { RGBAToGray(dst, src); return len(src) / 4 }},
+		{"ExpandRGB565", func(n int) int { return n / 2 * 3 }, func(dst, src []byte) int { ExpandRGB565(dst, src); return len(src) / 2 * 3 }},
+		{"PackRGB888To565", func(n int) int { return n / 3 * 2 }, func(dst, src []byte) int { PackRGB888To565(dst, src); return len(src) / 3 * 2 }},
+		{"DownsampleByTwo", func(n int) int { return n / 2 }, func(dst, src []byte) int { DownsampleByTwo(dst, src); return len(src) / 2 }},
+		{"UpsampleByTwo", func(n int) int { return 2 * n }, func(dst, src []byte) int { UpsampleByTwo(dst, src); return 2 * len(src) }},
+		{"MovingAverage", same, func(dst, src []byte) int { return MovingAverage(dst, src, 5) }},
+		{"MovingAverage wide", same, func(dst, src []byte) int { return MovingAverage(dst, src, maxLaneWindow+1) }},
+		{"RLEEncode", func(n int) int { return 2 * n }, func(dst, src []byte) int { n, _ := RLEEncode(dst, src); return n }},
+		{"RLEDecode", func(n int) int { return 4 * n }, func(dst, src []byte) int { n, _ := RLEDecode(dst, src); return n }},
+	}
+
+	pattern := []byte("0a1B\r\n2c  3D4e\r5f6789 title\x03\x03\x03case\n")
+	for _, c := range cases {
+		for _, n := range []int{0, 5, 37, 200} {
+			for shift := -17; shift <= 17; shift++ {
+				buf := make([]byte, 4*n+64)
+				FillPattern(buf, pattern)
+				srcAt := 32
+				src := buf[srcAt : srcAt+n]
+				want := make([]byte, c.outLen(n))
+				wantN := c.f(want, bytes.Clone(src))
+				dst := buf[srcAt+shift : srcAt+shift+c.outLen(n)]
+				if gotN := c.f(dst, src); gotN != wantN || !bytes.Equal(dst[:gotN], want[:wantN]) {
+					t.Errorf("%s(len %d) with dst shifted %d = %q; want %q", c.name, n, shift, dst[:gotN], want[:wantN])
+				}
+			}
+		}
+	}
+}
+
+// same is the output length of a transform that writes one byte per input byte
+func same(n int) int { return n }
+
+// TestOverlappingBitmaps checks the bitmap combinators when dst overlaps either input at a
+// word offset in either direction.
+func TestOverlappingBitmaps(t *testing.T) {
+	for shift := -3; shift <= 3; shift++ {
+		words := make([]uint64, 32)
+		for i := range words {
+			words[i] = uint64(i+1) * 0x9E37_79B9_7F4A_7C15
+		}
+		a, b := words[8:20], words[12:24]
+		want := make([]uint64, len(a))
+		AndNotBitmaps(want, slices.Clone(a), slices.Clone(b))
+		dst := words[8+shift : 20+shift]
+		if AndNotBitmaps(dst, a, b); !slices.Equal(dst, want) {
+			t.Errorf("AndNotBitmaps with dst shifted %d = %x; want %x", shift, dst, want)
+		}
+	}
+}
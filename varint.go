@@ -0,0 +1,52 @@
+package swar
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// DecodeUvarint64s decodes consecutive LEB128 varints from src into dst
+// Stops when dst is full or src ends or holds a malformed varint; returns values and bytes consumed
+func DecodeUvarint64s(dst []uint64, src []byte) (n, read int) {
+	for n < len(dst) && read < len(src) {
+		v := loadLane(src, read)
+		if read+8 <= len(src) && v&HighBits == 0 && n+8 <= len(dst) {
+			// Eight single-byte varints: widen each byte into its own value
+			for k := range 8 {
+				dst[n+k] = v >> (8 * k) & 0xFF
+			}
+			n += 8
+			read += 8
+			continue
+		}
+		ends := ^v & HighBits
+		if read+8 > len(src) {
+			ends &= uint64(1)<<(8*(len(src)-read)) - 1
+		}
+		if ends == 0 {
+			// Terminator is beyond this lane: only 9 and 10 byte varints get here
+			x, size := binary.Uvarint(src[read:])
+			if size <= 0 {
+				return n, read
+			}
+			dst[n] = x
+			n++
+			read += size
+			continue
+		}
+		size := bits.TrailingZeros64(ends)>>3 + 1
+		dst[n] = compactVarint(v & (^uint64(0) >> (64 - 8*size)))
+		n++
+		read += size
+	}
+	return n, read
+}
+
+// compactVarint gathers the low 7 bits of each byte of v into a contiguous value
+// Three halving steps pack 8 groups into 56 bits without looping over bytes
+func compactVarint(v uint64) uint64 {
+	v &^= HighBits
+	v = v&0x007F_007F_007F_007F | v&0x7F00_7F00_7F00_7F00>>1
+	v = v&0x0000_3FFF_0000_3FFF | v&0x3FFF_0000_3FFF_0000>>2
+	return v&0x0000_0000_0FFF_FFFF | v&0x0FFF_FFFF_0000_0000>>4
+}
@@ -0,0 +1,57 @@
+package swar
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// TestDecodeUvarint64s encodes value streams with encoding/binary and decodes them in
+// batches of every size. Streams mix one-byte runs for the fast path with 9 and 10 byte
+// varints that cross a whole lane, and truncated input must stop at the last full value.
+func TestDecodeUvarint64s(t *testing.T) {
+	run := func(values []uint64) {
+		var src []byte
+		for _, x := range values {
+			src = binary.AppendUvarint(src, x)
+		}
+		for batch := 1; batch <= len(values)+1; batch++ {
+			var got []uint64
+			dst := make([]uint64, batch)
+			rest := src
+			for len(rest) > 0 {
+				n, read := DecodeUvarint64s(dst, rest)
+				if n == 0 {
+					t.Errorf("DecodeUvarint64s(%x) made no progress", rest)
+					return
+				}
+				got = append(got, dst[:n]...)
+				rest = rest[read:]
+			}
+			for i := range values {
+				if i >= len(got) || got[i] != values[i] {
+					t.Errorf("DecodeUvarint64s(batch %d) = %v; want %v", batch, got, values)
+					return
+				}
+			}
+		}
+		if len(src) > 0 {
+			dst := make([]uint64, len(values))
+			wantRead := len(src) - len(binary.AppendUvarint(nil, values[len(values)-1]))
+			n, read := DecodeUvarint64s(dst, src[:len(src)-1])
+			if n != len(values)-1 || read != wantRead {
+				t.Errorf("DecodeUvarint64s(truncated %x) = %d, %d; want %d values", src, n, read, len(values)-1)
+			}
+		}
+	}
+
+	run(nil)
+	run([]uint64{0})
+	run([]uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17})
+	run([]uint64{127, 128, 255, 16383, 16384, 1 << 21, 1 << 28, 1 << 35, 1 << 42, 1 << 49})
+	run([]uint64{1 << 56, 1<<63 + 5, ^uint64(0), 3, 1<<56 - 1})
+	var values []uint64
+	for n := uint64(0); n < 0xFF_FF_FF_FF_FF; n = (n*12 + 13) / 11 {
+		values = append(values, n, n%128, n*0x9E37_79B9_7F4A_7C15)
+	}
+	run(values)
+}